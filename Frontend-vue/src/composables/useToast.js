@@ -38,6 +38,12 @@ export function useToast() {
             const data = err.response.data;
             let errorMessage = data.message || fallbackMessage;
 
+            // 412: otro usuario modificó el registro. details trae la versión actual, no validaciones
+            if (err.response.status === 412) {
+                error(`${errorMessage}. Cierra el formulario y vuelve a abrirlo para editar la versión actual.`, 8000);
+                return;
+            }
+
             if (data.details && typeof data.details === 'object') {
                 const detailsList = Object.values(data.details).join('. ');
                 if (detailsList) {
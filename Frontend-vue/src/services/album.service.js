@@ -1,9 +1,12 @@
+import { apiError, forgetETag, ifMatch, rememberETag } from './api';
+
 const API_URL = 'http://localhost:8080';
 
 export const albumService = {
     async getById(id) {
         const response = await fetch(`${API_URL}/albums/${id}`);
         if (!response.ok) throw new Error('Network response was not ok');
+        rememberETag(`albums/${id}`, response);
         return response.json();
     },
 
@@ -30,21 +33,31 @@ export const albumService = {
         return response.json();
     },
 
+    // Con la versión leída en getById: si cambió entremedio responde 412 y el error trae el mensaje
     async update(id, albumData) {
         const response = await fetch(`${API_URL}/albums/${id}`, {
             method: 'PUT',
-            headers: { 'Content-Type': 'application/json' },
+            headers: { 'Content-Type': 'application/json', ...ifMatch(`albums/${id}`) },
             body: JSON.stringify(albumData)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        rememberETag(`albums/${id}`, response);
         return response.json();
     },
 
-    async delete(id) {
-        const response = await fetch(`${API_URL}/albums/${id}`, {
-            method: 'DELETE'
+    // policy: restrict | cascade | detach (el backend usa restrict si se omite).
+    // Con dryRun solo se obtiene el reporte de lo que se eliminaría
+    async delete(id, { policy, dryRun = false } = {}) {
+        const params = new URLSearchParams();
+        if (policy) params.set('policy', policy);
+        if (dryRun) params.set('dry_run', 'true');
+        const query = params.toString();
+        const response = await fetch(`${API_URL}/albums/${id}${query ? `?${query}` : ''}`, {
+            method: 'DELETE',
+            headers: ifMatch(`albums/${id}`)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        if (!dryRun) forgetETag(`albums/${id}`);
         if (response.status === 204) return { data: { message: 'Deleted successfully' } };
         return response.json();
     },
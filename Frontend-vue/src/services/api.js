@@ -0,0 +1,36 @@
+// Helpers compartidos por los servicios
+
+// Versión (ETag) de cada registro leído con getById, por clave 'artists/3'. Se reenvía en If-Match
+// al editar o eliminar: si otro usuario lo modificó entremedio el backend responde 412 en vez de
+// sobrescribir sus cambios
+const etags = new Map();
+
+export function rememberETag(key, response) {
+    const etag = response.headers.get('ETag');
+    if (etag) etags.set(key, etag);
+}
+
+export function forgetETag(key) {
+    etags.delete(key);
+}
+
+// Header If-Match con la versión conocida. Sin versión (el registro no se leyó por ID) va vacío
+// y el backend acepta el cambio sin comparar
+export function ifMatch(key) {
+    const etag = etags.get(key);
+    return etag ? { 'If-Match': etag } : {};
+}
+
+// apiError arma el error con el cuerpo de la respuesta ({ status, message, details }),
+// en la forma que espera useToast().handleApiError
+export async function apiError(response) {
+    const err = new Error(`HTTP ${response.status}`);
+    let data = null;
+    try {
+        data = await response.json();
+    } catch {
+        // Cuerpo vacío o no JSON, se usa el mensaje por defecto
+    }
+    err.response = { status: response.status, data };
+    return err;
+}
@@ -1,9 +1,12 @@
+import { apiError, forgetETag, ifMatch, rememberETag } from './api';
+
 const API_URL = 'http://localhost:8080';
 
 export const artistService = {
     async getById(id) {
         const response = await fetch(`${API_URL}/artists/${id}`);
         if (!response.ok) throw new Error('Network response was not ok');
+        rememberETag(`artists/${id}`, response);
         return response.json();
     },
 
@@ -36,21 +39,31 @@ export const artistService = {
         return response.json();
     },
 
+    // Con la versión leída en getById: si cambió entremedio responde 412 y el error trae el mensaje
     async update(id, artistData) {
         const response = await fetch(`${API_URL}/artists/${id}`, {
             method: 'PUT',
-            headers: { 'Content-Type': 'application/json' },
+            headers: { 'Content-Type': 'application/json', ...ifMatch(`artists/${id}`) },
             body: JSON.stringify(artistData)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        rememberETag(`artists/${id}`, response);
         return response.json();
     },
 
-    async delete(id) {
-        const response = await fetch(`${API_URL}/artists/${id}`, {
-            method: 'DELETE'
+    // policy: restrict | cascade | detach (el backend usa restrict si se omite).
+    // Con dryRun solo se obtiene el reporte de lo que se eliminaría
+    async delete(id, { policy, dryRun = false } = {}) {
+        const params = new URLSearchParams();
+        if (policy) params.set('policy', policy);
+        if (dryRun) params.set('dry_run', 'true');
+        const query = params.toString();
+        const response = await fetch(`${API_URL}/artists/${id}${query ? `?${query}` : ''}`, {
+            method: 'DELETE',
+            headers: ifMatch(`artists/${id}`)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        if (!dryRun) forgetETag(`artists/${id}`);
         /* Some APIs return 204 No Content for DELETE, so handle carefully */
         if (response.status === 204) return { data: { message: 'Deleted successfully' } };
         return response.json();
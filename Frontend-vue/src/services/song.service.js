@@ -1,9 +1,12 @@
+import { apiError, forgetETag, ifMatch, rememberETag } from './api';
+
 const API_URL = 'http://localhost:8080';
 
 export const songService = {
     async getById(id) {
         const response = await fetch(`${API_URL}/songs/${id}`);
         if (!response.ok) throw new Error('Network response was not ok');
+        rememberETag(`songs/${id}`, response);
         return response.json();
     },
 
@@ -36,21 +39,25 @@ export const songService = {
         return response.json();
     },
 
+    // Con la versión leída en getById: si cambió entremedio responde 412 y el error trae el mensaje
     async update(id, songData) {
         const response = await fetch(`${API_URL}/songs/${id}`, {
             method: 'PUT',
-            headers: { 'Content-Type': 'application/json' },
+            headers: { 'Content-Type': 'application/json', ...ifMatch(`songs/${id}`) },
             body: JSON.stringify(songData)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        rememberETag(`songs/${id}`, response);
         return response.json();
     },
 
     async delete(id) {
         const response = await fetch(`${API_URL}/songs/${id}`, {
-            method: 'DELETE'
+            method: 'DELETE',
+            headers: ifMatch(`songs/${id}`)
         });
-        if (!response.ok) throw new Error('Network response was not ok');
+        if (!response.ok) throw await apiError(response);
+        forgetETag(`songs/${id}`);
         if (response.status === 204) return { data: { message: 'Deleted successfully' } };
         return response.json();
     },
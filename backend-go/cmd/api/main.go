@@ -13,8 +13,11 @@ import (
 	"github.com/IsaacEspinoza91/Song-Manager/internal/config"
 	"github.com/IsaacEspinoza91/Song-Manager/internal/database"
 	"github.com/IsaacEspinoza91/Song-Manager/internal/handler"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/jobs"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/openapi"
 	"github.com/IsaacEspinoza91/Song-Manager/internal/repository"
 	"github.com/IsaacEspinoza91/Song-Manager/internal/service"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/storage"
 )
 
 func main() {
@@ -34,21 +37,45 @@ func main() {
 	artistRepo := repository.NewArtistRepository(dbPool)
 	songRepo := repository.NewSongRepository(dbPool)
 	albumRepo := repository.NewAlbumRepository(dbPool)
+	playlistRepo := repository.NewPlaylistRepository(dbPool)
+	duplicateRepo := repository.NewDuplicateRepository(dbPool)
+	searchRepo := repository.NewSearchRepository(dbPool)
+	transactor := repository.NewTransactor(dbPool)
+
+	// Blob store para archivos subidos
+	blobStore, err := storage.NewFileSystem(cfg.StorageDir)
+	if err != nil {
+		log.Fatalf("Error fatal preparando el almacenamiento de archivos: %v", err)
+	}
 
 	// 4. Crear servicios (Inyectar repo)
 	artistService := service.NewArtistService(artistRepo)
 	songService := service.NewSongService(songRepo)
 	albumService := service.NewAlbumService(albumRepo)
+	playlistService := service.NewPlaylistService(playlistRepo)
+	duplicateService := service.NewDuplicateService(duplicateRepo)
+	searchService := service.NewSearchService(searchRepo, cfg.SearchTimeout)
+	importService := service.NewImportService(transactor, artistRepo, albumRepo, songRepo)
+	imageService := service.NewImageService(blobStore, albumService, artistService, cfg.PublicURL+"/media")
+	audioService := service.NewAudioService(blobStore, songRepo)
+	trashService := service.NewTrashService(artistRepo, songRepo, albumRepo, blobStore, imageService)
 
 	// 5. Crar enrutador (Inyectar services). Middleware: Log, CORS, recovery
-	router := handler.NewRouter(artistService, songService, albumService)
+	// Contrato OpenAPI embebido (documentación y validación opcional de peticiones)
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("Error fatal cargando el contrato OpenAPI: %v", err)
+	}
+
+	router := handler.NewRouter(artistService, songService, albumService, playlistService, trashService, duplicateService, importService, imageService, blobStore, audioService, searchService, spec, cfg.ValidateRequests)
 
 	// 6. Config servidor HTTP con Graceful Shutdown
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router, // Envuelto en middleware
 
-		// Buena práctica de seguridad, evitar que clientes lentos saturen la API
+		// Buena práctica de seguridad, evitar que clientes lentos saturen la API.
+		// Las rutas de audio (streaming) reemplazan estos plazos con middleware.Stream
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -59,6 +86,13 @@ func main() {
 	quit := make(chan os.Signal, 1) // Crear canal. Pipe
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
+	// Job de purga de la papelera en 2do plano (se desactiva con retención 0)
+	var purgeJob *jobs.PurgeJob
+	if cfg.TrashRetention > 0 {
+		purgeJob = jobs.NewPurgeJob(trashService, cfg.TrashPurgeInterval, cfg.TrashRetention)
+		purgeJob.Start()
+	}
+
 	// Levantar server con goroutine (2do plano)
 	go func() {
 		log.Printf("Servidor corriendo en el puerto %s...\n", cfg.Port)
@@ -82,5 +116,12 @@ func main() {
 		log.Fatalf("El servidor forzó el apagado debido a un error: %v", err)
 	}
 
+	// Detener jobs después del servidor, antes de cerrar el pool de la DB (defer)
+	if purgeJob != nil {
+		if err := purgeJob.Stop(ctxShutdown); err != nil {
+			log.Printf("El job de purga no terminó a tiempo: %v", err)
+		}
+	}
+
 	log.Println("Servidor apagado correctamente.")
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/config"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/database"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/repository"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/service"
+)
+
+// Respaldo completo del catálogo en NDJSON (una línea JSON por fila, con cabecera de versión).
+// Uso:
+//
+//	go run ./cmd/backup export [-file respaldo.ndjson]   (sin -file escribe en stdout)
+//	go run ./cmd/backup restore -file respaldo.ndjson    (requiere una base sin catálogo)
+//
+// El restore reasigna los IDs y mantiene las relaciones entre artistas, canciones y álbumes
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command := os.Args[1]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	file := fs.String("file", "", "archivo NDJSON del respaldo")
+	fs.Parse(os.Args[2:])
+
+	cfg := config.Load()
+	ctx := context.Background()
+	dbPool, err := database.NewPostgresConnection(ctx, cfg.DBUrl)
+	if err != nil {
+		log.Fatalf("Error fatal conectando a la base de datos: %v", err)
+	}
+	defer dbPool.Close()
+
+	backupService := service.NewBackupService(repository.NewTransactor(dbPool), repository.NewBackupRepository(dbPool))
+
+	switch command {
+	case "export":
+		var out io.Writer = os.Stdout
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				log.Fatalf("Error creando el archivo: %v", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := backupService.Export(ctx, out); err != nil {
+			log.Fatalf("Error exportando el catálogo: %v", err)
+		}
+
+	case "restore":
+		if *file == "" {
+			usage()
+		}
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Error abriendo el archivo: %v", err)
+		}
+		defer f.Close()
+
+		report, err := backupService.Restore(ctx, f)
+		if err != nil {
+			var valErrs domain.ValidationError
+			if errors.As(err, &valErrs) {
+				log.Fatalf("Respaldo inválido: %v", map[string]string(valErrs))
+			}
+			log.Fatalf("Error restaurando el catálogo: %v", err)
+		}
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: backup export [-file respaldo.ndjson] | backup restore -file respaldo.ndjson")
+	os.Exit(2)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/config"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/database"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/repository"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/service"
+)
+
+// Importa un catálogo CSV directo a la base de datos, sin pasar por la API (ni su rate limit).
+// Uso: go run ./cmd/import -file catalogo.csv [-dry-run]
+// Columnas: artist, album, track_number, title, duration (+ opcionales disc_number, album_type,
+// release_date, genre, country). Imprime el reporte en JSON y termina con código 1 si hubo filas fallidas
+func main() {
+	file := flag.String("file", "", "ruta del archivo CSV a importar")
+	dryRun := flag.Bool("dry-run", false, "valida e informa sin guardar cambios")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Error abriendo el archivo: %v", err)
+	}
+	defer f.Close()
+
+	cfg := config.Load()
+	ctx := context.Background()
+	dbPool, err := database.NewPostgresConnection(ctx, cfg.DBUrl)
+	if err != nil {
+		log.Fatalf("Error fatal conectando a la base de datos: %v", err)
+	}
+	defer dbPool.Close()
+
+	importService := service.NewImportService(
+		repository.NewTransactor(dbPool),
+		repository.NewArtistRepository(dbPool),
+		repository.NewAlbumRepository(dbPool),
+		repository.NewSongRepository(dbPool),
+	)
+
+	report, err := importService.ImportCSV(ctx, f, domain.ImportOptions{DryRun: *dryRun})
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			log.Fatalf("Archivo CSV inválido: %v", map[string]string(valErrs))
+		}
+		log.Fatalf("Error importando el catálogo: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+
+	if report.FailedRows > 0 {
+		dbPool.Close()
+		os.Exit(1)
+	}
+}
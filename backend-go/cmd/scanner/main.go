@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/config"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/database"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/repository"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/scanner"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/service"
+)
+
+// Escanea una carpeta de música (MP3 con ID3, FLAC y Ogg Vorbis) y sincroniza el catálogo con
+// las etiquetas de los archivos.
+// Uso: go run ./cmd/scanner -dir ~/Musica [-dry-run]
+// Es idempotente: un nuevo escaneo solo aplica las diferencias. Imprime el reporte en JSON
+// y termina con código 1 si algún archivo o álbum falló
+func main() {
+	dir := flag.String("dir", "", "carpeta a escanear (recursivo)")
+	dryRun := flag.Bool("dry-run", false, "informa los cambios sin guardarlos")
+	flag.Parse()
+
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	tracks, fileErrs, err := scanner.Scan(*dir)
+	if err != nil {
+		log.Fatalf("Error recorriendo la carpeta: %v", err)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+	dbPool, err := database.NewPostgresConnection(ctx, cfg.DBUrl)
+	if err != nil {
+		log.Fatalf("Error fatal conectando a la base de datos: %v", err)
+	}
+	defer dbPool.Close()
+
+	syncer := scanner.NewSyncer(
+		repository.NewTransactor(dbPool),
+		service.NewArtistService(repository.NewArtistRepository(dbPool)),
+		service.NewAlbumService(repository.NewAlbumRepository(dbPool)),
+		service.NewSongService(repository.NewSongRepository(dbPool)),
+	)
+
+	report := syncer.Sync(ctx, tracks, fileErrs, scanner.Options{DryRun: *dryRun})
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+
+	if report.Failed > 0 {
+		dbPool.Close()
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,20 @@ import (
 type AppConfig struct {
 	Port  string
 	DBUrl string
+
+	// Archivos subidos (carátulas, imágenes): directorio del blob store y URL pública de la API
+	StorageDir string
+	PublicURL  string
+
+	// Papelera: cada cuanto corre la purga y cuanto tiempo se conservan los registros eliminados
+	TrashPurgeInterval time.Duration
+	TrashRetention     time.Duration
+
+	// Plazo máximo de la búsqueda unificada; las secciones que no alcanzan vuelven vacías
+	SearchTimeout time.Duration
+
+	// Rechazar con 400 las peticiones que no cumplen el contrato OpenAPI
+	ValidateRequests bool
 }
 
 // Load lee las variables de entorno y construye la configuración
@@ -39,9 +56,35 @@ func Load() *AppConfig {
 	// Construir el Data Source Name
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", dbUser, dbPass, dbHost, dbPort, dbName)
 
+	// Archivos subidos (opcional). PUBLIC_URL es la base con la que se arman las URLs guardadas
+	storageDir := getEnvOrDefault("STORAGE_DIR", "./storage")
+	publicURL := getEnvOrDefault("PUBLIC_URL", "http://localhost:"+port)
+
+	// Papelera (opcional). TRASH_RETENTION_DAYS=0 desactiva el job de purga
+	retentionDays := getEnvIntOrDefault("TRASH_RETENTION_DAYS", 30)
+	purgeHours := getEnvIntOrDefault("TRASH_PURGE_INTERVAL_HOURS", 24)
+	if purgeHours <= 0 {
+		purgeHours = 24
+	}
+
+	// Búsqueda unificada (opcional). El cliente puede pedir un plazo menor, nunca mayor
+	searchTimeoutMs := getEnvIntOrDefault("SEARCH_TIMEOUT_MS", 2000)
+	if searchTimeoutMs <= 0 {
+		searchTimeoutMs = 2000
+	}
+
+	// Validación contra openapi.json (opcional, desactivada por defecto)
+	validateRequests := getEnvBoolOrDefault("VALIDATE_REQUESTS", false)
+
 	return &AppConfig{
-		Port:  port,
-		DBUrl: dsn,
+		Port:               port,
+		DBUrl:              dsn,
+		StorageDir:         storageDir,
+		PublicURL:          strings.TrimSuffix(publicURL, "/"),
+		TrashPurgeInterval: time.Duration(purgeHours) * time.Hour,
+		TrashRetention:     time.Duration(retentionDays) * 24 * time.Hour,
+		SearchTimeout:      time.Duration(searchTimeoutMs) * time.Millisecond,
+		ValidateRequests:   validateRequests,
 	}
 }
 
@@ -53,3 +96,37 @@ func getEnvOrFatal(key string) string {
 	}
 	return val
 }
+
+// getEnvOrDefault lee una variable opcional de texto
+func getEnvOrDefault(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// getEnvIntOrDefault lee una variable entera opcional. Si no existe usa el default, si es inválida mata la aplicación
+func getEnvIntOrDefault(key string, fallback int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		log.Fatalf("Error Crítico: La variable de entorno %s debe ser un entero mayor o igual a 0", key)
+	}
+	return n
+}
+
+// getEnvBoolOrDefault lee una variable booleana opcional (true/false, 1/0). Si es inválida mata la aplicación
+func getEnvBoolOrDefault(key string, fallback bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Fatalf("Error Crítico: La variable de entorno %s debe ser true o false", key)
+	}
+	return b
+}
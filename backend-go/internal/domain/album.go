@@ -19,14 +19,23 @@ type AlbumArtist struct {
 
 // Representa a cancion dentro de album
 type Track struct {
-	TrackNumber int    `json:"track_number"`
-	SongID      int64  `json:"song_id"`
-	Title       string `json:"title"`    // Info extraída de la tabla songs
-	Duration    int    `json:"duration"` // Info extraída de la tabla songs
+	DiscNumber  int     `json:"disc_number"`
+	TrackNumber int     `json:"track_number"`
+	SongID      int64   `json:"song_id"`
+	Title       string  `json:"title"`    // Info extraída de la tabla songs
+	Duration    int     `json:"duration"` // Info extraída de la tabla songs
+	FilePath    *string `json:"file_path,omitempty"`
+	HasAudio    bool    `json:"has_audio"` // Tiene audio adjunto en GET /songs/{id}/audio
 
 	Artists []ArtistWithRole `json:"artists,omitempty"`
 }
 
+// Titulo opcional de un disco dentro del album
+type Disc struct {
+	DiscNumber int    `json:"disc_number"`
+	Title      string `json:"title"`
+}
+
 type Album struct {
 	ID          int64      `json:"id"`
 	Title       string     `json:"title"`
@@ -36,9 +45,11 @@ type Album struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Score       *float64   `json:"score,omitempty"` // Relevancia (0..1) contra el filtro de texto del listado
 
 	Artists []AlbumArtist `json:"artists,omitempty"` // Mapeados para dto respuesta
-	Tracks  []Track       `json:"tracks,omitempty"`
+	Discs   []Disc        `json:"discs,omitempty"`
+	Tracks  []Track       `json:"tracks,omitempty"` // Ordenados por disco y luego por pista
 }
 
 type AlbumArtistInput struct {
@@ -46,34 +57,91 @@ type AlbumArtistInput struct {
 	IsPrimary bool  `json:"is_primary"`
 }
 
+// DiscNumber es opcional, si se omite (0) la pista va al disco 1
 type TrackInput struct {
 	SongID      int64 `json:"song_id"`
+	DiscNumber  int   `json:"disc_number"`
 	TrackNumber int   `json:"track_number"`
 }
 
+// Orden completo del tracklist de un disco, el primer ID queda como track 1
+type TrackOrderInput struct {
+	DiscNumber int     `json:"disc_number"`
+	SongIDs    []int64 `json:"song_ids"`
+}
+
+type DiscInput struct {
+	DiscNumber int    `json:"disc_number"`
+	Title      string `json:"title"`
+}
+
 type AlbumInput struct {
 	Title       string             `json:"title"`
 	ReleaseDate string             `json:"release_date"` // Formato "YYYY-MM-DD"
 	Type        string             `json:"type"`
 	CoverURL    *string            `json:"cover_url"`
 	Artists     []AlbumArtistInput `json:"artists"`
+	Discs       []DiscInput        `json:"discs"`
 	Tracks      []TrackInput       `json:"tracks"`
 }
 
+// Type y Year aceptan varios valores (?type=EP&type=LP), basta con que coincida uno
 type AlbumFilter struct {
 	Title      string
-	Type       string
+	Type       []string
+	Year       []int // Año de lanzamiento
 	ArtistID   int64
 	ArtistName string
+	Released   TimeRange // Fecha de lanzamiento
+	Created    TimeRange // Fecha de creación
+}
+
+// ToInput arma el input equivalente al estado actual, base sobre la que se aplica un PATCH.
+// Discs y Tracks quedan en nil para que el Update no los toque salvo que el patch los incluya
+func (a *Album) ToInput() AlbumInput {
+	input := AlbumInput{
+		Title:       a.Title,
+		ReleaseDate: a.ReleaseDate.Format(time.DateOnly),
+		Type:        a.Type,
+		CoverURL:    a.CoverURL,
+		Artists:     make([]AlbumArtistInput, 0, len(a.Artists)),
+	}
+	for _, artist := range a.Artists {
+		input.Artists = append(input.Artists, AlbumArtistInput{ArtistID: artist.ID, IsPrimary: artist.IsPrimary})
+	}
+	return input
 }
 
 // VALIDACIONES Y LIMPIEZA
 func (input *AlbumFilter) Sanitize() {
 	input.Title = validation.SanitizeString(input.Title)
-	input.Type = validation.SanitizeString(input.Type)
+	input.Type = validation.SanitizeStringList(input.Type)
 	input.ArtistName = validation.SanitizeString(input.ArtistName)
 }
 
+func (input *AlbumFilter) Validate() error {
+	input.Sanitize()
+	errs := make(ValidationError)
+
+	for _, t := range input.Type {
+		if t != "EP" && t != "LP" && t != "Single" {
+			errs["type"] = "el tipo de álbum debe ser EP, LP o Single"
+		}
+	}
+	for _, y := range input.Year {
+		if y < 1 || y > 9999 {
+			errs["year"] = "el año debe estar entre 1 y 9999"
+		}
+	}
+	input.Released.Validate("released_from", "released_to", errs)
+	input.Created.Validate("created_from", "created_to", errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func (input *AlbumInput) Sanitize() {
 	input.Title = validation.SanitizeString(input.Title)
 	input.ReleaseDate = validation.SanitizeString(input.ReleaseDate)
@@ -122,11 +190,40 @@ func (input *AlbumInput) Validate() error {
 		}
 	}
 
+	if len(input.Discs) > 0 {
+		seenDiscs := make(map[int]bool)
+		for i := range input.Discs {
+			d := &input.Discs[i]
+			d.Title = validation.SanitizeString(d.Title)
+			if d.DiscNumber <= 0 {
+				errs["discs"] = "los números de disco deben ser mayores a 0"
+				break
+			}
+			if d.Title == "" {
+				errs["discs"] = fmt.Sprintf("el título del disco %d es obligatorio", d.DiscNumber)
+				break
+			}
+			if seenDiscs[d.DiscNumber] {
+				errs["discs"] = fmt.Sprintf("el disco %d está duplicado", d.DiscNumber)
+				break
+			}
+			seenDiscs[d.DiscNumber] = true
+		}
+	}
+
 	if len(input.Tracks) > 0 {
-		seenTrackNumbers := make(map[int]bool)
-		seenSongIDs := make(map[int64]bool) // Evitar que manden la misma canción 2 veces
+		seenTrackNumbers := make(map[[2]int]bool) // Clave: [disco, pista]
+		seenSongIDs := make(map[int64]bool)       // Evitar que manden la misma canción 2 veces
 
-		for _, t := range input.Tracks {
+		for i := range input.Tracks {
+			t := &input.Tracks[i]
+			if t.DiscNumber == 0 {
+				t.DiscNumber = 1 // Disco por defecto
+			}
+			if t.DiscNumber < 0 {
+				errs["tracks"] = "los números de disco deben ser mayores a 0"
+				break
+			}
 			if t.TrackNumber <= 0 {
 				errs["tracks"] = "los números de pista deben ser mayores a 0"
 				break
@@ -136,12 +233,13 @@ func (input *AlbumInput) Validate() error {
 				break
 			}
 
-			// Buscar num de track duplicados
-			if seenTrackNumbers[t.TrackNumber] {
-				errs["tracks"] = fmt.Sprintf("el número de pista %d está duplicado", t.TrackNumber)
+			// Buscar num de track duplicados dentro del mismo disco
+			key := [2]int{t.DiscNumber, t.TrackNumber}
+			if seenTrackNumbers[key] {
+				errs["tracks"] = fmt.Sprintf("el número de pista %d está duplicado en el disco %d", t.TrackNumber, t.DiscNumber)
 				break
 			}
-			seenTrackNumbers[t.TrackNumber] = true // Add Track al mapa
+			seenTrackNumbers[key] = true // Add Track al mapa
 
 			// Buscar id de song
 			if seenSongIDs[t.SongID] {
@@ -161,9 +259,15 @@ func (input *AlbumInput) Validate() error {
 
 func (input *TrackInput) Validate() error {
 	errs := make(ValidationError)
+	if input.DiscNumber == 0 {
+		input.DiscNumber = 1
+	}
 	if input.SongID <= 0 {
 		errs["song_id"] = "el ID de la canción es obligatorio y debe ser mayor a 0"
 	}
+	if input.DiscNumber < 0 {
+		errs["disc_number"] = "el número de disco debe ser mayor a 0"
+	}
 	if input.TrackNumber <= 0 {
 		errs["track_number"] = "el número de pista debe ser mayor a 0"
 	}
@@ -174,25 +278,72 @@ func (input *TrackInput) Validate() error {
 	return nil
 }
 
+func (input *TrackOrderInput) Validate() error {
+	errs := make(ValidationError)
+	if input.DiscNumber == 0 {
+		input.DiscNumber = 1
+	}
+	if input.DiscNumber < 0 {
+		errs["disc_number"] = "el número de disco debe ser mayor a 0"
+	}
+
+	if len(input.SongIDs) == 0 {
+		errs["song_ids"] = "debe indicar el orden de al menos una canción"
+	} else {
+		seen := make(map[int64]bool)
+		for _, id := range input.SongIDs {
+			if id <= 0 {
+				errs["song_ids"] = "uno de los IDs de canción es inválido"
+				break
+			}
+			if seen[id] {
+				errs["song_ids"] = fmt.Sprintf("la canción con ID %d está duplicada en el orden", id)
+				break
+			}
+			seen[id] = true
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 // INTERFACES
 type AlbumRepository interface {
 	Create(ctx context.Context, input *AlbumInput) (*Album, error)
 	AddTrack(ctx context.Context, albumID int64, input *TrackInput) error
+	InsertTrack(ctx context.Context, albumID int64, input *TrackInput) error
 	RemoveTrack(ctx context.Context, albumID int64, songID int64) error
+	RemoveTrackAndCompact(ctx context.Context, albumID int64, songID int64) error
+	ReorderTracks(ctx context.Context, albumID int64, discNumber int, songIDs []int64) error
 	GetByID(ctx context.Context, id int64) (*Album, error)
+	GetByTitleAndArtist(ctx context.Context, title string, artistID int64) (*Album, error)
 	GetAlbumsByArtistID(ctx context.Context, artistID int64) ([]Album, error)
 	GetAllPaginated(ctx context.Context, filter AlbumFilter, params PaginationParams) (*PaginatedResult[Album], error)
-	Update(ctx context.Context, albumID int64, input *AlbumInput) (*Album, error)
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, albumID int64, input *AlbumInput, version *time.Time) (*Album, error)
+	Delete(ctx context.Context, id int64, opts DeleteOptions, version *time.Time) (*DeleteReport, error)
+
+	// Papelera
+	GetDeletedPaginated(ctx context.Context, params PaginationParams) (*PaginatedResult[Album], error)
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, id int64) (*Purged, error)
+	PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]Purged, error)
 }
 
 type AlbumService interface {
 	Create(ctx context.Context, input *AlbumInput) (*Album, error)
 	GetByID(ctx context.Context, albumID int64) (*Album, error)
+	GetByTitleAndArtist(ctx context.Context, title string, artistID int64) (*Album, error)
 	GetAllPaginated(ctx context.Context, filter AlbumFilter, params PaginationParams) (*PaginatedResult[Album], error)
 	GetAlbumsByArtistID(ctx context.Context, artistID int64) ([]Album, error)
-	Update(ctx context.Context, id int64, input *AlbumInput) (*Album, error)
+	Update(ctx context.Context, id int64, input *AlbumInput, version *time.Time) (*Album, error)
+	Patch(ctx context.Context, id int64, patch MergePatch, version *time.Time) (*Album, error)
 	AddTrack(ctx context.Context, albumID int64, input *TrackInput) error
+	InsertTrack(ctx context.Context, albumID int64, input *TrackInput) error
 	RemoveTrack(ctx context.Context, albumID int64, songID int64) error
-	Delete(ctx context.Context, albumID int64) error
+	RemoveTrackAndCompact(ctx context.Context, albumID int64, songID int64) error
+	ReorderTracks(ctx context.Context, albumID int64, input *TrackOrderInput) error
+	Delete(ctx context.Context, albumID int64, opts DeleteOptions, version *time.Time) (*DeleteReport, error)
 }
@@ -19,6 +19,7 @@ type Artist struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Score     *float64   `json:"score,omitempty"` // Relevancia (0..1) contra el filtro de texto del listado
 
 	Role      string `json:"role,omitempty"`       // Song relationships
 	IsPrimary *bool  `json:"is_primary,omitempty"` // Album relationships
@@ -32,11 +33,27 @@ type ArtistInput struct {
 	ImageURL *string `json:"image_url"`
 }
 
-// Contiene los campos opcionales para buscar artistas
+// Artista duplicado (source) que se fusiona dentro del artista de la URL (target)
+type ArtistMergeInput struct {
+	SourceID int64 `json:"source_id"`
+}
+
+// ArtistMergeReport resume la fusión de dos artistas
+type ArtistMergeReport struct {
+	Artist       *Artist `json:"artist"` // Sobreviviente ya actualizado
+	SourceID     int64   `json:"source_id"`
+	MovedSongs   int64   `json:"moved_songs"`   // Relaciones que pasaron al sobreviviente
+	MergedSongs  int64   `json:"merged_songs"`  // Canciones donde ambos participaban (se conserva el rol más fuerte)
+	MovedAlbums  int64   `json:"moved_albums"`  // Relaciones que pasaron al sobreviviente
+	MergedAlbums int64   `json:"merged_albums"` // Álbumes donde ambos participaban (is_primary se conserva si alguno lo era)
+}
+
+// Contiene los campos opcionales para buscar artistas.
+// Genre y Country aceptan varios valores (?genre=Rock&genre=Pop), basta con que coincida uno
 type ArtistFilter struct {
 	Name    string
-	Genre   string
-	Country string
+	Genre   []string
+	Country []string
 }
 
 type ArtistSeachResult struct {
@@ -44,11 +61,22 @@ type ArtistSeachResult struct {
 	ArtistName string `json:"artist_name"`
 }
 
+// ToInput arma el input equivalente al estado actual, base sobre la que se aplica un PATCH
+func (a *Artist) ToInput() ArtistInput {
+	return ArtistInput{
+		Name:     a.Name,
+		Genre:    a.Genre,
+		Country:  a.Country,
+		Bio:      a.Bio,
+		ImageURL: a.ImageURL,
+	}
+}
+
 // VALIDACIONES Y LIMPIEZA
 func (input *ArtistFilter) Sanitize() {
 	input.Name = validation.SanitizeString(input.Name)
-	input.Genre = validation.SanitizeString(input.Genre)
-	input.Country = validation.SanitizeString(input.Country)
+	input.Genre = validation.SanitizeStringList(input.Genre)
+	input.Country = validation.SanitizeStringList(input.Country)
 }
 
 func (input *ArtistInput) Sanitize() {
@@ -92,24 +120,50 @@ func (input *ArtistInput) Validate() error {
 	return nil
 }
 
+func (input *ArtistMergeInput) Validate(targetID int64) error {
+	errs := make(ValidationError)
+	if input.SourceID <= 0 {
+		errs["source_id"] = "el ID del artista a fusionar es obligatorio y debe ser mayor a 0"
+	} else if input.SourceID == targetID {
+		errs["source_id"] = "no se puede fusionar un artista consigo mismo"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 // INTERFACES
 
 type ArtistRepository interface {
 	Create(ctx context.Context, input *ArtistInput) (*Artist, error)
-	Update(ctx context.Context, id int64, input *ArtistInput) (*Artist, error)
+	Update(ctx context.Context, id int64, input *ArtistInput, version *time.Time) (*Artist, error)
 	GetAll(ctx context.Context) ([]Artist, error)
 	GetAllPaginated(ctx context.Context, filter ArtistFilter, params PaginationParams) (*PaginatedResult[Artist], error)
 	GetByID(ctx context.Context, id int64) (*Artist, error)
-	Delete(ctx context.Context, id int64) error
+	GetByName(ctx context.Context, name string) (*Artist, error)
+	Delete(ctx context.Context, id int64, opts DeleteOptions, version *time.Time) (*DeleteReport, error)
 	SearchArtists(ctx context.Context, searchTerm string) ([]ArtistSeachResult, error)
+	Merge(ctx context.Context, targetID, sourceID int64) (*ArtistMergeReport, error)
+	ResolveRedirect(ctx context.Context, id int64) (int64, error)
+
+	// Papelera
+	GetDeletedPaginated(ctx context.Context, params PaginationParams) (*PaginatedResult[Artist], error)
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, id int64) (*Purged, error)
+	PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]Purged, error)
 }
 
 type ArtistService interface {
 	Create(ctx context.Context, input *ArtistInput) (*Artist, error)
-	Update(ctx context.Context, id int64, input *ArtistInput) (*Artist, error)
+	Update(ctx context.Context, id int64, input *ArtistInput, version *time.Time) (*Artist, error)
+	Patch(ctx context.Context, id int64, patch MergePatch, version *time.Time) (*Artist, error)
 	GetAll(ctx context.Context) ([]Artist, error)
 	GetAllPaginated(ctx context.Context, filter ArtistFilter, params PaginationParams) (*PaginatedResult[Artist], error)
 	GetByID(ctx context.Context, id int64) (*Artist, error)
-	Delete(ctx context.Context, id int64) error
+	GetByName(ctx context.Context, name string) (*Artist, error)
+	Delete(ctx context.Context, id int64, opts DeleteOptions, version *time.Time) (*DeleteReport, error)
 	SearchArtists(ctx context.Context, searchTerm string) ([]ArtistSeachResult, error)
+	Merge(ctx context.Context, targetID int64, input *ArtistMergeInput) (*ArtistMergeReport, error)
 }
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// MODELOS
+
+const (
+	MaxAudioSize           = 200 << 20 // 200 MB
+	AudioDurationTolerance = 2         // Segundos de diferencia aceptados al comparar con el archivo
+)
+
+// SongAudio es el archivo adjunto a una canción
+type SongAudio struct {
+	Key         string `json:"-"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// AudioDurationMode define qué hacer con la duración leída del archivo (?duration=update|check)
+type AudioDurationMode string
+
+const (
+	AudioDurationUpdate AudioDurationMode = "update" // La duración del archivo reemplaza a la guardada (default)
+	AudioDurationCheck  AudioDurationMode = "check"  // Rechaza el archivo si no coincide con la guardada
+)
+
+type AudioAttachOptions struct {
+	DurationMode AudioDurationMode
+}
+
+type AudioAttachResult struct {
+	SongID           int64  `json:"song_id"`
+	ContentType      string `json:"content_type"`
+	Size             int64  `json:"size"`
+	FileDuration     int    `json:"file_duration"` // 0 si no se pudo calcular
+	PreviousDuration int    `json:"previous_duration"`
+	Duration         int    `json:"duration"` // Duración final de la canción
+	DurationUpdated  bool   `json:"duration_updated"`
+}
+
+// VALIDACIONES
+func (o *AudioAttachOptions) Validate() error {
+	if o.DurationMode == "" {
+		o.DurationMode = AudioDurationUpdate
+	}
+	if o.DurationMode != AudioDurationUpdate && o.DurationMode != AudioDurationCheck {
+		return ValidationError{"duration": "el modo de duración debe ser update o check"}
+	}
+	return nil
+}
+
+// INTERFACES
+type AudioService interface {
+	Attach(ctx context.Context, songID int64, r io.Reader, opts AudioAttachOptions) (*AudioAttachResult, error)
+	Open(ctx context.Context, songID int64) (io.ReadSeekCloser, *BlobInfo, *SongAudio, error)
+	Detach(ctx context.Context, songID int64) error
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// MODELOS
+
+// BackupSchemaVersion se incrementa cada vez que cambia el formato de algún registro del respaldo.
+// El restore acepta desde BackupMinSchemaVersion mientras los cambios sean solo campos nuevos opcionales
+//   - 2: file_path en canciones
+//   - 3: playlists con sus entradas y redirecciones de artistas fusionados
+//   - 4: audio adjunto en canciones (clave del blob, tipo y tamaño)
+const (
+	BackupSchemaVersion    = 4
+	BackupMinSchemaVersion = 1
+)
+
+// Tipos de línea del respaldo NDJSON, en el orden en que se exportan (padres antes que hijos)
+const (
+	BackupTypeHeader         = "header"
+	BackupTypeArtist         = "artist"
+	BackupTypeSong           = "song"
+	BackupTypeAlbum          = "album"
+	BackupTypeAlbumDisc      = "album_disc"
+	BackupTypeSongArtist     = "song_artist"
+	BackupTypeAlbumArtist    = "album_artist"
+	BackupTypeTrack          = "track"
+	BackupTypePlaylist       = "playlist"
+	BackupTypePlaylistSong   = "playlist_song"
+	BackupTypeArtistRedirect = "artist_redirect"
+)
+
+// BackupLine es cada línea del archivo: {"type":"artist","data":{...}}
+type BackupLine struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BackupHeader es siempre la primera línea
+type BackupHeader struct {
+	SchemaVersion int       `json:"schema_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+}
+
+// Registros tal como están en las tablas, incluidos los que están en la papelera.
+// Los IDs son los de origen; el restore los reasigna
+type BackupArtist struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Genre     string     `json:"genre"`
+	Country   string     `json:"country"`
+	Bio       *string    `json:"bio"`
+	ImageURL  *string    `json:"image_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type BackupSong struct {
+	ID               int64      `json:"id"`
+	Title            string     `json:"title"`
+	Duration         int        `json:"duration"`
+	FilePath         *string    `json:"file_path"`
+	AudioKey         *string    `json:"audio_key"` // Clave en el blob store; el archivo no va en el respaldo
+	AudioContentType *string    `json:"audio_content_type"`
+	AudioSize        *int64     `json:"audio_size"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+}
+
+type BackupAlbum struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	ReleaseDate string     `json:"release_date"` // Formato "YYYY-MM-DD"
+	Type        string     `json:"type"`
+	CoverURL    *string    `json:"cover_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type BackupAlbumDisc struct {
+	AlbumID    int64  `json:"album_id"`
+	DiscNumber int    `json:"disc_number"`
+	Title      string `json:"title"`
+}
+
+type BackupSongArtist struct {
+	SongID   int64  `json:"song_id"`
+	ArtistID int64  `json:"artist_id"`
+	Role     string `json:"role"`
+}
+
+type BackupAlbumArtist struct {
+	AlbumID   int64 `json:"album_id"`
+	ArtistID  int64 `json:"artist_id"`
+	IsPrimary bool  `json:"is_primary"`
+}
+
+type BackupTrack struct {
+	AlbumID     int64 `json:"album_id"`
+	SongID      int64 `json:"song_id"`
+	DiscNumber  int   `json:"disc_number"`
+	TrackNumber int   `json:"track_number"`
+}
+
+type BackupPlaylist struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type BackupPlaylistSong struct {
+	PlaylistID int64     `json:"playlist_id"`
+	SongID     int64     `json:"song_id"`
+	Position   int       `json:"position"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// OldID es el artista absorbido en una fusión, puede no estar en el respaldo si ya se purgó
+type BackupArtistRedirect struct {
+	OldID     int64     `json:"old_id"`
+	NewID     int64     `json:"new_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreReport cuenta los registros insertados por tipo. Skipped cuenta los que no tienen
+// equivalente en la base restaurada (redirecciones de artistas que ya no están en el respaldo)
+type RestoreReport struct {
+	SchemaVersion int            `json:"schema_version"`
+	Records       map[string]int `json:"records"`
+	Skipped       map[string]int `json:"skipped,omitempty"`
+}
+
+// INTERFACES
+type BackupRepository interface {
+	// Export recorre las tablas con cursores y llama a emit por cada fila, sin acumularlas.
+	// Todo se lee dentro de una misma transacción para que el respaldo sea consistente
+	Export(ctx context.Context, emit func(recordType string, record any) error) error
+	IsCatalogEmpty(ctx context.Context) (bool, error)
+
+	// Inserciones del restore, retornan el ID nuevo
+	InsertArtist(ctx context.Context, a *BackupArtist) (int64, error)
+	InsertSong(ctx context.Context, s *BackupSong) (int64, error)
+	InsertAlbum(ctx context.Context, a *BackupAlbum) (int64, error)
+	InsertAlbumDisc(ctx context.Context, d *BackupAlbumDisc) error
+	InsertSongArtist(ctx context.Context, sa *BackupSongArtist) error
+	InsertAlbumArtist(ctx context.Context, aa *BackupAlbumArtist) error
+	InsertTrack(ctx context.Context, t *BackupTrack) error
+	InsertPlaylist(ctx context.Context, p *BackupPlaylist) (int64, error)
+	InsertPlaylistSong(ctx context.Context, ps *BackupPlaylistSong) error
+	InsertArtistRedirect(ctx context.Context, ar *BackupArtistRedirect) error
+}
+
+type BackupService interface {
+	Export(ctx context.Context, w io.Writer) error
+	Restore(ctx context.Context, r io.Reader) (*RestoreReport, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+// MODELOS
+
+// DeletePolicy define qué pasa con las relaciones al hacer soft delete de un artista o álbum
+type DeletePolicy string
+
+const (
+	// Rechaza el borrado (409) si existen canciones/álbumes vigentes asociados
+	DeleteRestrict DeletePolicy = "restrict"
+	// Aplica soft delete a lo que pertenece solo a esta entidad y desvincula lo compartido
+	DeleteCascade DeletePolicy = "cascade"
+	// Elimina las relaciones y deja intactas las canciones/álbumes
+	DeleteDetach DeletePolicy = "detach"
+)
+
+type DeleteOptions struct {
+	Policy DeletePolicy
+	DryRun bool // Solo calcula el reporte, no modifica nada
+}
+
+// DeleteReport informa qué registros fueron (o serían, en dry run) afectados por el borrado
+type DeleteReport struct {
+	Policy  DeletePolicy `json:"policy"`
+	DryRun  bool         `json:"dry_run"`
+	Allowed bool         `json:"allowed"` // false si la política restrict encontró referencias
+
+	DeletedSongs   []int64 `json:"deleted_songs"`
+	DeletedAlbums  []int64 `json:"deleted_albums"`
+	DetachedSongs  []int64 `json:"detached_songs"`
+	DetachedAlbums []int64 `json:"detached_albums"`
+	BlockingSongs  []int64 `json:"blocking_songs"`
+	BlockingAlbums []int64 `json:"blocking_albums"`
+}
+
+func NewDeleteReport(opts DeleteOptions) *DeleteReport {
+	return &DeleteReport{
+		Policy:         opts.Policy,
+		DryRun:         opts.DryRun,
+		Allowed:        true,
+		DeletedSongs:   []int64{},
+		DeletedAlbums:  []int64{},
+		DetachedSongs:  []int64{},
+		DetachedAlbums: []int64{},
+		BlockingSongs:  []int64{},
+		BlockingAlbums: []int64{},
+	}
+}
+
+// VALIDACIONES
+func (o *DeleteOptions) Validate() error {
+	if o.Policy == "" {
+		o.Policy = DeleteRestrict // Por defecto no se borra nada que tenga referencias
+	}
+	if o.Policy != DeleteRestrict && o.Policy != DeleteCascade && o.Policy != DeleteDetach {
+		return ValidationError{"policy": "la política de borrado debe ser restrict, cascade o detach"}
+	}
+	return nil
+}
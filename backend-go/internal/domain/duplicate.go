@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// MODELOS
+
+// DuplicatePair relaciona dos registros que probablemente son el mismo
+type DuplicatePair struct {
+	AID   int64   `json:"a_id"`
+	BID   int64   `json:"b_id"`
+	Score float64 `json:"score"` // Puntaje combinado (0..1)
+
+	// Componentes del puntaje. En artistas solo aplica NameScore
+	NameScore     float64 `json:"name_score"`               // similarity() de título o nombre
+	DurationScore float64 `json:"duration_score,omitempty"` // 1 = misma duración, 0 = fuera de tolerancia
+	ArtistScore   float64 `json:"artist_score,omitempty"`   // Artistas en común / artistas totales
+}
+
+// DuplicateGroup agrupa registros conectados por uno o más pares candidatos
+type DuplicateGroup[T any] struct {
+	Score float64         `json:"score"` // Mejor puntaje dentro del grupo
+	Items []T             `json:"items"`
+	Pairs []DuplicatePair `json:"pairs"`
+}
+
+// DuplicateOptions viene desde la URL (?threshold=0.5&limit=200&duration_tolerance=10)
+type DuplicateOptions struct {
+	Threshold         *float64 // Similitud mínima de título/nombre para ser candidato; nil usa el default
+	Limit             int      // Máximo de pares evaluados
+	DurationTolerance int      // Segundos de diferencia aceptados en canciones
+}
+
+// Pesos del puntaje combinado de canciones
+const (
+	SongTitleWeight    = 0.6
+	SongDurationWeight = 0.2
+	SongArtistWeight   = 0.2
+)
+
+// VALIDACIONES
+func (o *DuplicateOptions) Validate() error {
+	errs := make(ValidationError)
+
+	if o.Threshold == nil {
+		threshold := 0.5
+		o.Threshold = &threshold
+	}
+	if o.Limit <= 0 {
+		o.Limit = 500
+	}
+	if o.DurationTolerance <= 0 {
+		o.DurationTolerance = 10
+	}
+
+	// Mismos límites que en los listados, escrito así para rechazar también NaN
+	if !(*o.Threshold >= MinMatchThreshold && *o.Threshold <= MaxMatchThreshold) {
+		errs["threshold"] = fmt.Sprintf("el umbral de similitud debe estar entre %g y %g", MinMatchThreshold, MaxMatchThreshold)
+	}
+	if o.Limit > 5000 {
+		errs["limit"] = "el límite de pares no puede superar 5000"
+	}
+	if o.DurationTolerance > 600 {
+		errs["duration_tolerance"] = "la tolerancia de duración no puede superar 600 segundos"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// INTERFACES
+type DuplicateRepository interface {
+	FindSongPairs(ctx context.Context, opts DuplicateOptions) ([]DuplicatePair, error)
+	FindArtistPairs(ctx context.Context, opts DuplicateOptions) ([]DuplicatePair, error)
+	GetSongsByIDs(ctx context.Context, ids []int64) ([]Song, error)
+	GetArtistsByIDs(ctx context.Context, ids []int64) ([]Artist, error)
+}
+
+type DuplicateService interface {
+	FindDuplicateSongs(ctx context.Context, opts DuplicateOptions) ([]DuplicateGroup[Song], error)
+	FindDuplicateArtists(ctx context.Context, opts DuplicateOptions) ([]DuplicateGroup[Artist], error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDuplicateOptionsValidateThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold *float64
+		want      float64
+		wantErr   bool
+	}{
+		{name: "sin umbral usa el default", threshold: nil, want: 0.5},
+		{name: "mínimo", threshold: floatPtr(MinMatchThreshold), want: MinMatchThreshold},
+		{name: "máximo", threshold: floatPtr(MaxMatchThreshold), want: MaxMatchThreshold},
+		{name: "cero no es el default", threshold: floatPtr(0), wantErr: true},
+		{name: "bajo el mínimo", threshold: floatPtr(0.05), wantErr: true},
+		{name: "sobre el máximo", threshold: floatPtr(1.5), wantErr: true},
+		{name: "NaN", threshold: floatPtr(math.NaN()), wantErr: true},
+		{name: "infinito", threshold: floatPtr(math.Inf(1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DuplicateOptions{Threshold: tt.threshold}
+			err := opts.Validate()
+			if errs, _ := err.(ValidationError); (errs["threshold"] != "") != tt.wantErr {
+				t.Fatalf("Validate() = %v, se esperaba error de umbral: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *opts.Threshold != tt.want {
+				t.Errorf("Threshold = %v, se esperaba %v", *opts.Threshold, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import "errors"
 // Errores comunes y transversales
 var (
 	ErrInvalidID = errors.New("el ID proporcionado es inválido")
+
+	// La versión enviada en If-Match ya no es la actual (otro usuario modificó el registro)
+	ErrPreconditionFailed = errors.New("el registro fue modificado por otro usuario, recargue los datos antes de guardar")
 )
 
 // Errores de Artistas
@@ -12,6 +15,7 @@ var (
 	ErrArtistNotFound  = errors.New("artista no encontrado")
 	ErrArtistIDInvalid = errors.New("ID de artista inválido")
 	ErrArtistNotInDB   = errors.New("el artista indicado no existe en la base de datos")
+	ErrArtistInUse     = errors.New("el artista tiene canciones o álbumes asociados")
 )
 
 // Errores de Canciones
@@ -26,7 +30,38 @@ var (
 	ErrAlbumNotFound      = errors.New("álbum no encontrado")
 	ErrAlbumIDInvalid     = errors.New("ID de álbum inválido")
 	ErrTrackNotFound      = errors.New("track no encontrado en este álbum")
-	ErrTrackAlreadyExists = errors.New("este número de pista ya está ocupado en el álbum")
+	ErrTrackAlreadyExists = errors.New("este número de pista ya está ocupado en el disco del álbum")
 	ErrSongAlreadyInAlbum = errors.New("esta canción ya existe en este álbum")
 	ErrSongNotInDB        = errors.New("la canción indicada no existe en la base de datos")
+	ErrAlbumInUse         = errors.New("el álbum tiene canciones asociadas")
+	ErrTrackOrderMismatch = errors.New("el orden indicado debe incluir exactamente las canciones actuales del disco")
+)
+
+// Errores de Playlists
+var (
+	ErrPlaylistNotFound       = errors.New("playlist no encontrada")
+	ErrPlaylistIDInvalid      = errors.New("ID de playlist inválido")
+	ErrPlaylistEntryNotFound  = errors.New("la entrada no existe en esta playlist")
+	ErrPlaylistEntryIDInvalid = errors.New("ID de entrada de playlist inválido")
+)
+
+// Errores de Respaldos
+var (
+	ErrBackupTargetNotEmpty = errors.New("la base de datos de destino ya tiene catálogo, el restore requiere una base vacía")
+)
+
+// Errores de Archivos (imágenes y audio)
+var (
+	ErrBlobNotFound     = errors.New("archivo no encontrado")
+	ErrBlobKeyInvalid   = errors.New("ruta de archivo inválida")
+	ErrImageTooLarge    = errors.New("la imagen supera el tamaño máximo permitido")
+	ErrImageUnsupported = errors.New("formato de imagen no soportado, use JPEG o PNG")
+	ErrImageDimensions  = errors.New("las dimensiones de la imagen superan el máximo permitido")
+)
+
+// Errores de Audio
+var (
+	ErrSongAudioNotFound = errors.New("la canción no tiene audio adjunto")
+	ErrAudioTooLarge     = errors.New("el archivo de audio supera el tamaño máximo permitido")
+	ErrAudioUnsupported  = errors.New("formato de audio no soportado, use MP3, FLAC u Ogg Vorbis")
 )
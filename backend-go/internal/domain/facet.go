@@ -0,0 +1,15 @@
+package domain
+
+// FacetValue es una opción de filtro y cuántos resultados devolvería
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets agrupa los conteos por campo (ej. "genre", "type", "year").
+// Cada faceta se calcula con todos los filtros aplicados salvo el suyo, así el cliente ve cuántos
+// resultados daría cada opción aunque ya tenga otra marcada del mismo campo
+type Facets map[string][]FacetValue
+
+// MaxFacetValues limita las opciones por faceta, se entregan las de mayor conteo
+const MaxFacetValues = 50
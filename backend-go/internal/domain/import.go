@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+)
+
+// MODELOS
+
+// ImportRow es una fila del CSV de catálogo. Columnas obligatorias: artist, album, track_number,
+// title, duration. Opcionales: disc_number, album_type, release_date, genre, country
+type ImportRow struct {
+	Line        int    `json:"line"` // Línea en el archivo (la cabecera es la 1)
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	DiscNumber  int    `json:"disc_number"`
+	TrackNumber int    `json:"track_number"`
+	Title       string `json:"title"`
+	Duration    int    `json:"duration"` // En segundos
+	AlbumType   string `json:"album_type,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+type ImportOptions struct {
+	DryRun bool // Ejecuta todo dentro de la transacción y hace rollback al final
+}
+
+type ImportRowError struct {
+	Line   int             `json:"line"`
+	Errors ValidationError `json:"errors"`
+}
+
+// ImportAlbumResult resume lo ocurrido con un álbum (cada álbum es una transacción)
+type ImportAlbumResult struct {
+	Artist        string `json:"artist"`
+	Album         string `json:"album"`
+	AlbumID       int64  `json:"album_id,omitempty"` // En dry run puede ser un ID que no llega a existir
+	Status        string `json:"status"`             // imported | failed
+	Rows          int    `json:"rows"`
+	CreatedArtist bool   `json:"created_artist"`
+	CreatedAlbum  bool   `json:"created_album"`
+	CreatedSongs  int    `json:"created_songs"`
+	SkippedRows   int    `json:"skipped_rows"` // Pistas que ya existían con el mismo título
+	Error         string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun       bool                `json:"dry_run"`
+	TotalRows    int                 `json:"total_rows"`
+	ImportedRows int                 `json:"imported_rows"`
+	SkippedRows  int                 `json:"skipped_rows"`
+	FailedRows   int                 `json:"failed_rows"`
+	Albums       []ImportAlbumResult `json:"albums"`
+	Errors       []ImportRowError    `json:"errors"`
+}
+
+const (
+	ImportStatusImported = "imported"
+	ImportStatusFailed   = "failed"
+
+	// Valores usados al crear artistas o álbumes cuando el CSV no trae la columna
+	ImportDefaultGenre     = "Desconocido"
+	ImportDefaultCountry   = "Desconocido"
+	ImportDefaultAlbumType = "LP"
+)
+
+// VALIDACIONES
+func (row *ImportRow) Sanitize() {
+	row.Artist = validation.SanitizeString(row.Artist)
+	row.Album = validation.SanitizeString(row.Album)
+	row.Title = validation.SanitizeString(row.Title)
+	row.AlbumType = validation.SanitizeString(row.AlbumType)
+	row.ReleaseDate = validation.SanitizeString(row.ReleaseDate)
+	row.Genre = validation.SanitizeString(row.Genre)
+	row.Country = validation.SanitizeString(row.Country)
+}
+
+func (row *ImportRow) Validate() error {
+	row.Sanitize()
+	errs := make(ValidationError)
+
+	if row.DiscNumber == 0 {
+		row.DiscNumber = 1
+	}
+	if row.Artist == "" {
+		errs["artist"] = "el artista es obligatorio"
+	}
+	if row.Album == "" {
+		errs["album"] = "el álbum es obligatorio"
+	}
+	if row.Title == "" {
+		errs["title"] = "el título es obligatorio"
+	}
+	if row.TrackNumber <= 0 {
+		errs["track_number"] = "el número de pista debe ser mayor a 0"
+	}
+	if row.DiscNumber < 0 {
+		errs["disc_number"] = "el número de disco debe ser mayor a 0"
+	}
+	if row.Duration <= 0 {
+		errs["duration"] = "la duración debe ser mayor a 0 segundos"
+	}
+	if row.AlbumType != "" && row.AlbumType != "EP" && row.AlbumType != "LP" && row.AlbumType != "Single" {
+		errs["album_type"] = "el tipo de álbum debe ser EP, LP o Single"
+	}
+	if row.ReleaseDate != "" {
+		if _, err := time.Parse(time.DateOnly, row.ReleaseDate); err != nil {
+			errs["release_date"] = "el formato de la fecha debe ser YYYY-MM-DD"
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// INTERFACES
+type ImportService interface {
+	ImportCSV(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// MODELOS
+
+// BlobInfo son los metadatos de un archivo guardado
+type BlobInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Límites de las imágenes subidas
+const (
+	MaxImageSize      = 5 << 20 // 5 MB
+	MaxImageDimension = 6000    // Ancho o alto máximo en pixeles, evita bombas de descompresión
+)
+
+// ThumbnailSize es un tamaño generado al subir una imagen, la imagen se ajusta dentro del cuadrado
+type ThumbnailSize struct {
+	Name string
+	Size int // Lado máximo en pixeles
+}
+
+// ThumbnailSizes en orden creciente. El más grande es el que se guarda en cover_url / image_url
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", Size: 64},
+	{Name: "medium", Size: 300},
+	{Name: "large", Size: 640},
+}
+
+// ImageUpload es la respuesta de una subida: URL guardada en el registro y todas las versiones
+type ImageUpload struct {
+	URL         string            `json:"url"`
+	Original    string            `json:"original"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+	ContentType string            `json:"content_type"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+}
+
+// INTERFACES
+
+// BlobStore guarda archivos binarios bajo una clave tipo ruta ("albums/1/cover/ab12/large.jpg").
+// La primera implementación es en disco (storage.FileSystem); otra (S3, etc.) solo debe cumplir esto
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, *BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type ImageService interface {
+	UploadAlbumCover(ctx context.Context, albumID int64, r io.Reader) (*ImageUpload, error)
+	UploadArtistImage(ctx context.Context, artistID int64, r io.Reader) (*ImageUpload, error)
+	// Borran del blob store la imagen subida que tenía un registro ya eliminado (mejor esfuerzo).
+	// Las URL externas cargadas a mano se ignoran
+	DeleteAlbumCover(ctx context.Context, albumID int64, url string)
+	DeleteArtistImage(ctx context.Context, artistID int64, url string)
+}
@@ -1,11 +1,68 @@
 package domain
 
-import "math"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
 
 // PaginationParams define lo que entra desde la URL (?page=1&limit=10)
+//
+// Hay dos modos:
+//   - Página (page): LIMIT/OFFSET, el modo original. Permite saltar a cualquier página
+//   - Cursor (keyset): ?cursor= vacío pide la primera página y cada respuesta trae next_cursor para
+//     la siguiente. No se degrada en páginas profundas ni repite/salta filas si cambian los datos
 type PaginationParams struct {
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
+
+	Keyset bool    `json:"-"` // Modo cursor
+	Cursor string  `json:"-"` // Cursor opaco recibido, vacío en la primera página
+	After  *Cursor `json:"-"` // Cursor decodificado por Validate
+
+	// WithTotal calcula total_items/total_pages con un COUNT(*) aparte. Por defecto solo en modo página
+	WithTotal bool `json:"-"`
+	// WithFacets agrega los conteos por faceta en los listados que los soportan (?facets=true)
+	WithFacets bool `json:"-"`
+
+	Sort    string      `json:"-"` // ?sort=-release_date,title tal como llega
+	OrderBy []SortField `json:"-"` // Sort interpretado por Validate; cada repositorio revisa su allowlist
+
+	// Threshold es la similitud mínima para los filtros de texto aproximados (?threshold=0.5).
+	// nil usa el default de pg_trgm (0.3)
+	Threshold *float64 `json:"-"`
+}
+
+// SortField es un campo de ?sort=. El prefijo "-" indica orden descendente
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// MaxSortFields limita los campos de ?sort= (el ID siempre se agrega como desempate)
+const MaxSortFields = 3
+
+// SortRelevance ordena por el puntaje contra el filtro de texto (-relevance primero los más parecidos).
+// Solo existe si hay filtro de texto, y en ese caso es el orden por defecto
+const SortRelevance = "relevance"
+
+// MaxPageLimit es el máximo de ?limit= en los listados paginados
+const MaxPageLimit = 100
+
+// Límites de ?threshold= en los listados
+const (
+	MinMatchThreshold = 0.1
+	MaxMatchThreshold = 1.0
+)
+
+// Cursor apunta a la última fila entregada: los valores de las columnas de orden (el último es el ID).
+// Sort identifica el orden con que se generó, un cursor no sirve para otro orden
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
 }
 
 // GetOffset calcula el salto de registros para la base de datos
@@ -20,28 +77,143 @@ func (p *PaginationParams) GetOffset() int {
 	return (p.Page - 1) * p.Limit
 }
 
+// Validate aplica los defaults y decodifica el cursor
+func (p *PaginationParams) Validate() error {
+	p.GetOffset()
+	errs := make(ValidationError)
+
+	orderBy, err := ParseSort(p.Sort)
+	if err != nil {
+		errs["sort"] = err.Error()
+	}
+	p.OrderBy = orderBy
+
+	if p.Limit > MaxPageLimit {
+		errs["limit"] = fmt.Sprintf("el límite no puede superar %d", MaxPageLimit)
+	}
+
+	// Escrito así para rechazar también NaN
+	if p.Threshold != nil && !(*p.Threshold >= MinMatchThreshold && *p.Threshold <= MaxMatchThreshold) {
+		errs["threshold"] = fmt.Sprintf("el umbral de similitud debe estar entre %g y %g", MinMatchThreshold, MaxMatchThreshold)
+	}
+
+	if p.Keyset && p.Cursor != "" {
+		cursor, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			errs["cursor"] = "el cursor es inválido, use el next_cursor de la respuesta anterior"
+		}
+		p.After = cursor
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ParseSort interpreta "-release_date,title". Solo revisa la sintaxis: que cada nombre exista lo decide
+// el repositorio, que traduce los nombres permitidos a SQL fijo
+func ParseSort(raw string) ([]SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Name: part}
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			field = SortField{Name: name, Desc: true}
+		} else if name, ok := strings.CutPrefix(part, "+"); ok {
+			field.Name = name // "+" llega como espacio si no se codifica, TrimSpace ya lo quitó
+		}
+
+		if !validSortName(field.Name) {
+			return nil, fmt.Errorf("campo de orden inválido '%s'", part)
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("el campo '%s' está repetido", field.Name)
+		}
+		seen[field.Name] = true
+		fields = append(fields, field)
+	}
+	if len(fields) > MaxSortFields {
+		return nil, fmt.Errorf("se permiten como máximo %d campos de orden", MaxSortFields)
+	}
+	return fields, nil
+}
+
+func validSortName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeCursor serializa el cursor como base64 URL-safe, el cliente lo trata como texto opaco
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if len(c.Values) == 0 {
+		return nil, errors.New("cursor sin valores")
+	}
+	return &c, nil
+}
+
 // PaginatedResult es una estructura genérica
-// [T any] que puede contener cualquier slice de modelos
+// [T any] que puede contener cualquier slice de modelos.
+// En modo cursor no hay page; total_items y total_pages solo vienen si se pidió el conteo
 type PaginatedResult[T any] struct {
-	Data       []T `json:"data"`
-	TotalItems int `json:"total_items"`
-	TotalPages int `json:"total_pages"`
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
+	Data       []T    `json:"data"`
+	TotalItems *int   `json:"total_items,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"` // Ausente en la última página
+	Facets     Facets `json:"facets,omitempty"`      // Solo si se pidieron
 }
 
 // Helper para calcular las páginas totales automáticamente
 func NewPaginatedResult[T any](data []T, totalItems, page, limit int) *PaginatedResult[T] {
-	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
-	if totalPages == 0 {
-		totalPages = 1
-	}
+	return NewPageResult(data, PaginationParams{Page: page, Limit: limit}, &totalItems, nil)
+}
 
-	return &PaginatedResult[T]{
-		Data:       data,
-		TotalItems: totalItems,
-		TotalPages: totalPages,
-		Page:       page,
-		Limit:      limit,
+// NewPageResult arma la respuesta de cualquiera de los dos modos. total y next son opcionales
+func NewPageResult[T any](data []T, params PaginationParams, total *int, next *Cursor) *PaginatedResult[T] {
+	result := &PaginatedResult[T]{
+		Data:  data,
+		Limit: params.Limit,
+	}
+	if !params.Keyset {
+		result.Page = params.Page
+	}
+	if total != nil {
+		totalPages := int(math.Ceil(float64(*total) / float64(params.Limit)))
+		if totalPages == 0 {
+			totalPages = 1
+		}
+		result.TotalItems = total
+		result.TotalPages = &totalPages
+	}
+	if next != nil {
+		result.NextCursor = EncodeCursor(*next)
 	}
+	return result
 }
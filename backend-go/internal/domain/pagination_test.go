@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/base64"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursors := []Cursor{
+		{Sort: "id", Values: []string{"42"}},
+		{Sort: "-release_date,id", Values: []string{"1997-05-20", "7"}},
+		{Sort: "name,id", Values: []string{"Ñandú \"comillas\" / ?&=", "3"}},
+	}
+	for _, c := range cursors {
+		token := EncodeCursor(c)
+		got, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q): %v", token, err)
+		}
+		if !reflect.DeepEqual(*got, c) {
+			t.Errorf("DecodeCursor(EncodeCursor(%+v)) = %+v", c, *got)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsTampered(t *testing.T) {
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	valid := EncodeCursor(Cursor{Sort: "id", Values: []string{"42"}})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "no es base64", token: "%%%"},
+		{name: "base64 con padding", token: valid + "="},
+		{name: "base64 estándar", token: "+/+/"},
+		{name: "truncado", token: valid[:len(valid)-3]},
+		{name: "no es JSON", token: b64("id=42")},
+		{name: "tipos distintos", token: b64(`{"s":"id","v":"42"}`)},
+		{name: "sin valores", token: b64(`{"s":"id","v":[]}`)},
+		{name: "objeto vacío", token: b64(`{}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := DecodeCursor(tt.token); err == nil {
+				t.Errorf("DecodeCursor(%q) = %+v, se esperaba error", tt.token, c)
+			}
+		})
+	}
+}
+
+func TestValidateCursor(t *testing.T) {
+	valid := EncodeCursor(Cursor{Sort: "id", Values: []string{"42"}})
+	tests := []struct {
+		name      string
+		params    PaginationParams
+		wantErr   bool
+		wantAfter bool
+	}{
+		{name: "primera página", params: PaginationParams{Keyset: true}},
+		{name: "cursor válido", params: PaginationParams{Keyset: true, Cursor: valid}, wantAfter: true},
+		{name: "cursor alterado", params: PaginationParams{Keyset: true, Cursor: valid[1:]}, wantErr: true},
+		{name: "modo página ignora el cursor", params: PaginationParams{Cursor: "basura"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if errs, _ := err.(ValidationError); (errs["cursor"] != "") != tt.wantErr {
+				t.Fatalf("Validate() = %v, se esperaba error de cursor: %v", err, tt.wantErr)
+			}
+			if (tt.params.After != nil) != tt.wantAfter {
+				t.Errorf("After = %+v, se esperaba cursor decodificado: %v", tt.params.After, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestValidateThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold *float64
+		wantErr   bool
+	}{
+		{name: "sin umbral", threshold: nil},
+		{name: "mínimo", threshold: floatPtr(MinMatchThreshold)},
+		{name: "máximo", threshold: floatPtr(MaxMatchThreshold)},
+		{name: "intermedio", threshold: floatPtr(0.45)},
+		{name: "bajo el mínimo", threshold: floatPtr(0.05), wantErr: true},
+		{name: "sobre el máximo", threshold: floatPtr(1.5), wantErr: true},
+		{name: "negativo", threshold: floatPtr(-0.3), wantErr: true},
+		{name: "NaN", threshold: floatPtr(math.NaN()), wantErr: true},
+		{name: "infinito", threshold: floatPtr(math.Inf(1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := PaginationParams{Threshold: tt.threshold}
+			err := params.Validate()
+			if errs, _ := err.(ValidationError); (errs["threshold"] != "") != tt.wantErr {
+				t.Errorf("Validate() = %v, se esperaba error de umbral: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		want    int
+		wantErr bool
+	}{
+		{name: "sin límite usa el default", limit: 0, want: 10},
+		{name: "negativo usa el default", limit: -5, want: 10},
+		{name: "máximo", limit: MaxPageLimit, want: MaxPageLimit},
+		{name: "sobre el máximo", limit: MaxPageLimit + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := PaginationParams{Limit: tt.limit}
+			err := params.Validate()
+			if errs, _ := err.(ValidationError); (errs["limit"] != "") != tt.wantErr {
+				t.Fatalf("Validate() = %v, se esperaba error de límite: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && params.Limit != tt.want {
+				t.Errorf("Limit = %d, se esperaba %d", params.Limit, tt.want)
+			}
+		})
+	}
+}
+
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// MergePatch es el cuerpo de un PATCH en formato JSON Merge Patch (RFC 7386):
+// las claves presentes reemplazan el valor actual, null lo elimina y las omitidas no cambian
+type MergePatch map[string]json.RawMessage
+
+// Has indica si el patch trae el campo (aunque sea null)
+func (p MergePatch) Has(field string) bool {
+	_, ok := p[field]
+	return ok
+}
+
+// Apply aplica el patch sobre target, un puntero a un input con tags json. Los campos que
+// quedan eliminados (null) vuelven a su valor cero, por ejemplo un *string queda en nil.
+// Campos desconocidos o con tipo incorrecto se reportan como ValidationError
+func (p MergePatch) Apply(target any) error {
+	doc, err := toDocument(target)
+	if err != nil {
+		return err
+	}
+
+	errs := make(ValidationError)
+	for field, raw := range p {
+		if _, known := doc[field]; !known {
+			errs[field] = "el campo no existe o no se puede modificar"
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			errs[field] = "valor JSON inválido"
+			continue
+		}
+		if value == nil {
+			delete(doc, field)
+			continue
+		}
+		doc[field] = mergeValue(doc[field], value)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	merged, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	// Partir desde cero para que los campos eliminados no conserven el valor anterior
+	v := reflect.ValueOf(target).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	if err := json.NewDecoder(bytes.NewReader(merged)).Decode(target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return ValidationError{typeErr.Field: "el tipo de dato no es válido para este campo"}
+		}
+		return err
+	}
+	return nil
+}
+
+// Scope deja en un ValidationError solo los errores de campos que trae el patch, asi un PATCH
+// no falla por datos antiguos que no está tocando. Los errores que no corresponden a un campo
+// del input (ej. "role" dentro de "artists") se conservan
+func (p MergePatch) Scope(err error, target any) error {
+	var valErrs ValidationError
+	if !errors.As(err, &valErrs) {
+		return err
+	}
+	doc, docErr := toDocument(target)
+	if docErr != nil {
+		return docErr
+	}
+
+	scoped := make(ValidationError)
+	for field, msg := range valErrs {
+		if _, isField := doc[field]; isField && !p.Has(field) {
+			continue
+		}
+		scoped[field] = msg
+	}
+	if len(scoped) > 0 {
+		return scoped
+	}
+	return nil
+}
+
+func toDocument(target any) (map[string]any, error) {
+	raw, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]any)
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// mergeValue aplica la regla recursiva del RFC: objetos se mezclan clave a clave,
+// cualquier otro valor (incluidos arrays) reemplaza completo
+func mergeValue(current, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	currentObj, ok := current.(map[string]any)
+	if !ok {
+		currentObj = make(map[string]any)
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(currentObj, k)
+			continue
+		}
+		currentObj[k] = mergeValue(currentObj[k], v)
+	}
+	return currentObj
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type patchMeta struct {
+	Label string `json:"label"`
+	Notes string `json:"notes"`
+}
+
+type patchTarget struct {
+	Name     string    `json:"name"`
+	Bio      *string   `json:"bio"`
+	Duration int       `json:"duration"`
+	Tags     []string  `json:"tags"`
+	Meta     patchMeta `json:"meta"`
+}
+
+func TestMergePatchApply(t *testing.T) {
+	bio := "Biografía"
+	base := func() patchTarget {
+		b := bio
+		return patchTarget{Name: "Original", Bio: &b, Duration: 180, Tags: []string{"rock", "pop"}, Meta: patchMeta{Label: "Sello", Notes: "Notas"}}
+	}
+
+	tests := []struct {
+		name     string
+		patch    string
+		want     func() patchTarget
+		wantErrs []string // Campos esperados en el ValidationError
+	}{
+		{
+			name:  "reemplaza un valor",
+			patch: `{"name":"Nuevo"}`,
+			want:  func() patchTarget { p := base(); p.Name = "Nuevo"; return p },
+		},
+		{
+			name:  "objeto vacío no cambia nada",
+			patch: `{}`,
+			want:  base,
+		},
+		{
+			name:  "null elimina un puntero",
+			patch: `{"bio":null}`,
+			want:  func() patchTarget { p := base(); p.Bio = nil; return p },
+		},
+		{
+			name:  "null deja un valor en cero",
+			patch: `{"duration":null}`,
+			want:  func() patchTarget { p := base(); p.Duration = 0; return p },
+		},
+		{
+			name:  "arrays se reemplazan completos",
+			patch: `{"tags":["jazz"]}`,
+			want:  func() patchTarget { p := base(); p.Tags = []string{"jazz"}; return p },
+		},
+		{
+			name:  "objetos se mezclan clave a clave",
+			patch: `{"meta":{"notes":"Otras"}}`,
+			want:  func() patchTarget { p := base(); p.Meta.Notes = "Otras"; return p },
+		},
+		{
+			name:  "null dentro de un objeto elimina la clave",
+			patch: `{"meta":{"label":null}}`,
+			want:  func() patchTarget { p := base(); p.Meta.Label = ""; return p },
+		},
+		{
+			name:     "campo desconocido",
+			patch:    `{"name":"Nuevo","id":99}`,
+			wantErrs: []string{"id"},
+		},
+		{
+			name:     "tipo incorrecto",
+			patch:    `{"duration":"largo"}`,
+			wantErrs: []string{"duration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch MergePatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("patch inválido en el test: %v", err)
+			}
+			target := base()
+			err := patch.Apply(&target)
+
+			if tt.wantErrs != nil {
+				errs, ok := err.(ValidationError)
+				if !ok {
+					t.Fatalf("Apply() = %v, se esperaba ValidationError", err)
+				}
+				for _, field := range tt.wantErrs {
+					if errs[field] == "" {
+						t.Errorf("falta el error de '%s' en %v", field, errs)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply(): %v", err)
+			}
+			if want := tt.want(); !reflect.DeepEqual(target, want) {
+				t.Errorf("Apply() = %+v, se esperaba %+v", target, want)
+			}
+		})
+	}
+}
+
+func TestMergePatchApplyInvalidJSON(t *testing.T) {
+	patch := MergePatch{"name": json.RawMessage(`{`)}
+	target := patchTarget{Name: "Original"}
+	errs, ok := patch.Apply(&target).(ValidationError)
+	if !ok || errs["name"] == "" {
+		t.Fatalf("se esperaba ValidationError en 'name', se obtuvo %v", errs)
+	}
+	if target.Name != "Original" {
+		t.Errorf("un patch con errores no debe modificar el destino, name = %q", target.Name)
+	}
+}
+
+func TestMergePatchScope(t *testing.T) {
+	patch := MergePatch{"name": json.RawMessage(`""`)}
+	valErrs := ValidationError{"name": "requerido", "duration": "inválida", "role": "rol inválido"}
+
+	got := patch.Scope(valErrs, &patchTarget{})
+	want := ValidationError{"name": "requerido", "role": "rol inválido"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scope() = %v, se esperaba %v", got, want)
+	}
+	if err := (MergePatch{}).Scope(ValidationError{"duration": "inválida"}, &patchTarget{}); err != nil {
+		t.Errorf("Scope() = %v, se esperaba nil si solo fallan campos que el patch no toca", err)
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+)
+
+// MODELOS
+
+// Representa una cancion dentro de la playlist. Una misma cancion puede aparecer varias veces
+type PlaylistEntry struct {
+	ID       int64     `json:"id"`
+	Position int       `json:"position"`
+	SongID   int64     `json:"song_id"`
+	Title    string    `json:"title"`    // Info extraída de la tabla songs
+	Duration int       `json:"duration"` // Info extraída de la tabla songs
+	CoverURL *string   `json:"cover_url"`
+	AddedAt  time.Time `json:"added_at"`
+
+	Artists []ArtistWithRole `json:"artists,omitempty"`
+}
+
+type Playlist struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	SongCount   int        `json:"song_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Score       *float64   `json:"score,omitempty"` // Relevancia (0..1) contra el filtro de nombre
+
+	Entries []PlaylistEntry `json:"entries,omitempty"`
+}
+
+type PlaylistInput struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+// Position es opcional. Si es 0 (o mayor al largo de la playlist) la cancion se agrega al final
+type PlaylistEntryInput struct {
+	SongID   int64 `json:"song_id"`
+	Position int   `json:"position"`
+}
+
+type PlaylistMoveInput struct {
+	Position int `json:"position"`
+}
+
+type PlaylistFilter struct {
+	Name string
+}
+
+// VALIDACIONES Y LIMPIEZA
+func (input *PlaylistFilter) Sanitize() {
+	input.Name = validation.SanitizeString(input.Name)
+}
+
+func (input *PlaylistInput) Sanitize() {
+	input.Name = validation.SanitizeString(input.Name)
+	input.Description = validation.SanitizeOpcionalString(input.Description)
+}
+
+func (input *PlaylistInput) Validate() error {
+	input.Sanitize()
+	errs := make(ValidationError)
+
+	if input.Name == "" {
+		errs["name"] = "el nombre de la playlist es obligatorio"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (input *PlaylistEntryInput) Validate() error {
+	errs := make(ValidationError)
+	if input.SongID <= 0 {
+		errs["song_id"] = "el ID de la canción es obligatorio y debe ser mayor a 0"
+	}
+	if input.Position < 0 {
+		errs["position"] = "la posición no puede ser negativa"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (input *PlaylistMoveInput) Validate() error {
+	errs := make(ValidationError)
+	if input.Position <= 0 {
+		errs["position"] = "la posición debe ser mayor a 0"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// INTERFACES
+type PlaylistRepository interface {
+	Create(ctx context.Context, input *PlaylistInput) (*Playlist, error)
+	GetByID(ctx context.Context, id int64) (*Playlist, error)
+	GetAllPaginated(ctx context.Context, filter PlaylistFilter, params PaginationParams) (*PaginatedResult[Playlist], error)
+	Update(ctx context.Context, id int64, input *PlaylistInput) (*Playlist, error)
+	Delete(ctx context.Context, id int64) error
+	AddEntry(ctx context.Context, playlistID int64, input *PlaylistEntryInput) (*PlaylistEntry, error)
+	RemoveEntry(ctx context.Context, playlistID, entryID int64) error
+	MoveEntry(ctx context.Context, playlistID, entryID int64, position int) error
+}
+
+type PlaylistService interface {
+	Create(ctx context.Context, input *PlaylistInput) (*Playlist, error)
+	GetByID(ctx context.Context, id int64) (*Playlist, error)
+	GetAllPaginated(ctx context.Context, filter PlaylistFilter, params PaginationParams) (*PaginatedResult[Playlist], error)
+	Update(ctx context.Context, id int64, input *PlaylistInput) (*Playlist, error)
+	Delete(ctx context.Context, id int64) error
+	AddEntry(ctx context.Context, playlistID int64, input *PlaylistEntryInput) (*PlaylistEntry, error)
+	RemoveEntry(ctx context.Context, playlistID, entryID int64) error
+	MoveEntry(ctx context.Context, playlistID, entryID int64, input *PlaylistMoveInput) error
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IntRange es un rango cerrado opcional, nil en un extremo significa sin límite
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+func (r IntRange) IsSet() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// Validate revisa que los límites no sean negativos y estén en orden. minKey y maxKey son
+// los nombres de los parámetros, se usan como claves del error
+func (r IntRange) Validate(minKey, maxKey string, errs ValidationError) {
+	if r.Min != nil && *r.Min < 0 {
+		errs[minKey] = "no puede ser negativo"
+	}
+	if r.Max != nil && *r.Max < 0 {
+		errs[maxKey] = "no puede ser negativo"
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		errs[maxKey] = fmt.Sprintf("debe ser mayor o igual a %s", minKey)
+	}
+}
+
+// TimeRange filtra fechas: From incluido y Until excluido, nil en un extremo significa sin límite
+type TimeRange struct {
+	From  *time.Time
+	Until *time.Time
+}
+
+func (r TimeRange) IsSet() bool {
+	return r.From != nil || r.Until != nil
+}
+
+func (r TimeRange) Validate(fromKey, toKey string, errs ValidationError) {
+	if r.From != nil && r.Until != nil && !r.From.Before(*r.Until) {
+		errs[toKey] = fmt.Sprintf("debe ser posterior a %s", fromKey)
+	}
+}
+
+// ParseIntBound interpreta un extremo de IntRange, vacío es sin límite
+func ParseIntBound(raw string) (*int, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' no es un número entero", raw)
+	}
+	return &n, nil
+}
+
+// ParseTimeBound interpreta un extremo de TimeRange: fecha YYYY-MM-DD o instante RFC 3339, vacío es sin límite.
+// Como límite superior una fecha incluye el día completo (se corta al inicio del día siguiente)
+func ParseTimeBound(raw string, upper bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		if upper {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' debe ser una fecha YYYY-MM-DD o RFC 3339", raw)
+	}
+	// Las columnas son TIMESTAMP sin zona, se comparan en UTC (la zona por defecto del servidor)
+	t = t.UTC()
+	return &t, nil
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func intPtr(n int) *int { return &n }
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestIntRangeValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rng      IntRange
+		wantErrs ValidationError
+	}{
+		{name: "sin límites", rng: IntRange{}},
+		{name: "solo mínimo", rng: IntRange{Min: intPtr(60)}},
+		{name: "solo máximo", rng: IntRange{Max: intPtr(0)}},
+		{name: "límites iguales", rng: IntRange{Min: intPtr(180), Max: intPtr(180)}},
+		{name: "mínimo negativo", rng: IntRange{Min: intPtr(-1)}, wantErrs: ValidationError{"min": "no puede ser negativo"}},
+		{name: "máximo negativo", rng: IntRange{Max: intPtr(-5)}, wantErrs: ValidationError{"max": "no puede ser negativo"}},
+		{name: "invertido", rng: IntRange{Min: intPtr(300), Max: intPtr(200)}, wantErrs: ValidationError{"max": "debe ser mayor o igual a min"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make(ValidationError)
+			tt.rng.Validate("min", "max", errs)
+			if len(errs) == 0 && len(tt.wantErrs) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("Validate() = %v, se esperaba %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestTimeRangeValidate(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rng     TimeRange
+		wantErr bool
+	}{
+		{name: "sin límites", rng: TimeRange{}},
+		{name: "abierto arriba", rng: TimeRange{From: timePtr(day)}},
+		{name: "en orden", rng: TimeRange{From: timePtr(day), Until: timePtr(day.AddDate(0, 0, 1))}},
+		{name: "vacío (from = to)", rng: TimeRange{From: timePtr(day), Until: timePtr(day)}, wantErr: true},
+		{name: "invertido", rng: TimeRange{From: timePtr(day), Until: timePtr(day.AddDate(0, 0, -1))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make(ValidationError)
+			tt.rng.Validate("from", "to", errs)
+			if (errs["to"] != "") != tt.wantErr {
+				t.Errorf("Validate() = %v, se esperaba error: %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseIntBound(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    *int
+		wantErr bool
+	}{
+		{raw: "", want: nil},
+		{raw: "0", want: intPtr(0)},
+		{raw: "240", want: intPtr(240)},
+		{raw: "-3", want: intPtr(-3)}, // El signo lo rechaza Validate
+		{raw: "3.5", wantErr: true},
+		{raw: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseIntBound(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIntBound(%q) error = %v, se esperaba error: %v", tt.raw, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIntBound(%q) = %v, se esperaba %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		upper   bool
+		want    *time.Time
+		wantErr bool
+	}{
+		{name: "vacío", raw: "", want: nil},
+		{name: "fecha como inicio", raw: "2024-03-01", want: timePtr(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "fecha como fin incluye el día", raw: "2024-03-01", upper: true, want: timePtr(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))},
+		{name: "fin de año bisiesto", raw: "2024-02-29", upper: true, want: timePtr(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "RFC 3339 en UTC", raw: "2024-03-01T10:30:00Z", want: timePtr(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))},
+		{name: "RFC 3339 con zona se pasa a UTC", raw: "2024-03-01T10:30:00-03:00", want: timePtr(time.Date(2024, 3, 1, 13, 30, 0, 0, time.UTC))},
+		{name: "RFC 3339 como fin no se extiende", raw: "2024-03-01T10:30:00Z", upper: true, want: timePtr(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))},
+		{name: "fecha inexistente", raw: "2023-02-29", wantErr: true},
+		{name: "formato local", raw: "01/03/2024", wantErr: true},
+		{name: "sin zona horaria", raw: "2024-03-01T10:30:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeBound(tt.raw, tt.upper)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeBound(%q) error = %v, se esperaba error: %v", tt.raw, err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("ParseTimeBound(%q) = %v, se esperaba %v", tt.raw, got, tt.want)
+			}
+			if got != nil && got.Location() != time.UTC {
+				t.Errorf("ParseTimeBound(%q) debe quedar en UTC, zona %v", tt.raw, got.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+)
+
+// MODELOS
+
+// Tipos de resultado de la búsqueda unificada, también son los nombres de las secciones
+const (
+	SearchTypeArtist = "artist"
+	SearchTypeSong   = "song"
+	SearchTypeAlbum  = "album"
+)
+
+// SearchTypes en el orden en que se devuelven las secciones
+var SearchTypes = []string{SearchTypeArtist, SearchTypeSong, SearchTypeAlbum}
+
+// Estado de una sección. Una sección lenta o con error no hace fallar la búsqueda completa
+const (
+	SearchStatusOK      = "ok"
+	SearchStatusTimeout = "timeout"
+	SearchStatusError   = "error"
+)
+
+// SearchHit es un resultado de cualquier entidad, con su puntaje similarity() (0..1)
+type SearchHit struct {
+	Type     string  `json:"type"`
+	ID       int64   `json:"id"`
+	Title    string  `json:"title"`              // Nombre del artista o título de canción/álbum
+	Subtitle string  `json:"subtitle,omitempty"` // Artistas de la canción/álbum, país del artista
+	Score    float64 `json:"score"`
+}
+
+type SearchSection struct {
+	Type   string      `json:"type"`
+	Status string      `json:"status"`
+	Count  int         `json:"count"` // Coincidencias totales, aunque solo se devuelvan las primeras
+	Items  []SearchHit `json:"items"`
+}
+
+type SearchResult struct {
+	Query    string          `json:"query"`
+	Partial  bool            `json:"partial"`  // Alguna sección no respondió a tiempo o falló
+	Top      []SearchHit     `json:"top"`      // Mejores resultados de todas las secciones juntas
+	Sections []SearchSection `json:"sections"` // Una por tipo pedido
+}
+
+// SearchOptions viene desde la URL (?q=soda&limit=5&types=artist,album&timeout_ms=500)
+type SearchOptions struct {
+	Query   string
+	Limit   int           // Resultados por sección y en top
+	Types   []string      // Vacío = todos
+	Timeout time.Duration // Plazo pedido por el cliente; 0 usa el del servidor, que además es el máximo
+}
+
+const (
+	SearchDefaultLimit = 10
+	SearchMaxLimit     = 50
+)
+
+// VALIDACIONES
+func (o *SearchOptions) Validate() error {
+	o.Query = validation.SanitizeString(o.Query)
+	errs := make(ValidationError)
+
+	if o.Query == "" {
+		errs["q"] = "el texto a buscar es obligatorio"
+	}
+	if o.Limit == 0 {
+		o.Limit = SearchDefaultLimit
+	}
+	if o.Limit < 0 || o.Limit > SearchMaxLimit {
+		errs["limit"] = "el límite debe estar entre 1 y 50"
+	}
+	if o.Timeout < 0 {
+		errs["timeout_ms"] = "el plazo no puede ser negativo"
+	}
+
+	if len(o.Types) == 0 {
+		o.Types = SearchTypes
+	} else {
+		requested := make(map[string]bool)
+		for _, t := range o.Types {
+			t = strings.TrimSpace(t)
+			if t != SearchTypeArtist && t != SearchTypeSong && t != SearchTypeAlbum {
+				errs["types"] = "los tipos deben ser artist, song o album"
+				break
+			}
+			requested[t] = true
+		}
+		// Las secciones siempre salen en el orden de SearchTypes, sin repetidos
+		var types []string
+		for _, t := range SearchTypes {
+			if requested[t] {
+				types = append(types, t)
+			}
+		}
+		o.Types = types
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// INTERFACES
+
+// SearchRepository busca una entidad y devuelve los mejores resultados junto al total de coincidencias
+type SearchRepository interface {
+	SearchArtists(ctx context.Context, query string, limit int) ([]SearchHit, int, error)
+	SearchSongs(ctx context.Context, query string, limit int) ([]SearchHit, int, error)
+	SearchAlbums(ctx context.Context, query string, limit int) ([]SearchHit, int, error)
+}
+
+type SearchService interface {
+	Search(ctx context.Context, opts SearchOptions) (*SearchResult, error)
+}
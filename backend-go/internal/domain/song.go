@@ -22,9 +22,12 @@ type Song struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Score     *float64   `json:"score,omitempty"` // Relevancia (0..1) contra el filtro de texto del listado
 
 	Artists  []ArtistWithRole `json:"artists,omitempty"`
-	CoverURL *string          `json:"cover_url"` // Permite nulos
+	CoverURL *string          `json:"cover_url"`           // Permite nulos
+	FilePath *string          `json:"file_path,omitempty"` // Ubicación local conocida del audio
+	HasAudio bool             `json:"has_audio"`           // Tiene audio adjunto en GET /songs/{id}/audio
 }
 
 type ArtistSongInput struct {
@@ -42,6 +45,8 @@ type SongFilter struct {
 	Title      string
 	ArtistID   int64
 	ArtistName string
+	Duration   IntRange  // Segundos
+	Created    TimeRange // Fecha de creación
 }
 
 type ArtistsSongSearchResult struct {
@@ -55,6 +60,19 @@ type SongSearchResult struct {
 	Artists []ArtistsSongSearchResult `json:"artists"`
 }
 
+// ToInput arma el input equivalente al estado actual, base sobre la que se aplica un PATCH
+func (s *Song) ToInput() SongInput {
+	input := SongInput{
+		Title:    s.Title,
+		Duration: s.Duration,
+		Artists:  make([]ArtistSongInput, 0, len(s.Artists)),
+	}
+	for _, a := range s.Artists {
+		input.Artists = append(input.Artists, ArtistSongInput{ArtistID: a.ID, Role: a.Role})
+	}
+	return input
+}
+
 // VALIDACIONES
 func (input *ArtistSongInput) Sanitize() {
 	input.Role = validation.SanitizeString(input.Role)
@@ -82,6 +100,19 @@ func (input *SongFilter) Sanitize() {
 	input.ArtistName = validation.SanitizeString(input.ArtistName)
 }
 
+func (input *SongFilter) Validate() error {
+	input.Sanitize()
+	errs := make(ValidationError)
+
+	input.Duration.Validate("duration_min", "duration_max", errs)
+	input.Created.Validate("created_from", "created_to", errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func (input *SongInput) Sanitize() {
 	input.Title = validation.SanitizeString(input.Title)
 }
@@ -122,11 +153,22 @@ type SongRepository interface {
 	GetByID(ctx context.Context, id int64) (*Song, error)
 	GetAll(ctx context.Context) ([]Song, error)
 	GetAllPaginated(ctx context.Context, filter SongFilter, params PaginationParams) (*PaginatedResult[Song], error)
-	Update(ctx context.Context, id int64, input *SongInput) (*Song, error)
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, input *SongInput, version *time.Time) (*Song, error)
+	Delete(ctx context.Context, id int64, version *time.Time) error
 	AddArtist(ctx context.Context, songID int64, input *ArtistSongInput) error
 	RemoveArtist(ctx context.Context, songID, artistID int64) error
 	SearchSongs(ctx context.Context, searchTerm string) ([]SongSearchResult, error)
+	GetByArtistID(ctx context.Context, artistID int64) ([]Song, error)
+	SetFilePath(ctx context.Context, id int64, path *string) error
+	GetAudio(ctx context.Context, id int64) (*SongAudio, error)
+	// SetAudio reemplaza el audio adjunto (nil lo quita). duration > 0 actualiza también la duración
+	SetAudio(ctx context.Context, id int64, audio *SongAudio, duration int) error
+
+	// Papelera
+	GetDeletedPaginated(ctx context.Context, params PaginationParams) (*PaginatedResult[Song], error)
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, id int64) (*Purged, error)
+	PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]Purged, error)
 }
 
 type SongService interface {
@@ -134,9 +176,12 @@ type SongService interface {
 	GetByID(ctx context.Context, id int64) (*Song, error)
 	GetAll(ctx context.Context) ([]Song, error)
 	GetAllPaginated(ctx context.Context, filter SongFilter, params PaginationParams) (*PaginatedResult[Song], error)
-	Update(ctx context.Context, id int64, input *SongInput) (*Song, error)
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, input *SongInput, version *time.Time) (*Song, error)
+	Patch(ctx context.Context, id int64, patch MergePatch, version *time.Time) (*Song, error)
+	Delete(ctx context.Context, id int64, version *time.Time) error
 	AddArtist(ctx context.Context, songID int64, input *ArtistSongInput) error
 	RemoveArtist(ctx context.Context, songID, artistID int64) error
 	SearchSongs(ctx context.Context, searchTerm string) ([]SongSearchResult, error)
+	GetByArtistID(ctx context.Context, artistID int64) ([]Song, error)
+	SetFilePath(ctx context.Context, id int64, path *string) error
 }
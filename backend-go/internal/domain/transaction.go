@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Transactor permite que un servicio agrupe varias operaciones de distintos repositorios
+// en una sola transacción. Los repositorios que reciben el ctx de fn trabajan dentro de ella
+// (sus transacciones internas pasan a ser savepoints)
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
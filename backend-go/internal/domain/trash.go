@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MODELOS
+
+// PurgeReport resume cuantos registros se eliminaron definitivamente de la papelera
+type PurgeReport struct {
+	Artists   int64     `json:"artists"`
+	Songs     int64     `json:"songs"`
+	Albums    int64     `json:"albums"`
+	OlderThan time.Time `json:"older_than"`
+}
+
+// Purged es un registro eliminado definitivamente y el archivo que referenciaba: la clave del audio de
+// una canción o la URL de la imagen de un álbum/artista (nil si no tenía). El servicio lo borra del blob store
+type Purged struct {
+	ID   int64
+	File *string
+}
+
+// INTERFACES
+
+// TrashService administra los registros con soft delete (deleted_at no nulo) de todas las entidades
+type TrashService interface {
+	GetDeletedArtists(ctx context.Context, params PaginationParams) (*PaginatedResult[Artist], error)
+	GetDeletedSongs(ctx context.Context, params PaginationParams) (*PaginatedResult[Song], error)
+	GetDeletedAlbums(ctx context.Context, params PaginationParams) (*PaginatedResult[Album], error)
+	RestoreArtist(ctx context.Context, id int64) (*Artist, error)
+	RestoreSong(ctx context.Context, id int64) (*Song, error)
+	RestoreAlbum(ctx context.Context, id int64) (*Album, error)
+	PurgeArtist(ctx context.Context, id int64) error
+	PurgeSong(ctx context.Context, id int64) error
+	PurgeAlbum(ctx context.Context, id int64) error
+	PurgeOlderThan(ctx context.Context, retention time.Duration) (*PurgeReport, error)
+}
@@ -39,10 +39,14 @@ func (h *AlbumHandler) Create(w http.ResponseWriter, r *http.Request) {
 			WriteError(w, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
-		if errors.Is(err, domain.ErrSongNotFound) {
+		if errors.Is(err, domain.ErrSongNotInDB) {
 			WriteError(w, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
+		if errors.Is(err, domain.ErrTrackAlreadyExists) || errors.Is(err, domain.ErrSongAlreadyInAlbum) {
+			WriteError(w, http.StatusConflict, err.Error(), nil) // 409
+			return
+		}
 
 		WriteError(w, http.StatusInternalServerError, "No se pudo crear la cancion", err.Error()) // 500
 		return
@@ -76,37 +80,47 @@ func (h *AlbumHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, album.UpdatedAt)
 	WriteJSON(w, http.StatusOK, album) // 200
 }
 
 // GET ALL PAG (GET /albums?page=1&limit=10&artist_id=1)
+// Modo cursor: GET /albums?cursor=&limit=20, luego ?cursor=<next_cursor>
+// Orden: GET /albums?sort=-total_duration (ver la allowlist en el repositorio)
 func (h *AlbumHandler) GetAllPaginated(w http.ResponseWriter, r *http.Request) {
 	// Extraer query params
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 {
-		limit = 10
-	}
-	pagination := domain.PaginationParams{
-		Page:  page,
-		Limit: limit,
-	}
+	queryErrs := make(domain.ValidationError)
+	pagination := readPagination(r, queryErrs)
 
 	// Extraer artist_id si es viene en query params
 	filter := domain.AlbumFilter{
 		Title:      r.URL.Query().Get("title"),
-		Type:       r.URL.Query().Get("type"),
+		Type:       r.URL.Query()["type"], // Repetibles: ?type=EP&type=LP
 		ArtistName: r.URL.Query().Get("artist_name"),
 	}
 	if artistIDStr := r.URL.Query().Get("artist_id"); artistIDStr != "" {
 		filter.ArtistID, _ = strconv.ParseInt(artistIDStr, 10, 64)
 	}
+	// Años y rangos opcionales: fecha de lanzamiento y de creación
+	years, err := readInts(r, "year")
+	if err != nil {
+		queryErrs["year"] = err.Error()
+	}
+	filter.Year = years
+	filter.Released = readTimeRange(r, "released_from", "released_to", queryErrs)
+	filter.Created = readTimeRange(r, "created_from", "created_to", queryErrs)
+	if len(queryErrs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", queryErrs) // 400
+		return
+	}
 
 	paginatedData, err := h.service.GetAllPaginated(r.Context(), filter, pagination)
 	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", valErrs) // 400
+			return
+		}
 		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
 		WriteError(w, http.StatusInternalServerError, "Error obteniendo la lista de albums", nil)
 		return
@@ -161,34 +175,71 @@ func (h *AlbumHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	album, err := h.service.Update(r.Context(), id, &input)
+	album, err := h.service.Update(r.Context(), id, &input, readIfMatch(r))
 	if err != nil {
-		// Errores de validacion
-		var valErrs domain.ValidationError
-		if errors.As(err, &valErrs) {
-			WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
-			return
-		}
+		h.writeUpdateError(w, r, id, err)
+		return
+	}
 
-		// Errores de negocio / relaciones
-		if errors.Is(err, domain.ErrAlbumNotFound) {
-			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
-			return
-		}
-		if errors.Is(err, domain.ErrArtistNotFound) {
-			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
-			return
-		}
+	setETag(w, album.UpdatedAt)
+	WriteJSON(w, http.StatusOK, album)
+}
+
+// PATCH (PATCH /albums/{id}) con JSON Merge Patch. Ej: {"cover_url": null} quita la portada
+func (h *AlbumHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
 
-		log.Printf("[ERROR INTERNO] PUT /albums/%d: %v\n", id, err)
-		WriteError(w, http.StatusInternalServerError, "Error actualizando el álbum", nil) // 500
+	patch, ok := readMergePatch(w, r)
+	if !ok {
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, album)
+	album, err := h.service.Patch(r.Context(), id, patch, readIfMatch(r))
+	if err != nil {
+		h.writeUpdateError(w, r, id, err)
+		return
+	}
+
+	setETag(w, album.UpdatedAt)
+	WriteJSON(w, http.StatusOK, album) // 200
 }
 
-// DELETE (DELETE /albums/{id})
+// writeUpdateError traduce los errores de PUT y PATCH a su código HTTP
+func (h *AlbumHandler) writeUpdateError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	// Errores de validacion
+	var valErrs domain.ValidationError
+	if errors.As(err, &valErrs) {
+		WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
+		return
+	}
+
+	// Errores de negocio / relaciones
+	if errors.Is(err, domain.ErrAlbumNotFound) {
+		WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+		return
+	}
+	if errors.Is(err, domain.ErrPreconditionFailed) {
+		h.writeStale(w, r, id, err) // 412
+		return
+	}
+	if errors.Is(err, domain.ErrArtistNotFound) || errors.Is(err, domain.ErrSongNotInDB) {
+		WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
+		return
+	}
+	if errors.Is(err, domain.ErrTrackAlreadyExists) || errors.Is(err, domain.ErrSongAlreadyInAlbum) {
+		WriteError(w, http.StatusConflict, err.Error(), nil) // 409
+		return
+	}
+
+	log.Printf("[ERROR INTERNO] %s /albums/%d: %v\n", r.Method, id, err)
+	WriteError(w, http.StatusInternalServerError, "Error actualizando el álbum", nil) // 500
+}
+
+// DELETE (DELETE /albums/{id}?policy=restrict|cascade|detach&dry_run=true)
 func (h *AlbumHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
@@ -200,22 +251,37 @@ func (h *AlbumHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), id); err != nil {
+	report, err := h.service.Delete(r.Context(), id, readDeleteOptions(r), readIfMatch(r))
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de borrado inválidos", valErrs)
+			return
+		}
 		if errors.Is(err, domain.ErrAlbumNotFound) {
 			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
 			return
 		}
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			h.writeStale(w, r, id, err) // 412
+			return
+		}
+		if errors.Is(err, domain.ErrAlbumInUse) {
+			WriteError(w, http.StatusConflict, err.Error(), report) // 409, detalla qué lo bloquea
+			return
+		}
 		log.Printf("[ERROR INTERNO] DELETE /albums/%d: %v\n", id, err)
 		WriteError(w, http.StatusInternalServerError, "Error al eliminar el álbum", nil) // 500
 		return
 	}
 
-	WriteNoContent(w)
+	WriteJSON(w, http.StatusOK, report) // 200
 }
 
 // Otros
 
 // Agregar nuevo track a album (POST /albums/{id}/tracks)
+// Con ?shift=true inserta en la posición indicada desplazando los tracks siguientes
 func (h *AlbumHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
 	albumID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
@@ -233,7 +299,11 @@ func (h *AlbumHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.AddTrack(r.Context(), albumID, &input)
+	if r.URL.Query().Get("shift") == "true" {
+		err = h.service.InsertTrack(r.Context(), albumID, &input)
+	} else {
+		err = h.service.AddTrack(r.Context(), albumID, &input)
+	}
 	if err != nil {
 		var valErrs domain.ValidationError
 		if errors.As(err, &valErrs) {
@@ -251,6 +321,10 @@ func (h *AlbumHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
 			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400 Bad Request
 			return
 		}
+		if errors.Is(err, domain.ErrAlbumNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
 
 		log.Printf("[ERROR INTERNO] POST /albums/%d/tracks: %v\n", albumID, err)
 		WriteError(w, http.StatusInternalServerError, "Error al agregar el track", nil) // 500
@@ -262,6 +336,7 @@ func (h *AlbumHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
 }
 
 // DELETE (/albums/{id}/tracks/{song_id})
+// Con ?shift=true sube los tracks siguientes para cerrar el hueco
 func (h *AlbumHandler) RemoveTrack(w http.ResponseWriter, r *http.Request) {
 	albumID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	songID, err2 := strconv.ParseInt(r.PathValue("song_id"), 10, 64)
@@ -271,9 +346,13 @@ func (h *AlbumHandler) RemoveTrack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.RemoveTrack(r.Context(), albumID, songID)
+	if r.URL.Query().Get("shift") == "true" {
+		err = h.service.RemoveTrackAndCompact(r.Context(), albumID, songID)
+	} else {
+		err = h.service.RemoveTrack(r.Context(), albumID, songID)
+	}
 	if err != nil {
-		if errors.Is(err, domain.ErrTrackNotFound) {
+		if errors.Is(err, domain.ErrTrackNotFound) || errors.Is(err, domain.ErrAlbumNotFound) {
 			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
 			return
 		}
@@ -285,3 +364,58 @@ func (h *AlbumHandler) RemoveTrack(w http.ResponseWriter, r *http.Request) {
 
 	WriteNoContent(w) // 204
 }
+
+// Reordenar tracklist completo (PUT /albums/{id}/tracks)
+func (h *AlbumHandler) ReorderTracks(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || albumID <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID del álbum debe ser un entero válido mayor a 0", nil)
+		return
+	}
+
+	var input domain.TrackOrderInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	err = h.service.ReorderTracks(r.Context(), albumID, &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Orden de tracks inválido", valErrs)
+			return
+		}
+		if errors.Is(err, domain.ErrTrackOrderMismatch) {
+			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
+			return
+		}
+		if errors.Is(err, domain.ErrAlbumNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] PUT /albums/%d/tracks: %v\n", albumID, err)
+		WriteError(w, http.StatusInternalServerError, "Error al reordenar los tracks", nil) // 500
+		return
+	}
+
+	WriteMessageJSON(w, http.StatusOK, "Tracks reordenados exitosamente") // 200
+}
+
+// writeStale responde 412 con la representación y el ETag actuales del álbum
+func (h *AlbumHandler) writeStale(w http.ResponseWriter, r *http.Request, id int64, staleErr error) {
+	current, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlbumNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404, se eliminó entre medio
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /albums/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al buscar el álbum", nil) // 500
+		return
+	}
+
+	setETag(w, current.UpdatedAt)
+	WriteError(w, http.StatusPreconditionFailed, staleErr.Error(), current)
+}
@@ -91,6 +91,7 @@ func (h *ArtistHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, artist.UpdatedAt)
 	WriteJSON(w, http.StatusOK, artist) // 200 OK
 }
 
@@ -111,31 +112,31 @@ func (h *ArtistHandler) SearchArtists(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET ALL PAG (GET /artists?page=2&limit=5&genre=rock&country=chile?name=  bad)
+// Modo cursor: GET /artists?cursor=&limit=20, luego ?cursor=<next_cursor>
+// Orden: GET /artists?sort=-song_count,name (ver la allowlist en el repositorio)
 func (h *ArtistHandler) GetAllPaginated(w http.ResponseWriter, r *http.Request) {
 	// Extraer query params
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 {
-		limit = 10 // valor default
-	}
-	pagination := domain.PaginationParams{
-		Page:  page,
-		Limit: limit,
+	queryErrs := make(domain.ValidationError)
+	pagination := readPagination(r, queryErrs)
+	if len(queryErrs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", queryErrs) // 400
+		return
 	}
 
 	filter := domain.ArtistFilter{
 		Name:    r.URL.Query().Get("name"),
-		Genre:   r.URL.Query().Get("genre"),
-		Country: r.URL.Query().Get("country"),
+		Genre:   r.URL.Query()["genre"], // Repetibles: ?genre=Rock&genre=Pop
+		Country: r.URL.Query()["country"],
 	}
 
 	// Llamar servicio
 	paginatedData, err := h.service.GetAllPaginated(r.Context(), filter, pagination)
 	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", valErrs) // 400
+			return
+		}
 		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
 		WriteError(w, http.StatusInternalServerError, "Error obteniendo la lista de artistas", nil)
 		return
@@ -166,31 +167,64 @@ func (h *ArtistHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Pasar el ID y los datos a la capa de Servicio
-	artist, err := h.service.Update(r.Context(), id, &input)
+	// Pasar el ID, los datos y la versión leída por el cliente (If-Match) a la capa de Servicio
+	artist, err := h.service.Update(r.Context(), id, &input, readIfMatch(r))
 	if err != nil {
-		// Evaluar si es error de validación de campos
-		var valErrs domain.ValidationError
-		if errors.As(err, &valErrs) {
-			WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
-			return
-		}
-
-		// Caso error fue porque no se encontró el artista
-		if errors.Is(err, domain.ErrArtistNotFound) {
-			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
-			return
-		}
-		// Cualquier otro error de validación o base de datos
-		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
-		WriteError(w, http.StatusInternalServerError, "Error actualizando al artista", nil) // 500
+		h.writeUpdateError(w, r, id, err)
 		return
 	}
 
+	setETag(w, artist.UpdatedAt)
 	WriteJSON(w, http.StatusOK, artist) // 200 OK
 }
 
-// DELETE (DELETE /artists/{id})
+// PATCH (PATCH /artists/{id}) con JSON Merge Patch. Ej: {"bio": null} borra la biografía
+func (h *ArtistHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	patch, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	artist, err := h.service.Patch(r.Context(), id, patch, readIfMatch(r))
+	if err != nil {
+		h.writeUpdateError(w, r, id, err)
+		return
+	}
+
+	setETag(w, artist.UpdatedAt)
+	WriteJSON(w, http.StatusOK, artist) // 200
+}
+
+// writeUpdateError traduce los errores de PUT y PATCH a su código HTTP
+func (h *ArtistHandler) writeUpdateError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	// Evaluar si es error de validación de campos
+	var valErrs domain.ValidationError
+	if errors.As(err, &valErrs) {
+		WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
+		return
+	}
+
+	// Caso error fue porque no se encontró el artista
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+		return
+	}
+	if errors.Is(err, domain.ErrPreconditionFailed) {
+		h.writeStale(w, r, id, err) // 412
+		return
+	}
+	// Cualquier otro error de validación o base de datos
+	log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+	WriteError(w, http.StatusInternalServerError, "Error actualizando al artista", nil) // 500
+}
+
+// DELETE (DELETE /artists/{id}?policy=restrict|cascade|detach&dry_run=true)
 func (h *ArtistHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Extraer y convertir el ID
 	idString := r.PathValue("id")
@@ -205,19 +239,86 @@ func (h *ArtistHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Llamar al servicio. Soft Delete
-	err = h.service.Delete(r.Context(), id)
+	// Llamar al servicio. Soft Delete con política sobre canciones y álbumes
+	report, err := h.service.Delete(r.Context(), id, readDeleteOptions(r), readIfMatch(r))
 	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de borrado inválidos", valErrs)
+			return
+		}
 		if errors.Is(err, domain.ErrArtistNotFound) {
 			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
 			return
 		}
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			h.writeStale(w, r, id, err) // 412
+			return
+		}
+		if errors.Is(err, domain.ErrArtistInUse) {
+			WriteError(w, http.StatusConflict, err.Error(), report) // 409, detalla qué lo bloquea
+			return
+		}
 		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
 		WriteError(w, http.StatusInternalServerError, "Error al eliminar el artista", nil) // 500
 		return
 	}
 
-	// Responder al frontend indicando exito, sin contenido en el body
-	// 204 No Content es el código HTTP estándar para un DELETE exitoso
-	WriteNoContent(w)
+	// Se responde el reporte de lo afectado (o lo que se afectaría en dry run)
+	WriteJSON(w, http.StatusOK, report) // 200
+}
+
+// MERGE (POST /artists/{id}/merge). Body: {"source_id": 12}
+func (h *ArtistHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	var input domain.ArtistMergeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	report, err := h.service.Merge(r.Context(), id, &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Datos de fusión inválidos", valErrs)
+			return
+		}
+		if errors.Is(err, domain.ErrArtistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		if errors.Is(err, domain.ErrArtistNotInDB) {
+			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400, el source no existe
+			return
+		}
+		log.Printf("[ERROR INTERNO] POST /artists/%d/merge: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al fusionar los artistas", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, report) // 200
+}
+
+// writeStale responde 412 con la representación y el ETag actuales, para que el cliente
+// pueda comparar y reintentar sobre la versión vigente
+func (h *ArtistHandler) writeStale(w http.ResponseWriter, r *http.Request, id int64, staleErr error) {
+	current, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrArtistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404, se eliminó entre medio
+			return
+		}
+		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al buscar el artista", nil) // 500
+		return
+	}
+
+	setETag(w, current.UpdatedAt)
+	WriteError(w, http.StatusPreconditionFailed, staleErr.Error(), current)
 }
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type AudioHandler struct {
+	service domain.AudioService
+}
+
+func NewAudioHandler(service domain.AudioService) *AudioHandler {
+	return &AudioHandler{service: service}
+}
+
+// UPLOAD (PUT /songs/{id}/audio?duration=update|check)
+// Acepta el archivo como cuerpo (audio/*) o en un formulario multipart (campo "file"). El
+// multipart se lee como stream para no copiar a disco un archivo de cientos de MB dos veces
+func (h *AudioHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxAudioSize+multipartOverhead)
+
+	var body io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		part, err := multipartFile(r, "file")
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Debe adjuntar el audio en el campo 'file' de un formulario multipart", err.Error())
+			return
+		}
+		defer part.Close()
+		body = part
+	}
+
+	opts := domain.AudioAttachOptions{DurationMode: domain.AudioDurationMode(r.URL.Query().Get("duration"))}
+	result, err := h.service.Attach(r.Context(), id, body, opts)
+	if err != nil {
+		var valErrs domain.ValidationError
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &valErrs):
+			WriteError(w, http.StatusBadRequest, "El audio no coincide con la canción", valErrs) // 400
+		case errors.Is(err, domain.ErrSongNotFound):
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+		case errors.Is(err, domain.ErrAudioTooLarge), errors.As(err, &maxErr):
+			WriteError(w, http.StatusRequestEntityTooLarge, domain.ErrAudioTooLarge.Error(), nil) // 413
+		case errors.Is(err, domain.ErrAudioUnsupported):
+			WriteError(w, http.StatusUnsupportedMediaType, err.Error(), nil) // 415
+		default:
+			log.Printf("[ERROR INTERNO] PUT /songs/%d/audio: %v\n", id, err)
+			WriteError(w, http.StatusInternalServerError, "Error al guardar el audio", nil) // 500
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, result) // 200
+}
+
+// STREAM (GET /songs/{id}/audio)
+// http.ServeContent responde Range (206) e If-Range, lo que permite adelantar en el navegador
+func (h *AudioHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	f, info, audio, err := h.service.Open(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSongNotFound) || errors.Is(err, domain.ErrSongAudioNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /songs/%d/audio: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al leer el audio", nil) // 500
+		return
+	}
+	defer f.Close()
+
+	// La clave lleva el hash del contenido: sirve de ETag y cambia si se sube otro archivo
+	name := path.Base(audio.Key)
+	w.Header().Set("Content-Type", audio.ContentType)
+	w.Header().Set("ETag", `"`+strings.TrimSuffix(name, path.Ext(name))+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, name, info.ModTime, f)
+}
+
+// DELETE (DELETE /songs/{id}/audio)
+func (h *AudioHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	if err := h.service.Detach(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrSongNotFound) || errors.Is(err, domain.ErrSongAudioNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] DELETE /songs/%d/audio: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al quitar el audio", nil) // 500
+		return
+	}
+
+	WriteNoContent(w) // 204
+}
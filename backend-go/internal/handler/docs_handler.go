@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/openapi"
+)
+
+// DocsHandler publica el contrato OpenAPI y la página de documentación que lo muestra
+type DocsHandler struct {
+	spec *openapi.Spec
+}
+
+func NewDocsHandler(spec *openapi.Spec) *DocsHandler {
+	return &DocsHandler{spec: spec}
+}
+
+// SPEC (GET /openapi.json)
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(h.spec.JSON())
+}
+
+// DOCS (GET /docs)
+func (h *DocsHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(openapi.DocsHTML())
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// DuplicateHandler expone el reporte de posibles duplicados para revisión manual
+type DuplicateHandler struct {
+	service domain.DuplicateService
+}
+
+func NewDuplicateHandler(service domain.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{service: service}
+}
+
+// GET (GET /duplicates/songs?threshold=0.5&duration_tolerance=10&limit=500)
+func (h *DuplicateHandler) FindSongs(w http.ResponseWriter, r *http.Request) {
+	opts, ok := readDuplicateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	groups, err := h.service.FindDuplicateSongs(r.Context(), opts)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros inválidos", valErrs)
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /duplicates/songs: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error buscando canciones duplicadas", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, groups) // 200
+}
+
+// GET (GET /duplicates/artists?threshold=0.5&limit=500)
+func (h *DuplicateHandler) FindArtists(w http.ResponseWriter, r *http.Request) {
+	opts, ok := readDuplicateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	groups, err := h.service.FindDuplicateArtists(r.Context(), opts)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros inválidos", valErrs)
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /duplicates/artists: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error buscando artistas duplicados", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, groups) // 200
+}
+
+// readDuplicateOptions lee los query params; los vacíos quedan sin definir y el dominio aplica los defaults
+func readDuplicateOptions(w http.ResponseWriter, r *http.Request) (domain.DuplicateOptions, bool) {
+	errs := make(domain.ValidationError)
+	opts := domain.DuplicateOptions{
+		Threshold:         readThreshold(r, errs),
+		Limit:             readInt(r, "limit", errs),
+		DurationTolerance: readInt(r, "duration_tolerance", errs),
+	}
+	if len(errs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros inválidos", errs)
+		return opts, false
+	}
+	return opts, true
+}
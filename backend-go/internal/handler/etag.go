@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Control de concurrencia optimista. El ETag de artistas, canciones y álbumes es su updated_at
+// en microsegundos (la precisión de TIMESTAMP), asi el valor se puede comparar en SQL
+
+func formatETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+func setETag(w http.ResponseWriter, updatedAt time.Time) {
+	w.Header().Set("ETag", formatETag(updatedAt))
+}
+
+// readIfMatch devuelve la versión que exige el cliente, o nil si no envió If-Match (o envió "*").
+// Un ETag débil o malformado nunca coincide (If-Match usa comparación fuerte), por eso se traduce
+// a una versión imposible y el repositorio responde precondición fallida
+func readIfMatch(r *http.Request) *time.Time {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	// Solo se considera el primer ETag de la lista; el frontend envía uno solo
+	tag := strings.TrimSpace(strings.Split(header, ",")[0])
+	stale := time.Time{}
+	if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 3 {
+		return &stale
+	}
+	micros, err := strconv.ParseInt(tag[1:len(tag)-1], 36, 64)
+	if err != nil {
+		return &stale
+	}
+	version := time.UnixMicro(micros)
+	return &version
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Tamaño máximo del CSV recibido por HTTP. Catálogos más grandes se importan con cmd/import
+const maxImportSize = 10 << 20 // 10 MB
+
+type ImportHandler struct {
+	service domain.ImportService
+}
+
+func NewImportHandler(service domain.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// IMPORT (POST /import/csv?dry_run=true)
+// Acepta el CSV como cuerpo (text/csv) o como archivo en un formulario multipart (campo "file")
+func (h *ImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Debe adjuntar el CSV en el campo 'file'", err.Error())
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	report, err := h.service.ImportCSV(r.Context(), body, domain.ImportOptions{DryRun: dryRun})
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Archivo CSV inválido", valErrs) // 400
+			return
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			WriteError(w, http.StatusRequestEntityTooLarge, "El archivo supera el tamaño máximo de 10 MB", nil) // 413
+			return
+		}
+		log.Printf("[ERROR INTERNO] POST /import/csv: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al importar el catálogo", nil) // 500
+		return
+	}
+
+	// Se responde el reporte aunque haya filas con errores; cada álbum se confirma por separado
+	WriteJSON(w, http.StatusOK, report) // 200
+}
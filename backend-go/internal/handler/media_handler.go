@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Margen sobre MaxImageSize para las cabeceras del formulario multipart
+const multipartOverhead = 1 << 20
+
+// MediaHandler recibe imágenes subidas y sirve los archivos del blob store
+type MediaHandler struct {
+	images domain.ImageService
+	store  domain.BlobStore
+}
+
+func NewMediaHandler(images domain.ImageService, store domain.BlobStore) *MediaHandler {
+	return &MediaHandler{images: images, store: store}
+}
+
+type imageUploadFunc func(ctx context.Context, id int64, r io.Reader) (*domain.ImageUpload, error)
+
+// UPLOAD carátula (POST /albums/{id}/cover, multipart con campo "file")
+func (h *MediaHandler) UploadAlbumCover(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, h.images.UploadAlbumCover)
+}
+
+// UPLOAD imagen de artista (POST /artists/{id}/image, multipart con campo "file")
+func (h *MediaHandler) UploadArtistImage(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, h.images.UploadArtistImage)
+}
+
+func (h *MediaHandler) upload(w http.ResponseWriter, r *http.Request, upload imageUploadFunc) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxImageSize+multipartOverhead)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			WriteError(w, http.StatusRequestEntityTooLarge, domain.ErrImageTooLarge.Error(), nil) // 413
+			return
+		}
+		WriteError(w, http.StatusBadRequest, "Debe adjuntar la imagen en el campo 'file' de un formulario multipart", err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, err := upload(r.Context(), id, file)
+	if err != nil {
+		var valErrs domain.ValidationError
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, domain.ErrAlbumNotFound), errors.Is(err, domain.ErrArtistNotFound):
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+		case errors.Is(err, domain.ErrImageTooLarge), errors.As(err, &maxErr):
+			WriteError(w, http.StatusRequestEntityTooLarge, domain.ErrImageTooLarge.Error(), nil) // 413
+		case errors.Is(err, domain.ErrImageUnsupported):
+			WriteError(w, http.StatusUnsupportedMediaType, err.Error(), nil) // 415
+		case errors.Is(err, domain.ErrImageDimensions):
+			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
+		case errors.As(err, &valErrs):
+			WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs) // 400
+		default:
+			log.Printf("[ERROR INTERNO] POST %s: %v\n", r.URL.Path, err)
+			WriteError(w, http.StatusInternalServerError, "Error al guardar la imagen", nil) // 500
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, result) // 201
+}
+
+// SERVE (GET /media/{key...})
+// Las claves incluyen el hash del contenido, por eso la respuesta se puede cachear sin expirar.
+// http.ServeContent resuelve Content-Type por extensión, Range e If-Modified-Since
+func (h *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	f, info, err := h.store.Open(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, domain.ErrBlobNotFound) || errors.Is(err, domain.ErrBlobKeyInvalid) {
+			WriteError(w, http.StatusNotFound, domain.ErrBlobNotFound.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /media/%s: %v\n", key, err)
+		WriteError(w, http.StatusInternalServerError, "Error al leer el archivo", nil) // 500
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, path.Base(key), info.ModTime, f)
+}
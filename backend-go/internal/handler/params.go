@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Helpers para leer query params compartidos entre handlers
+
+// readPagination lee ?page=&limit= (modo página) o ?cursor= (modo cursor, vacío pide la primera página),
+// más el orden ?sort=-campo,campo.
+// ?count=true|false decide si se calcula el total; por defecto solo en modo página, que es el costoso
+// de mantener en listados grandes. ?facets=true agrega los conteos por faceta donde existan y
+// ?threshold= ajusta la similitud mínima de los filtros de texto. Un page, limit o threshold que no es
+// un número queda en errs; los defaults, el máximo de limit y el cursor se validan en el dominio
+func readPagination(r *http.Request, errs domain.ValidationError) domain.PaginationParams {
+	q := r.URL.Query()
+	page := readInt(r, "page", errs)
+	limit := readInt(r, "limit", errs)
+
+	params := domain.PaginationParams{
+		Page:   page,
+		Limit:  limit,
+		Keyset: q.Has("cursor"),
+		Cursor: q.Get("cursor"),
+		Sort:   q.Get("sort"),
+	}
+	params.WithTotal = !params.Keyset
+	if count, err := strconv.ParseBool(q.Get("count")); err == nil {
+		params.WithTotal = count
+	}
+	params.WithFacets, _ = strconv.ParseBool(q.Get("facets"))
+	params.Threshold = readThreshold(r, errs)
+	return params
+}
+
+// readInt lee un query param entero opcional (0 si falta). Si no se puede interpretar queda en errs
+func readInt(r *http.Request, key string, errs domain.ValidationError) int {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		errs[key] = fmt.Sprintf("'%s' no es un número entero", raw)
+		return 0
+	}
+	return n
+}
+
+// readThreshold lee ?threshold=, la similitud mínima de los filtros de texto. Un valor que no se puede
+// interpretar queda en errs en vez de ignorarse; los límites se validan en el dominio
+func readThreshold(r *http.Request, errs domain.ValidationError) *float64 {
+	raw := r.URL.Query().Get("threshold")
+	if raw == "" {
+		return nil
+	}
+	threshold, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		errs["threshold"] = fmt.Sprintf("'%s' no es un número", raw)
+		return nil
+	}
+	return &threshold
+}
+
+// readIntRange lee un rango ?min=&max=. Los valores que no se pueden interpretar quedan en errs,
+// que los límites estén en orden lo revisa el dominio
+func readIntRange(r *http.Request, minKey, maxKey string, errs domain.ValidationError) domain.IntRange {
+	var rng domain.IntRange
+	var err error
+	if rng.Min, err = domain.ParseIntBound(r.URL.Query().Get(minKey)); err != nil {
+		errs[minKey] = err.Error()
+	}
+	if rng.Max, err = domain.ParseIntBound(r.URL.Query().Get(maxKey)); err != nil {
+		errs[maxKey] = err.Error()
+	}
+	return rng
+}
+
+// readTimeRange lee un rango de fechas ?from=&to= (YYYY-MM-DD o RFC 3339), igual que readIntRange
+func readTimeRange(r *http.Request, fromKey, toKey string, errs domain.ValidationError) domain.TimeRange {
+	var rng domain.TimeRange
+	var err error
+	if rng.From, err = domain.ParseTimeBound(r.URL.Query().Get(fromKey), false); err != nil {
+		errs[fromKey] = err.Error()
+	}
+	if rng.Until, err = domain.ParseTimeBound(r.URL.Query().Get(toKey), true); err != nil {
+		errs[toKey] = err.Error()
+	}
+	return rng
+}
+
+// readInts lee un query param que puede repetirse (?year=1984&year=1990). Los valores vacíos se ignoran
+func readInts(r *http.Request, key string) ([]int, error) {
+	var values []int
+	for _, raw := range r.URL.Query()[key] {
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' no es un número entero", raw)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// readDeleteOptions lee ?policy=restrict|cascade|detach&dry_run=true. La validación ocurre en el servicio
+func readDeleteOptions(r *http.Request) domain.DeleteOptions {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	return domain.DeleteOptions{
+		Policy: domain.DeletePolicy(r.URL.Query().Get("policy")),
+		DryRun: dryRun,
+	}
+}
+
+// readMergePatch decodifica un cuerpo JSON Merge Patch (application/merge-patch+json).
+// También se acepta application/json para clientes que no envían el tipo específico
+func readMergePatch(w http.ResponseWriter, r *http.Request) (domain.MergePatch, bool) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+			WriteError(w, http.StatusUnsupportedMediaType, "Use Content-Type application/merge-patch+json", nil) // 415
+			return nil, false
+		}
+	}
+
+	var patch domain.MergePatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido, se espera un objeto", err.Error())
+		return nil, false
+	}
+	if patch == nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido, se espera un objeto", nil)
+		return nil, false
+	}
+	return patch, true
+}
+
+// multipartFile recorre el formulario como stream y devuelve la parte del campo indicado,
+// sin pasar por ParseMultipartForm (que copia a disco los archivos grandes)
+func multipartFile(r *http.Request, field string) (io.ReadCloser, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("falta el campo '%s'", field)
+			}
+			return nil, err
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+func TestReadThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *float64
+		wantErr bool
+	}{
+		{name: "sin umbral", query: "", want: nil},
+		{name: "umbral válido", query: "threshold=0.4", want: ptr(0.4)},
+		{name: "no numérico", query: "threshold=abc", wantErr: true},
+		{name: "vacío explícito", query: "threshold=", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/songs?"+tt.query, nil)
+			errs := make(domain.ValidationError)
+			got := readThreshold(r, errs)
+
+			if _, hasErr := errs["threshold"]; hasErr != tt.wantErr {
+				t.Fatalf("errs = %v, se esperaba error: %v", errs, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("readThreshold = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Un threshold inválido en el listado debe llegar al cliente como 400 y no ignorarse
+func TestPaginatedListsRejectInvalidThreshold(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"artists":   (&ArtistHandler{}).GetAllPaginated,
+		"songs":     (&SongHandler{}).GetAllPaginated,
+		"albums":    (&AlbumHandler{}).GetAllPaginated,
+		"playlists": (&PlaylistHandler{}).GetAllPaginated,
+	}
+	for name, handle := range handlers {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handle(w, httptest.NewRequest("GET", "/"+name+"?name=x&threshold=abc", nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, se esperaba 400", w.Code)
+			}
+		})
+	}
+}
+
+// page y limit no numéricos deben dar 400 en todos los listados, no ignorarse
+func TestPaginatedListsRejectInvalidPagination(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"artists":   (&ArtistHandler{}).GetAllPaginated,
+		"songs":     (&SongHandler{}).GetAllPaginated,
+		"albums":    (&AlbumHandler{}).GetAllPaginated,
+		"playlists": (&PlaylistHandler{}).GetAllPaginated,
+		"trash":     (&TrashHandler{}).GetDeleted,
+	}
+	queries := []string{"page=abc", "limit=diez"}
+	for name, handle := range handlers {
+		for _, query := range queries {
+			t.Run(name+"?"+query, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				handle(w, httptest.NewRequest("GET", "/"+name+"?"+query, nil))
+				if w.Code != http.StatusBadRequest {
+					t.Fatalf("status = %d, se esperaba 400", w.Code)
+				}
+			})
+		}
+	}
+}
+
+func ptr(f float64) *float64 { return &f }
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// PlaylistExportHandler entrega tracklists para reproductores en M3U8 extendido o XSPF.
+// El formato se negocia con el header Accept; ?paths=true usa la ruta local conocida del audio
+// como ubicación de cada pista (si no hay ruta, la ubicación es la URL de la canción en la API)
+type PlaylistExportHandler struct {
+	albumService  domain.AlbumService
+	artistService domain.ArtistService
+	songService   domain.SongService
+}
+
+func NewPlaylistExportHandler(albumService domain.AlbumService, artistService domain.ArtistService, songService domain.SongService) *PlaylistExportHandler {
+	return &PlaylistExportHandler{albumService: albumService, artistService: artistService, songService: songService}
+}
+
+// exportItem es una pista ya resuelta, común a ambos formatos
+type exportItem struct {
+	songID      int64
+	title       string
+	artist      string
+	album       string
+	trackNumber int
+	duration    int // Segundos
+	filePath    *string
+	hasAudio    bool
+}
+
+type exportPlaylist struct {
+	title   string
+	creator string
+	items   []exportItem
+}
+
+// Formatos soportados
+const (
+	formatM3U8 = "m3u8"
+	formatXSPF = "xspf"
+)
+
+var exportMediaTypes = map[string]string{
+	"application/vnd.apple.mpegurl": formatM3U8,
+	"application/x-mpegurl":         formatM3U8,
+	"audio/mpegurl":                 formatM3U8,
+	"audio/x-mpegurl":               formatM3U8,
+	"application/xspf+xml":          formatXSPF,
+}
+
+// GET ALBUM (GET /export/albums/{id}?paths=true)
+func (h *PlaylistExportHandler) Album(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateExportFormat(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	album, err := h.albumService.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlbumNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /export/albums/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al generar la playlist del álbum", nil) // 500
+		return
+	}
+
+	var primary []string
+	for _, a := range album.Artists {
+		if a.IsPrimary {
+			primary = append(primary, a.Name)
+		}
+	}
+	playlist := exportPlaylist{title: album.Title, creator: strings.Join(primary, ", ")}
+	for _, t := range album.Tracks {
+		playlist.items = append(playlist.items, exportItem{
+			songID:      t.SongID,
+			title:       t.Title,
+			artist:      artistCredit(t.Artists),
+			album:       album.Title,
+			trackNumber: t.TrackNumber,
+			duration:    t.Duration,
+			filePath:    t.FilePath,
+			hasAudio:    t.HasAudio,
+		})
+	}
+
+	writePlaylistExport(w, r, format, fmt.Sprintf("album-%d", id), playlist)
+}
+
+// GET ARTISTA (GET /export/artists/{id}?paths=true)
+func (h *PlaylistExportHandler) Artist(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateExportFormat(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	artist, err := h.artistService.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrArtistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /export/artists/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al generar la playlist del artista", nil) // 500
+		return
+	}
+	songs, err := h.songService.GetByArtistID(r.Context(), artist.ID)
+	if err != nil {
+		log.Printf("[ERROR INTERNO] GET /export/artists/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al generar la playlist del artista", nil) // 500
+		return
+	}
+
+	playlist := exportPlaylist{title: artist.Name, creator: artist.Name}
+	for _, s := range songs {
+		playlist.items = append(playlist.items, exportItem{
+			songID:   s.ID,
+			title:    s.Title,
+			artist:   artistCredit(s.Artists),
+			duration: s.Duration,
+			filePath: s.FilePath,
+			hasAudio: s.HasAudio,
+		})
+	}
+
+	writePlaylistExport(w, r, format, fmt.Sprintf("artist-%d", id), playlist)
+}
+
+// negotiateExportFormat elige el formato con mayor q del header Accept. Sin Accept o con
+// comodines se usa M3U8; si ningún tipo pedido es soportado responde 406
+func negotiateExportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return formatM3U8, true
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		format, ok := exportMediaTypes[mediaType]
+		if !ok && (mediaType == "*/*" || mediaType == "audio/*" || mediaType == "application/*") {
+			format, ok = formatM3U8, true
+		}
+		if ok && q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+
+	if best == "" {
+		WriteError(w, http.StatusNotAcceptable, "Formato no soportado, use Accept: application/vnd.apple.mpegurl o application/xspf+xml", nil) // 406
+		return "", false
+	}
+	return best, true
+}
+
+func writePlaylistExport(w http.ResponseWriter, r *http.Request, format, filename string, playlist exportPlaylist) {
+	includePaths, _ := strconv.ParseBool(r.URL.Query().Get("paths"))
+	location := func(item exportItem, asURI bool) string {
+		if includePaths && item.filePath != nil {
+			if asURI { // XSPF exige URIs
+				return (&url.URL{Scheme: "file", Path: *item.filePath}).String()
+			}
+			return *item.filePath
+		}
+		return songURL(r, item)
+	}
+
+	w.Header().Set("Vary", "Accept")
+	switch format {
+	case formatXSPF:
+		w.Header().Set("Content-Type", "application/xspf+xml; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.xspf"`, filename))
+		w.WriteHeader(http.StatusOK)
+		writeXSPF(w, playlist, func(item exportItem) string { return location(item, true) })
+	default:
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.m3u8"`, filename))
+		w.WriteHeader(http.StatusOK)
+		writeM3U8(w, playlist, func(item exportItem) string { return location(item, false) })
+	}
+}
+
+// M3U8 extendido: #EXTINF:<segundos>,<Artista> - <Título> seguido de la ubicación
+func writeM3U8(w io.Writer, playlist exportPlaylist, location func(exportItem) string) {
+	fmt.Fprintln(w, "#EXTM3U")
+	fmt.Fprintf(w, "#PLAYLIST:%s\n", oneLine(playlist.title))
+	for _, item := range playlist.items {
+		display := item.title
+		if item.artist != "" {
+			display = item.artist + " - " + item.title
+		}
+		fmt.Fprintf(w, "#EXTINF:%d,%s\n", item.duration, oneLine(display))
+		fmt.Fprintln(w, oneLine(location(item)))
+	}
+}
+
+// Estructura XSPF (http://xspf.org/ns/0/), la duración va en milisegundos
+type xspfPlaylist struct {
+	XMLName   xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version   string      `xml:"version,attr"`
+	Title     string      `xml:"title,omitempty"`
+	Creator   string      `xml:"creator,omitempty"`
+	TrackList []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location,omitempty"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title"`
+	Creator    string `xml:"creator,omitempty"`
+	Album      string `xml:"album,omitempty"`
+	TrackNum   int    `xml:"trackNum,omitempty"`
+	Duration   int    `xml:"duration,omitempty"`
+}
+
+func writeXSPF(w io.Writer, playlist exportPlaylist, location func(exportItem) string) {
+	doc := xspfPlaylist{Version: "1", Title: playlist.title, Creator: playlist.creator, TrackList: []xspfTrack{}}
+	for _, item := range playlist.items {
+		doc.TrackList = append(doc.TrackList, xspfTrack{
+			Location:   location(item),
+			Identifier: fmt.Sprintf("song:%d", item.songID),
+			Title:      item.title,
+			Creator:    item.artist,
+			Album:      item.album,
+			TrackNum:   item.trackNumber,
+			Duration:   item.duration * 1000,
+		})
+	}
+
+	io.WriteString(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		log.Printf("[ERROR INTERNO] escribiendo XSPF: %v\n", err)
+	}
+	io.WriteString(w, "\n")
+}
+
+// artistCredit arma "Principal, Otro feat. Invitado" (los productores no aparecen)
+func artistCredit(artists []domain.ArtistWithRole) string {
+	var main, ft []string
+	for _, a := range artists {
+		switch a.Role {
+		case "main":
+			main = append(main, a.Name)
+		case "ft":
+			ft = append(ft, a.Name)
+		}
+	}
+	credit := strings.Join(main, ", ")
+	if len(ft) > 0 {
+		if credit == "" {
+			return strings.Join(ft, ", ")
+		}
+		credit += " feat. " + strings.Join(ft, ", ")
+	}
+	return credit
+}
+
+// songURL es la ubicación por defecto: el audio de la canción en esta misma API si tiene uno
+// adjunto, o si no su ficha
+func songURL(r *http.Request, item exportItem) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	if item.hasAudio {
+		return fmt.Sprintf("%s://%s/songs/%d/audio", scheme, r.Host, item.songID)
+	}
+	return fmt.Sprintf("%s://%s/songs/%d", scheme, r.Host, item.songID)
+}
+
+// oneLine evita que un título con saltos de línea rompa el formato M3U
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
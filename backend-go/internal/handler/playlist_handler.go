@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type PlaylistHandler struct {
+	service domain.PlaylistService
+}
+
+func NewPlaylistHandler(service domain.PlaylistService) *PlaylistHandler {
+	return &PlaylistHandler{service: service}
+}
+
+// CREATE (POST /playlists)
+func (h *PlaylistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input domain.PlaylistInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	playlist, err := h.service.Create(r.Context(), &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Datos de entrada inválidos", valErrs)
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] POST /playlists: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "No se pudo crear la playlist", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, playlist) // 201
+}
+
+// GET ID (GET /playlists/{id})
+func (h *PlaylistHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		log.Printf("[ERROR en Handler] %v\n", err)
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+	if id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID debe ser mayor a 0", nil)
+		return
+	}
+
+	playlist, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrPlaylistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al buscar la playlist", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, playlist) // 200
+}
+
+// GET ALL PAG (GET /playlists?page=1&limit=10&name=rock&threshold=0.4)
+func (h *PlaylistHandler) GetAllPaginated(w http.ResponseWriter, r *http.Request) {
+	queryErrs := make(domain.ValidationError)
+	pagination := readPagination(r, queryErrs)
+	if len(queryErrs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", queryErrs) // 400
+		return
+	}
+
+	filter := domain.PlaylistFilter{
+		Name: r.URL.Query().Get("name"),
+	}
+
+	paginatedData, err := h.service.GetAllPaginated(r.Context(), filter, pagination)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", valErrs) // 400
+			return
+		}
+		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error obteniendo la lista de playlists", nil)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, paginatedData) // 200
+}
+
+// UPDATE (PUT /playlists/{id})
+func (h *PlaylistHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido", nil)
+		return
+	}
+	if id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID debe ser mayor a 0", nil)
+		return
+	}
+
+	var input domain.PlaylistInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	playlist, err := h.service.Update(r.Context(), id, &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] PUT /playlists/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error actualizando la playlist", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, playlist)
+}
+
+// DELETE (DELETE /playlists/{id})
+func (h *PlaylistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido", nil)
+		return
+	}
+	if id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID debe ser mayor a 0", nil)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrPlaylistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] DELETE /playlists/%d: %v\n", id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al eliminar la playlist", nil) // 500
+		return
+	}
+
+	WriteNoContent(w)
+}
+
+// Otros
+
+// Agregar cancion a playlist (POST /playlists/{id}/entries)
+func (h *PlaylistHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || playlistID <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la playlist debe ser un entero válido mayor a 0", nil)
+		return
+	}
+
+	var input domain.PlaylistEntryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	entry, err := h.service.AddEntry(r.Context(), playlistID, &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Datos de entrada inválidos", valErrs)
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+		if errors.Is(err, domain.ErrSongNotInDB) {
+			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] POST /playlists/%d/entries: %v\n", playlistID, err)
+		WriteError(w, http.StatusInternalServerError, "Error al agregar la canción a la playlist", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, entry) // 201
+}
+
+// Mover entrada de posicion (PUT /playlists/{id}/entries/{entry_id})
+func (h *PlaylistHandler) MoveEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	entryID, err2 := strconv.ParseInt(r.PathValue("entry_id"), 10, 64)
+
+	if err != nil || err2 != nil || playlistID <= 0 || entryID <= 0 {
+		WriteError(w, http.StatusBadRequest, "Los IDs de la URL deben ser válidos", nil)
+		return
+	}
+
+	var input domain.PlaylistMoveInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, http.StatusBadRequest, "Formato JSON inválido", err.Error())
+		return
+	}
+
+	err = h.service.MoveEntry(r.Context(), playlistID, entryID, &input)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Datos de entrada inválidos", valErrs)
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistNotFound) || errors.Is(err, domain.ErrPlaylistEntryNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] PUT /playlists/%d/entries/%d: %v\n", playlistID, entryID, err)
+		WriteError(w, http.StatusInternalServerError, "Error al mover la canción en la playlist", nil) // 500
+		return
+	}
+
+	WriteMessageJSON(w, http.StatusOK, "Canción movida exitosamente") // 200
+}
+
+// DELETE (/playlists/{id}/entries/{entry_id})
+func (h *PlaylistHandler) RemoveEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	entryID, err2 := strconv.ParseInt(r.PathValue("entry_id"), 10, 64)
+
+	if err != nil || err2 != nil || playlistID <= 0 || entryID <= 0 {
+		WriteError(w, http.StatusBadRequest, "Los IDs de la URL deben ser válidos", nil)
+		return
+	}
+
+	err = h.service.RemoveEntry(r.Context(), playlistID, entryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPlaylistNotFound) || errors.Is(err, domain.ErrPlaylistEntryNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+			return
+		}
+
+		log.Printf("[ERROR INTERNO] DELETE /playlists/%d/entries/%d: %v\n", playlistID, entryID, err)
+		WriteError(w, http.StatusInternalServerError, "Error al remover la canción de la playlist", nil) // 500
+		return
+	}
+
+	WriteNoContent(w) // 204
+}
@@ -2,48 +2,113 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
 	"github.com/IsaacEspinoza91/Song-Manager/internal/middleware"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/openapi"
 )
 
+// audioIdleTimeout corta una subida o descarga de audio que lleva ese tiempo sin avanzar
+const audioIdleTimeout = 30 * time.Second
+
 // NewRouter recibe TODOS los servicios y retorna un http.Handler listo para usar
-func NewRouter(artistService domain.ArtistService, songService domain.SongService, albumService domain.AlbumService) http.Handler {
+func NewRouter(artistService domain.ArtistService, songService domain.SongService, albumService domain.AlbumService, playlistService domain.PlaylistService, trashService domain.TrashService, duplicateService domain.DuplicateService, importService domain.ImportService, imageService domain.ImageService, blobStore domain.BlobStore, audioService domain.AudioService, searchService domain.SearchService, spec *openapi.Spec, validateRequests bool) http.Handler {
 	mux := http.NewServeMux()
 
 	// Instanciar los handlers específicos inyectándoles su servicio correspondiente
 	artistHandler := NewArtistHandler(artistService)
 	songHandler := NewSongHandler(songService)
 	albumHandler := NewAlbumHandler(albumService)
+	playlistHandler := NewPlaylistHandler(playlistService)
+	trashHandler := NewTrashHandler(trashService)
+	duplicateHandler := NewDuplicateHandler(duplicateService)
+	importHandler := NewImportHandler(importService)
+	playlistExportHandler := NewPlaylistExportHandler(albumService, artistService, songService)
+	mediaHandler := NewMediaHandler(imageService, blobStore)
+	audioHandler := NewAudioHandler(audioService)
+	searchHandler := NewSearchHandler(searchService)
+	docsHandler := NewDocsHandler(spec)
+
+	// Registramos las rutas (Requiere Go 1.22+). Cada ruta nueva debe agregarse también a openapi.json
+
+	// Contrato OpenAPI y documentación
+	mux.HandleFunc("GET /openapi.json", docsHandler.Spec)
+	mux.HandleFunc("GET /docs", docsHandler.Docs)
 
-	// Registramos las rutas (Requiere Go 1.22+)
 	mux.HandleFunc("POST /artists", artistHandler.Create)
 	mux.HandleFunc("GET /artists/all", artistHandler.GetAll)
 	mux.HandleFunc("GET /artists", artistHandler.GetAllPaginated)
 	mux.HandleFunc("GET /artists/{id}", artistHandler.GetByID)
 	mux.HandleFunc("PUT /artists/{id}", artistHandler.Update)
+	mux.HandleFunc("PATCH /artists/{id}", artistHandler.Patch)
 	mux.HandleFunc("DELETE /artists/{id}", artistHandler.Delete)
 	mux.HandleFunc("GET /artists/search", artistHandler.SearchArtists)
+	mux.HandleFunc("POST /artists/{id}/merge", artistHandler.Merge)
 
 	mux.HandleFunc("POST /songs", songHandler.Create)
 	mux.HandleFunc("GET /songs/{id}", songHandler.GetByID)
 	mux.HandleFunc("GET /songs/all", songHandler.GetAll)
 	mux.HandleFunc("GET /songs", songHandler.GetAllPaginated)
 	mux.HandleFunc("PUT /songs/{id}", songHandler.Update)
+	mux.HandleFunc("PATCH /songs/{id}", songHandler.Patch)
 	mux.HandleFunc("DELETE /songs/{id}", songHandler.Delete)
 	mux.HandleFunc("DELETE /songs/{id}/artist/{artist_id}", songHandler.RemoveArtist)
 	mux.HandleFunc("POST /songs/{id}/artist", songHandler.AddArtist)
 	mux.HandleFunc("GET /songs/search", songHandler.SearchSongs)
 
+	// Audio de canciones. Las transferencias pueden superar el WriteTimeout del servidor,
+	// por eso usan un plazo de inactividad propio en vez del límite global
+	mux.HandleFunc("PUT /songs/{id}/audio", middleware.Stream(audioIdleTimeout, audioHandler.Upload))
+	mux.HandleFunc("GET /songs/{id}/audio", middleware.Stream(audioIdleTimeout, audioHandler.Stream))
+	mux.HandleFunc("DELETE /songs/{id}/audio", audioHandler.Delete)
+
 	mux.HandleFunc("POST /albums", albumHandler.Create)
 	mux.HandleFunc("GET /albums/{id}", albumHandler.GetByID)
 	mux.HandleFunc("GET /albums", albumHandler.GetAllPaginated)
 	mux.HandleFunc("GET /albums/artist/{artist_id}", albumHandler.GetAlbumsByArtistID)
 	mux.HandleFunc("PUT /albums/{id}", albumHandler.Update)
+	mux.HandleFunc("PATCH /albums/{id}", albumHandler.Patch)
 	mux.HandleFunc("DELETE /albums/{id}", albumHandler.Delete)
 	mux.HandleFunc("POST /albums/{id}/tracks", albumHandler.AddTrack)
+	mux.HandleFunc("PUT /albums/{id}/tracks", albumHandler.ReorderTracks)
 	mux.HandleFunc("DELETE /albums/{id}/tracks/{song_id}", albumHandler.RemoveTrack)
 
+	// Tracklists para reproductores (M3U8 / XSPF según Accept). Van bajo /export porque
+	// GET /albums/{id}/... choca con GET /albums/artist/{artist_id} en el mux
+	mux.HandleFunc("GET /export/albums/{id}", playlistExportHandler.Album)
+	mux.HandleFunc("GET /export/artists/{id}", playlistExportHandler.Artist)
+
+	// Imágenes subidas (carátulas y fotos de artistas) y archivos del blob store
+	mux.HandleFunc("POST /albums/{id}/cover", mediaHandler.UploadAlbumCover)
+	mux.HandleFunc("POST /artists/{id}/image", mediaHandler.UploadArtistImage)
+	mux.HandleFunc("GET /media/{key...}", mediaHandler.Serve)
+
+	mux.HandleFunc("POST /playlists", playlistHandler.Create)
+	mux.HandleFunc("GET /playlists/{id}", playlistHandler.GetByID)
+	mux.HandleFunc("GET /playlists", playlistHandler.GetAllPaginated)
+	mux.HandleFunc("PUT /playlists/{id}", playlistHandler.Update)
+	mux.HandleFunc("DELETE /playlists/{id}", playlistHandler.Delete)
+	mux.HandleFunc("POST /playlists/{id}/entries", playlistHandler.AddEntry)
+	mux.HandleFunc("PUT /playlists/{id}/entries/{entry_id}", playlistHandler.MoveEntry)
+	mux.HandleFunc("DELETE /playlists/{id}/entries/{entry_id}", playlistHandler.RemoveEntry)
+
+	// Papelera: {entity} = artists | songs | albums
+	mux.HandleFunc("GET /trash/{entity}", trashHandler.GetDeleted)
+	mux.HandleFunc("POST /trash/{entity}/{id}/restore", trashHandler.Restore)
+	mux.HandleFunc("DELETE /trash/{entity}/{id}", trashHandler.Purge)
+	mux.HandleFunc("DELETE /trash", trashHandler.PurgeOlderThan)
+
+	// Búsqueda unificada (artistas, canciones y álbumes en paralelo)
+	mux.HandleFunc("GET /search", searchHandler.Search)
+
+	// Reporte de posibles duplicados
+	mux.HandleFunc("GET /duplicates/songs", duplicateHandler.FindSongs)
+	mux.HandleFunc("GET /duplicates/artists", duplicateHandler.FindArtists)
+
+	// Importación masiva de catálogo
+	mux.HandleFunc("POST /import/csv", importHandler.ImportCSV)
+
 	// Middleware
 
 	// El orden importa:
@@ -51,9 +116,14 @@ func NewRouter(artistService domain.ArtistService, songService domain.SongServic
 	// Luego el CORS revisa los permisos.
 	// Rate Limiting. Contra ataques masivos de una IP
 	// Recovery en caso de panic
+	// Validación contra el contrato OpenAPI (opcional)
 	// Finalmente, llega al Mux (enrutador).
 
-	handlerConCORS := middleware.CORS(mux)
+	var handlerBase http.Handler = mux
+	if validateRequests {
+		handlerBase = middleware.ValidateRequests(spec, mux)
+	}
+	handlerConCORS := middleware.CORS(handlerBase)
 	handlerConLogger := middleware.Logger(handlerConCORS)
 	handlerConRateLimit := middleware.RateLimit(handlerConLogger)
 	handlerFinal := middleware.Recovery(handlerConRateLimit)
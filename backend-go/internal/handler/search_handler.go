@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// SearchHandler expone la búsqueda unificada de artistas, canciones y álbumes
+type SearchHandler struct {
+	service domain.SearchService
+}
+
+func NewSearchHandler(service domain.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// SEARCH (GET /search?q=soda&limit=10&types=artist,album&timeout_ms=500)
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := domain.SearchOptions{Query: q.Get("q")}
+	errs := make(domain.ValidationError)
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			errs["limit"] = "debe ser un número entero"
+		}
+		opts.Limit = limit
+	}
+	if v := q.Get("timeout_ms"); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			errs["timeout_ms"] = "debe ser un número entero de milisegundos"
+		}
+		opts.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	if v := q.Get("types"); v != "" {
+		opts.Types = strings.Split(v, ",")
+	}
+	if len(errs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros inválidos", errs)
+		return
+	}
+
+	result, err := h.service.Search(r.Context(), opts)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de búsqueda inválidos", valErrs) // 400
+			return
+		}
+		log.Printf("[ERROR INTERNO] GET /search: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al realizar la búsqueda", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, result) // 200
+}
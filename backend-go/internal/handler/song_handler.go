@@ -76,6 +76,7 @@ func (h *SongHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, song.UpdatedAt)
 	WriteJSON(w, http.StatusOK, song) // 200
 }
 
@@ -95,20 +96,12 @@ func (h *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET ALL PAG (GET /songs?page=1&limit=10&artist_id=1&artist_name=shakira&name=sordo)
+// Modo cursor: GET /songs?cursor=&limit=20, luego ?cursor=<next_cursor>
+// Orden: GET /songs?sort=-duration,title (ver la allowlist en el repositorio)
 func (h *SongHandler) GetAllPaginated(w http.ResponseWriter, r *http.Request) {
 	// Extraer query params
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 {
-		limit = 10
-	}
-	pagination := domain.PaginationParams{
-		Page:  page,
-		Limit: limit,
-	}
+	queryErrs := make(domain.ValidationError)
+	pagination := readPagination(r, queryErrs)
 
 	// Extraemos el ID numérico si viene en la query
 	var artistaID int64
@@ -120,9 +113,21 @@ func (h *SongHandler) GetAllPaginated(w http.ResponseWriter, r *http.Request) {
 		ArtistID:   artistaID,
 		ArtistName: r.URL.Query().Get("artist_name"),
 	}
+	// Rangos opcionales: duración en segundos y fecha de creación
+	filter.Duration = readIntRange(r, "duration_min", "duration_max", queryErrs)
+	filter.Created = readTimeRange(r, "created_from", "created_to", queryErrs)
+	if len(queryErrs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", queryErrs) // 400
+		return
+	}
 
 	paginatedData, err := h.service.GetAllPaginated(r.Context(), filter, pagination)
 	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", valErrs) // 400
+			return
+		}
 		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
 		WriteError(w, http.StatusInternalServerError, "Error obteniendo la lista de canciones", nil)
 		return
@@ -167,32 +172,65 @@ func (h *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	song, err := h.service.Update(r.Context(), id, &input)
+	song, err := h.service.Update(r.Context(), id, &input, readIfMatch(r))
 	if err != nil {
-		// Errores de validacion
-		var valErrs domain.ValidationError
-		if errors.As(err, &valErrs) {
-			WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
-			return
-		}
+		h.writeUpdateError(w, r, id, err)
+		return
+	}
 
-		// Errores de Negocio / Relaciones
-		if errors.Is(err, domain.ErrArtistNotFound) {
-			WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
-			return
-		}
+	setETag(w, song.UpdatedAt)
+	WriteJSON(w, http.StatusOK, song)
+}
 
-		if errors.Is(err, domain.ErrSongNotFound) {
-			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
-			return
-		}
+// PATCH (PATCH /songs/{id}) con JSON Merge Patch. "artists" reemplaza la lista completa
+func (h *SongHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
 
-		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
-		WriteError(w, http.StatusInternalServerError, "Error actualizando la cancion", nil) // 500
+	patch, ok := readMergePatch(w, r)
+	if !ok {
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, song)
+	song, err := h.service.Patch(r.Context(), id, patch, readIfMatch(r))
+	if err != nil {
+		h.writeUpdateError(w, r, id, err)
+		return
+	}
+
+	setETag(w, song.UpdatedAt)
+	WriteJSON(w, http.StatusOK, song) // 200
+}
+
+// writeUpdateError traduce los errores de PUT y PATCH a su código HTTP
+func (h *SongHandler) writeUpdateError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	// Errores de validacion
+	var valErrs domain.ValidationError
+	if errors.As(err, &valErrs) {
+		WriteError(w, http.StatusBadRequest, "Datos de actualización inválidos", valErrs)
+		return
+	}
+
+	// Errores de Negocio / Relaciones
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		WriteError(w, http.StatusBadRequest, err.Error(), nil) // 400
+		return
+	}
+
+	if errors.Is(err, domain.ErrSongNotFound) {
+		WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
+		return
+	}
+	if errors.Is(err, domain.ErrPreconditionFailed) {
+		h.writeStale(w, r, id, err) // 412
+		return
+	}
+
+	log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+	WriteError(w, http.StatusInternalServerError, "Error actualizando la cancion", nil) // 500
 }
 
 // DELETE (DELETE /songs/{id})
@@ -209,12 +247,16 @@ func (h *SongHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Delete(r.Context(), id)
+	err = h.service.Delete(r.Context(), id, readIfMatch(r))
 	if err != nil {
 		if errors.Is(err, domain.ErrSongNotFound) {
 			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404
 			return
 		}
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			h.writeStale(w, r, id, err) // 412
+			return
+		}
 		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
 		WriteError(w, http.StatusInternalServerError, "Error al eliminar la cancion", nil) // 500
 		return
@@ -288,3 +330,20 @@ func (h *SongHandler) RemoveArtist(w http.ResponseWriter, r *http.Request) {
 
 	WriteNoContent(w)
 }
+
+// writeStale responde 412 con la representación y el ETag actuales de la canción
+func (h *SongHandler) writeStale(w http.ResponseWriter, r *http.Request, id int64, staleErr error) {
+	current, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSongNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error(), nil) // 404, se eliminó entre medio
+			return
+		}
+		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al buscar la cancion", nil) // 500
+		return
+	}
+
+	setETag(w, current.UpdatedAt)
+	WriteError(w, http.StatusPreconditionFailed, staleErr.Error(), current)
+}
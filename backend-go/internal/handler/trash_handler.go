@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// TrashHandler expone la papelera: registros con soft delete de artistas, canciones y álbumes.
+// La entidad viaja en la ruta ({entity} = artists | songs | albums)
+type TrashHandler struct {
+	service domain.TrashService
+}
+
+func NewTrashHandler(service domain.TrashService) *TrashHandler {
+	return &TrashHandler{service: service}
+}
+
+// GET (GET /trash/{entity}?page=1&limit=10)
+func (h *TrashHandler) GetDeleted(w http.ResponseWriter, r *http.Request) {
+	queryErrs := make(domain.ValidationError)
+	pagination := readPagination(r, queryErrs)
+	if len(queryErrs) > 0 {
+		WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", queryErrs) // 400
+		return
+	}
+
+	var data interface{}
+	var err error
+	switch r.PathValue("entity") {
+	case "artists":
+		data, err = h.service.GetDeletedArtists(r.Context(), pagination)
+	case "songs":
+		data, err = h.service.GetDeletedSongs(r.Context(), pagination)
+	case "albums":
+		data, err = h.service.GetDeletedAlbums(r.Context(), pagination)
+	default:
+		WriteError(w, http.StatusNotFound, "Entidad desconocida, use artists, songs o albums", nil)
+		return
+	}
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros de consulta inválidos", valErrs) // 400
+			return
+		}
+		log.Printf("[ERROR INTERNO en Handler] %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error obteniendo la papelera", nil)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, data) // 200
+}
+
+// RESTORE (POST /trash/{entity}/{id}/restore)
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	var restored interface{}
+	switch r.PathValue("entity") {
+	case "artists":
+		restored, err = h.service.RestoreArtist(r.Context(), id)
+	case "songs":
+		restored, err = h.service.RestoreSong(r.Context(), id)
+	case "albums":
+		restored, err = h.service.RestoreAlbum(r.Context(), id)
+	default:
+		WriteError(w, http.StatusNotFound, "Entidad desconocida, use artists, songs o albums", nil)
+		return
+	}
+	if err != nil {
+		if isTrashNotFound(err) {
+			WriteError(w, http.StatusNotFound, "El registro no existe en la papelera", nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] POST /trash/%s/%d/restore: %v\n", r.PathValue("entity"), id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al restaurar el registro", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, restored) // 200
+}
+
+// PURGE uno (DELETE /trash/{entity}/{id})
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, "El ID de la URL debe ser un número entero válido mayor a 0", nil)
+		return
+	}
+
+	switch r.PathValue("entity") {
+	case "artists":
+		err = h.service.PurgeArtist(r.Context(), id)
+	case "songs":
+		err = h.service.PurgeSong(r.Context(), id)
+	case "albums":
+		err = h.service.PurgeAlbum(r.Context(), id)
+	default:
+		WriteError(w, http.StatusNotFound, "Entidad desconocida, use artists, songs o albums", nil)
+		return
+	}
+	if err != nil {
+		if isTrashNotFound(err) {
+			WriteError(w, http.StatusNotFound, "El registro no existe en la papelera", nil) // 404
+			return
+		}
+		log.Printf("[ERROR INTERNO] DELETE /trash/%s/%d: %v\n", r.PathValue("entity"), id, err)
+		WriteError(w, http.StatusInternalServerError, "Error al purgar el registro", nil) // 500
+		return
+	}
+
+	WriteNoContent(w) // 204
+}
+
+// PURGE por antigüedad (DELETE /trash?older_than_days=30)
+func (h *TrashHandler) PurgeOlderThan(w http.ResponseWriter, r *http.Request) {
+	days, err := strconv.Atoi(r.URL.Query().Get("older_than_days"))
+	if err != nil || days < 0 {
+		WriteError(w, http.StatusBadRequest, "El parámetro older_than_days es obligatorio y debe ser un entero mayor o igual a 0", nil)
+		return
+	}
+
+	report, err := h.service.PurgeOlderThan(r.Context(), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			WriteError(w, http.StatusBadRequest, "Parámetros inválidos", valErrs)
+			return
+		}
+		log.Printf("[ERROR INTERNO] DELETE /trash: %v\n", err)
+		WriteError(w, http.StatusInternalServerError, "Error al vaciar la papelera", nil) // 500
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, report) // 200
+}
+
+func isTrashNotFound(err error) bool {
+	return errors.Is(err, domain.ErrArtistNotFound) ||
+		errors.Is(err, domain.ErrSongNotFound) ||
+		errors.Is(err, domain.ErrAlbumNotFound)
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+/*
+PurgeJob vacía periódicamente la papelera, eliminando de forma definitiva los registros
+con soft delete más antiguos que el periodo de retención.
+Corre en su propia goroutine; main lo inicia junto al servidor y lo detiene en el Graceful Shutdown.
+*/
+type PurgeJob struct {
+	service   domain.TrashService
+	interval  time.Duration
+	retention time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewPurgeJob(service domain.TrashService, interval, retention time.Duration) *PurgeJob {
+	return &PurgeJob{
+		service:   service,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Start lanza la goroutine. Ejecuta una purga inmediata y luego una por cada intervalo
+func (j *PurgeJob) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Job de purga iniciado (cada %v, retención %v)\n", j.interval, j.retention)
+}
+
+// Stop cancela la purga en curso y espera a que la goroutine termine, o a que expire ctx
+func (j *PurgeJob) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil // Nunca se inició
+	}
+	j.cancel()
+
+	select {
+	case <-j.done:
+		log.Println("Job de purga detenido correctamente.")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *PurgeJob) run(ctx context.Context) {
+	report, err := j.service.PurgeOlderThan(ctx, j.retention)
+	if err != nil {
+		if ctx.Err() == nil { // Ignorar errores provocados por el apagado
+			log.Printf("[ERROR] Job de purga: %v\n", err)
+		}
+		return
+	}
+	if report.Artists+report.Songs+report.Albums > 0 {
+		log.Printf("[PURGA] Eliminados definitivamente: %d artistas, %d canciones, %d álbumes\n",
+			report.Artists, report.Songs, report.Albums)
+	}
+}
@@ -35,8 +35,9 @@ func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 1. Cabeceras de permiso
 		w.Header().Set("Access-Control-Allow-Origin", "*") // En producción, cambiar "*" por dominio
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, Range")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Content-Range, Accept-Ranges") // ETag: el frontend lo reenvía en If-Match
 
 		// 2. Manejo del "Preflight Request"
 		// Los navegadores envían una petición OPTIONS antes de un POST/PUT para ver si tienen permiso.
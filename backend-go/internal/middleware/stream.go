@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+/*
+Timeouts de rutas de streaming. El servidor usa ReadTimeout y WriteTimeout de 10s para toda la
+petición, lo que corta cualquier descarga o subida de audio más larga. Stream reemplaza ese límite
+por uno de inactividad: cada lectura o escritura renueva el plazo, asi una transferencia activa
+puede durar lo necesario pero un cliente detenido igual se corta.
+*/
+
+// Stream aplica el plazo idle a lecturas del body y escrituras de la respuesta de next
+func Stream(idle time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		// Si el ResponseWriter no soporta plazos se sigue con los del servidor
+		if rc.SetWriteDeadline(time.Now().Add(idle)) != nil {
+			next(w, r)
+			return
+		}
+		rc.SetReadDeadline(time.Now().Add(idle))
+
+		r.Body = &deadlineReader{ReadCloser: r.Body, rc: rc, idle: idle}
+		next(&deadlineWriter{ResponseWriter: w, rc: rc, idle: idle}, r)
+	}
+}
+
+type deadlineWriter struct {
+	http.ResponseWriter
+	rc   *http.ResponseController
+	idle time.Duration
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	w.rc.SetWriteDeadline(time.Now().Add(w.idle))
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap permite que http.ResponseController (Flush, etc.) llegue al writer original
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type deadlineReader struct {
+	io.ReadCloser
+	rc   *http.ResponseController
+	idle time.Duration
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	r.rc.SetReadDeadline(time.Now().Add(r.idle))
+	return r.ReadCloser.Read(p)
+}
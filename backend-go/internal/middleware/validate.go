@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/openapi"
+)
+
+// ValidateRequests rechaza las peticiones que no cumplen el contrato OpenAPI antes de llegar a los
+// handlers. Es opcional (VALIDATE_REQUESTS=true); el formato de error es el mismo APIError de la API
+func ValidateRequests(spec *openapi.Spec, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := spec.ValidateRequest(r)
+		if err == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var valErrs domain.ValidationError
+		switch {
+		case errors.As(err, &valErrs):
+			writeContractError(w, http.StatusBadRequest, "La petición no cumple el contrato de la API", valErrs) // 400
+		case errors.Is(err, openapi.ErrUnsupportedMedia):
+			writeContractError(w, http.StatusUnsupportedMediaType, err.Error(), nil) // 415
+		case errors.Is(err, openapi.ErrBodyTooLarge):
+			writeContractError(w, http.StatusRequestEntityTooLarge, err.Error(), nil) // 413
+		default:
+			log.Printf("[ERROR INTERNO] validando %s %s: %v\n", r.Method, r.URL.Path, err)
+			writeContractError(w, http.StatusInternalServerError, "Error al validar la petición", nil) // 500
+		}
+	})
+}
+
+func writeContractError(w http.ResponseWriter, status int, message string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	body := map[string]interface{}{
+		"status":  status,
+		"message": message,
+	}
+	if details != nil {
+		body["details"] = details
+	}
+	json.NewEncoder(w).Encode(body)
+}
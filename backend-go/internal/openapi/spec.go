@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+/*
+Contrato OpenAPI 3.1 de la API. El documento (openapi.json) se mantiene a mano junto a las rutas de
+handler.NewRouter y se embebe en el binario: se sirve en /openapi.json, lo usa la página /docs y,
+si se activa, el middleware que valida las peticiones antes de llegar a los handlers.
+
+Para validar solo se interpreta el subconjunto de JSON Schema que usa el documento: type (con "null"),
+properties, required, additionalProperties, items, enum, minimum/maximum, minLength/maxLength,
+minItems/maxItems, format date, allOf/oneOf y $ref locales.
+*/
+
+//go:embed openapi.json
+var specJSON []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// Spec es el documento ya interpretado, con las rutas listas para buscar la operación de una petición
+type Spec struct {
+	raw        []byte
+	components components
+	routes     []*route
+}
+
+type document struct {
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*pathItem `json:"paths"`
+	Components components           `json:"components"`
+}
+
+type components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+}
+
+type pathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Post       *Operation   `json:"post"`
+	Put        *Operation   `json:"put"`
+	Patch      *Operation   `json:"patch"`
+	Delete     *Operation   `json:"delete"`
+}
+
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []*Parameter `json:"parameters"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // path | query | header
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// route es un path del documento separado en segmentos; "{x}" es un parámetro
+type route struct {
+	template   string
+	segments   []string
+	literals   int
+	operations map[string]*Operation // Por método HTTP
+}
+
+// Load interpreta el documento embebido y resuelve las referencias a parámetros
+func Load() (*Spec, error) {
+	var doc document
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		return nil, fmt.Errorf("openapi.json inválido: %w", err)
+	}
+	spec := &Spec{raw: specJSON, components: doc.Components}
+
+	for template, item := range doc.Paths {
+		rt := &route{template: template, operations: make(map[string]*Operation)}
+		for _, seg := range strings.Split(strings.Trim(template, "/"), "/") {
+			rt.segments = append(rt.segments, seg)
+			if !isParam(seg) {
+				rt.literals++
+			}
+		}
+
+		methods := map[string]*Operation{
+			http.MethodGet: item.Get, http.MethodPost: item.Post, http.MethodPut: item.Put,
+			http.MethodPatch: item.Patch, http.MethodDelete: item.Delete,
+		}
+		for method, op := range methods {
+			if op == nil {
+				continue
+			}
+			// Los parámetros del path aplican a todas sus operaciones
+			params := append(append([]*Parameter{}, item.Parameters...), op.Parameters...)
+			for i, p := range params {
+				resolved, err := spec.parameter(p)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, template, err)
+				}
+				params[i] = resolved
+			}
+			op.Parameters = params
+			rt.operations[method] = op
+		}
+		spec.routes = append(spec.routes, rt)
+	}
+
+	// Las rutas con más segmentos literales ganan: /artists/search antes que /artists/{id}
+	sort.Slice(spec.routes, func(i, j int) bool {
+		if spec.routes[i].literals != spec.routes[j].literals {
+			return spec.routes[i].literals > spec.routes[j].literals
+		}
+		return spec.routes[i].template < spec.routes[j].template
+	})
+	return spec, nil
+}
+
+// JSON es el documento tal como se publica en /openapi.json
+func (s *Spec) JSON() []byte {
+	return s.raw
+}
+
+// DocsHTML es la página de documentación, carga el documento desde /openapi.json
+func DocsHTML() []byte {
+	return docsHTML
+}
+
+// Operation busca la operación que corresponde a la petición y los valores de sus parámetros de ruta.
+// Devuelve nil si la ruta o el método no están documentados
+func (s *Spec) Operation(method, path string) (*Operation, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, rt := range s.routes {
+		values, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if op := rt.operations[method]; op != nil {
+			return op, values
+		}
+	}
+	return nil, nil
+}
+
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	values := make(map[string]string)
+	for i, seg := range rt.segments {
+		if isParam(seg) {
+			if segments[i] == "" {
+				return nil, false
+			}
+			values[seg[1:len(seg)-1]] = segments[i]
+		} else if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/")
+	if !ok || s.components.Parameters[name] == nil {
+		return nil, fmt.Errorf("referencia desconocida %s", p.Ref)
+	}
+	return s.components.Parameters[name], nil
+}
+
+func (s *Spec) schema(sch *Schema) (*Schema, error) {
+	for sch != nil && sch.Ref != "" {
+		name, ok := strings.CutPrefix(sch.Ref, "#/components/schemas/")
+		if !ok || s.components.Schemas[name] == nil {
+			return nil, fmt.Errorf("referencia desconocida %s", sch.Ref)
+		}
+		sch = s.components.Schemas[name]
+	}
+	return sch, nil
+}
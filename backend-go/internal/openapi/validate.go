@@ -0,0 +1,379 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Tamaño máximo de un cuerpo JSON que se valida (los archivos no se leen, solo se revisa su Content-Type)
+const MaxJSONBody = 1 << 20 // 1 MB
+
+var (
+	ErrBodyTooLarge     = errors.New("el cuerpo JSON supera el tamaño máximo de 1 MB")
+	ErrUnsupportedMedia = errors.New("content-type no soportado por esta ruta")
+)
+
+// Schema es el subconjunto de JSON Schema que usa el documento
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	AllOf                []*Schema          `json:"allOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+}
+
+// schemaType acepta "type": "string" y "type": ["string", "null"]
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaType{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+func (t schemaType) allows(name string) bool {
+	for _, v := range t {
+		if v == name || (name == "integer" && v == "number") {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRequest revisa parámetros de ruta, query y cuerpo contra la operación documentada.
+// Los errores de contrato vuelven como domain.ValidationError (campo -> mensaje); ErrBodyTooLarge y
+// ErrUnsupportedMedia se informan aparte. El cuerpo JSON leído se repone en r.Body para el handler.
+// Las rutas no documentadas se dejan pasar, el mux responde 404/405
+func (s *Spec) ValidateRequest(r *http.Request) error {
+	op, pathValues := s.Operation(r.Method, r.URL.Path)
+	if op == nil {
+		return nil
+	}
+	errs := make(domain.ValidationError)
+
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var value string
+		switch p.In {
+		case "path":
+			value = pathValues[p.Name]
+		case "query":
+			value = query.Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+		default:
+			continue
+		}
+		field := p.In + "." + p.Name
+		// Query params repetibles (?genre=Rock&genre=Pop): se valida cada valor contra items
+		if p.In == "query" {
+			handled, err := s.validateArrayParam(field, query[p.Name], p.Schema, errs)
+			if err != nil {
+				return err
+			}
+			if handled {
+				continue
+			}
+		}
+		// Un parámetro vacío (?artist_id=) cuenta como ausente, igual que en los handlers
+		if value == "" {
+			if p.Required {
+				errs[field] = "es obligatorio"
+			}
+			continue
+		}
+		if err := s.validateParam(field, value, p.Schema, errs); err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody != nil {
+		if err := s.validateBody(r, op.RequestBody, errs); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateArrayParam valida un parámetro de tipo array. handled es false si el schema no es un array
+func (s *Spec) validateArrayParam(field string, values []string, sch *Schema, errs domain.ValidationError) (handled bool, err error) {
+	sch, err = s.schema(sch)
+	if err != nil || sch == nil || !sch.Type.allows("array") || sch.Items == nil {
+		return false, err
+	}
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := s.validateParam(field, value, sch.Items, errs); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// validateParam convierte el texto al tipo del schema antes de validarlo
+func (s *Spec) validateParam(field, value string, sch *Schema, errs domain.ValidationError) error {
+	sch, err := s.schema(sch)
+	if err != nil || sch == nil {
+		return err
+	}
+	var parsed any = value
+	switch {
+	case sch.Type.allows("integer") && !sch.Type.allows("number"):
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs[field] = "debe ser un número entero"
+			return nil
+		}
+		parsed = json.Number(strconv.FormatInt(n, 10))
+	case sch.Type.allows("number"):
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			errs[field] = "debe ser un número"
+			return nil
+		}
+		parsed = json.Number(value)
+	case sch.Type.allows("boolean"):
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			errs[field] = "debe ser true o false"
+			return nil
+		}
+		parsed = b
+	}
+	return s.validateValue(field, parsed, sch, errs)
+}
+
+func (s *Spec) validateBody(r *http.Request, body *RequestBody, errs domain.ValidationError) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		if body.Required {
+			errs["body"] = "es obligatorio"
+		}
+		return nil
+	}
+
+	// Sin Content-Type se asume JSON, los handlers lo decodifican igual
+	mediaType := "application/json"
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		parsed, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return ErrUnsupportedMedia
+		}
+		mediaType = parsed
+	}
+	content, ok := matchContent(body.Content, mediaType)
+	if !ok {
+		return ErrUnsupportedMedia
+	}
+	// Archivos (multipart, CSV, audio) se dejan al handler, que los lee como stream
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, MaxJSONBody+1))
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxJSONBody {
+		return ErrBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		errs["body"] = "JSON inválido: " + err.Error()
+		return nil
+	}
+	return s.validateValue("body", value, content.Schema, errs)
+}
+
+// matchContent busca el media type exacto o un comodín del documento (audio/*)
+func matchContent(content map[string]mediaType, mediaType string) (mediaType, bool) {
+	if c, ok := content[mediaType]; ok {
+		return c, true
+	}
+	if major, _, ok := strings.Cut(mediaType, "/"); ok {
+		if c, ok := content[major+"/*"]; ok {
+			return c, true
+		}
+	}
+	c, ok := content["*/*"]
+	return c, ok
+}
+
+// validateValue valida un valor decodificado (números como json.Number). Solo se guarda el primer
+// error de cada campo, en el formato de ValidationError ("body.artists[0].role")
+func (s *Spec) validateValue(field string, value any, sch *Schema, errs domain.ValidationError) error {
+	sch, err := s.schema(sch)
+	if err != nil || sch == nil {
+		return err
+	}
+
+	for _, sub := range sch.AllOf {
+		if err := s.validateValue(field, value, sub, errs); err != nil {
+			return err
+		}
+	}
+	if len(sch.OneOf) > 0 {
+		matches := 0
+		for _, sub := range sch.OneOf {
+			tmp := make(domain.ValidationError)
+			if err := s.validateValue(field, value, sub, tmp); err != nil {
+				return err
+			}
+			if len(tmp) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			errs[field] = "no coincide con ninguna de las alternativas permitidas"
+			return nil
+		}
+	}
+
+	if len(sch.Type) > 0 && !sch.Type.allows(jsonType(value)) {
+		errs[field] = "debe ser de tipo " + strings.Join(sch.Type, " o ")
+		return nil
+	}
+	if len(sch.Enum) > 0 && !inEnum(value, sch.Enum) {
+		errs[field] = "debe ser uno de: " + enumList(sch.Enum)
+		return nil
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		n, _ := v.Float64()
+		if sch.Minimum != nil && n < *sch.Minimum {
+			errs[field] = "debe ser mayor o igual a " + formatNumber(*sch.Minimum)
+		} else if sch.Maximum != nil && n > *sch.Maximum {
+			errs[field] = "debe ser menor o igual a " + formatNumber(*sch.Maximum)
+		}
+	case string:
+		length := len([]rune(v))
+		if sch.MinLength != nil && length < *sch.MinLength {
+			if *sch.MinLength == 1 {
+				errs[field] = "no puede estar vacío"
+			} else {
+				errs[field] = fmt.Sprintf("debe tener al menos %d caracteres", *sch.MinLength)
+			}
+		} else if sch.MaxLength != nil && length > *sch.MaxLength {
+			errs[field] = fmt.Sprintf("debe tener como máximo %d caracteres", *sch.MaxLength)
+		} else if sch.Format == "date" {
+			if _, err := time.Parse(time.DateOnly, v); err != nil {
+				errs[field] = "debe tener el formato YYYY-MM-DD"
+			}
+		}
+	case []any:
+		if sch.MinItems != nil && len(v) < *sch.MinItems {
+			if *sch.MinItems == 1 {
+				errs[field] = "no puede ser una lista vacía"
+			} else {
+				errs[field] = fmt.Sprintf("debe tener al menos %d elementos", *sch.MinItems)
+			}
+			return nil
+		}
+		if sch.MaxItems != nil && len(v) > *sch.MaxItems {
+			errs[field] = fmt.Sprintf("debe tener como máximo %d elementos", *sch.MaxItems)
+			return nil
+		}
+		for i, item := range v {
+			if err := s.validateValue(fmt.Sprintf("%s[%d]", field, i), item, sch.Items, errs); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, name := range sch.Required {
+			if _, ok := v[name]; !ok {
+				errs[field+"."+name] = "es obligatorio"
+			}
+		}
+		for name, item := range v {
+			propSchema, ok := sch.Properties[name]
+			if !ok {
+				// Sin additionalProperties los campos desconocidos se ignoran, como hace json.Decoder
+				propSchema = sch.AdditionalProperties
+			}
+			if err := s.validateValue(field+"."+name, item, propSchema, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func inEnum(value any, enum []any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
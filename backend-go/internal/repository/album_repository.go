@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,7 +23,7 @@ func NewAlbumRepository(db *pgxpool.Pool) domain.AlbumRepository {
 }
 
 func (r *albumRepository) Create(ctx context.Context, input *domain.AlbumInput) (*domain.Album, error) {
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error iniciando transacción para crear álbum: %w", err)
 	}
@@ -58,20 +60,21 @@ func (r *albumRepository) Create(ctx context.Context, input *domain.AlbumInput)
 		}
 	}
 
+	// Insertar titulos de discos (opcional)
+	if err := replaceDiscs(ctx, tx, albumID, input.Discs); err != nil {
+		return nil, err
+	}
+
 	// Insertar relacion cancion (track)
 	if len(input.Tracks) > 0 {
 		queryTrack := `
-			INSERT INTO tracks (album_id, song_id, track_number)
-			VALUES ($1, $2, $3)
+			INSERT INTO tracks (album_id, song_id, disc_number, track_number)
+			VALUES ($1, $2, $3, $4)
 		`
 		for _, t := range input.Tracks {
-			_, err := tx.Exec(ctx, queryTrack, albumID, t.SongID, t.TrackNumber)
+			_, err := tx.Exec(ctx, queryTrack, albumID, t.SongID, t.DiscNumber, t.TrackNumber)
 			if err != nil {
-				var pgErr *pgconn.PgError
-				if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-					return nil, domain.ErrSongNotFound
-				}
-				return nil, fmt.Errorf("error asociando la cancion ID %d como track %d: %w", t.SongID, t.TrackNumber, err)
+				return nil, mapTrackError(err, albumID, t.SongID)
 			}
 		}
 	}
@@ -89,42 +92,57 @@ func (r *albumRepository) Create(ctx context.Context, input *domain.AlbumInput)
 	return fullAlbum, nil
 }
 
+// Los cambios de tracks también actualizan updated_at del álbum (su versión/ETag)
 func (r *albumRepository) AddTrack(ctx context.Context, albumID int64, input *domain.TrackInput) error {
 	query := `
-		INSERT INTO tracks (album_id, song_id, track_number)
-		VALUES ($1, $2, $3)
+		WITH inserted AS (
+			INSERT INTO tracks (album_id, song_id, disc_number, track_number)
+			VALUES ($1, $2, $3, $4)
+		)
+		UPDATE albums SET updated_at = NOW() WHERE id = $1
 	`
 	// Uso de Exec, porque solo insertamos en tabla intermedia
-	_, err := r.db.Exec(ctx, query, albumID, input.SongID, input.TrackNumber)
+	_, err := conn(ctx, r.db).Exec(ctx, query, albumID, input.SongID, input.DiscNumber, input.TrackNumber)
 	if err != nil {
-		var pgErr *pgconn.PgError // Verificar si error viene de postgres
-
-		if errors.As(err, &pgErr) {
-			// 23505: unique_violation
-			if pgErr.Code == "23505" {
-				// Evaluamos qué restricción falló
-				if pgErr.ConstraintName == "tracks_album_id_track_number_key" {
-					return domain.ErrTrackAlreadyExists
-				}
-				if pgErr.ConstraintName == "tracks_pkey" {
-					return domain.ErrSongAlreadyInAlbum
-				}
+		return mapTrackError(err, albumID, input.SongID)
+	}
+	return nil
+}
+
+// mapTrackError traduce los errores de postgres al insertar/editar tracks a errores de dominio.
+// Compartido por Create, AddTrack y Update para que respondan igual ante el mismo problema
+func mapTrackError(err error, albumID, songID int64) error {
+	var pgErr *pgconn.PgError // Verificar si error viene de postgres
+
+	if errors.As(err, &pgErr) {
+		// 23505: unique_violation
+		if pgErr.Code == "23505" {
+			// Evaluamos qué restricción falló
+			if pgErr.ConstraintName == "unique_track_number_per_disc" {
+				return domain.ErrTrackAlreadyExists
 			}
-			// Código 23503: foreign_key_violation (song_id no existe)
-			if pgErr.Code == "23503" {
-				return domain.ErrSongNotInDB
+			if pgErr.ConstraintName == "tracks_pkey" {
+				return domain.ErrSongAlreadyInAlbum
 			}
 		}
-
-		return fmt.Errorf("error agregando el track %d al álbum %d: %w", input.SongID, albumID, err)
+		// Código 23503: foreign_key_violation (song_id no existe)
+		if pgErr.Code == "23503" {
+			return domain.ErrSongNotInDB
+		}
 	}
-	return nil
+
+	return fmt.Errorf("error agregando el track %d al álbum %d: %w", songID, albumID, err)
 }
 
 func (r *albumRepository) RemoveTrack(ctx context.Context, albumID int64, songID int64) error {
-	query := `DELETE FROM tracks WHERE album_id = $1 AND song_id = $2`
+	query := `
+		WITH removed AS (
+			DELETE FROM tracks WHERE album_id = $1 AND song_id = $2 RETURNING album_id
+		)
+		UPDATE albums SET updated_at = NOW() WHERE id IN (SELECT album_id FROM removed)
+	`
 
-	res, err := r.db.Exec(ctx, query, albumID, songID)
+	res, err := conn(ctx, r.db).Exec(ctx, query, albumID, songID)
 	if err != nil {
 		return fmt.Errorf("error eliminando el track %d del álbum %d: %w", songID, albumID, err)
 	}
@@ -134,6 +152,154 @@ func (r *albumRepository) RemoveTrack(ctx context.Context, albumID int64, songID
 	return nil
 }
 
+// InsertTrack agrega el track en la posición indicada, desplazando una posición hacia abajo
+// los tracks del mismo disco desde ese número en adelante
+func (r *albumRepository) InsertTrack(ctx context.Context, albumID int64, input *domain.TrackInput) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción para insertar track: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockAlbum(ctx, tx, albumID); err != nil {
+		return err
+	}
+	if err := shiftTracks(ctx, tx, albumID, input.DiscNumber, input.TrackNumber, 1); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tracks (album_id, song_id, disc_number, track_number)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(ctx, query, albumID, input.SongID, input.DiscNumber, input.TrackNumber); err != nil {
+		return mapTrackError(err, albumID, input.SongID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error confirmando la inserción del track: %w", err)
+	}
+	return nil
+}
+
+// RemoveTrackAndCompact elimina el track y sube una posición los tracks siguientes del mismo disco
+// para no dejar huecos
+func (r *albumRepository) RemoveTrackAndCompact(ctx context.Context, albumID int64, songID int64) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción para remover track: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockAlbum(ctx, tx, albumID); err != nil {
+		return err
+	}
+
+	var discNumber, trackNumber int
+	query := `DELETE FROM tracks WHERE album_id = $1 AND song_id = $2 RETURNING disc_number, track_number`
+	if err := tx.QueryRow(ctx, query, albumID, songID).Scan(&discNumber, &trackNumber); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrTrackNotFound
+		}
+		return fmt.Errorf("error eliminando el track %d del álbum %d: %w", songID, albumID, err)
+	}
+
+	if err := shiftTracks(ctx, tx, albumID, discNumber, trackNumber+1, -1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error confirmando la eliminación del track: %w", err)
+	}
+	return nil
+}
+
+// ReorderTracks renumera el tracklist completo de un disco (1..n) según el orden de songIDs.
+// songIDs debe contener exactamente las canciones vigentes que hoy tiene ese disco del álbum (las que
+// muestra GetByID); los tracks de canciones en la papelera quedan después, en su orden relativo
+func (r *albumRepository) ReorderTracks(ctx context.Context, albumID int64, discNumber int, songIDs []int64) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción para reordenar tracks: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockAlbum(ctx, tx, albumID); err != nil {
+		return err
+	}
+
+	// Comparar contra los tracks actuales del disco
+	var total, matching int
+	queryCheck := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE t.song_id = ANY($3))
+		FROM tracks t
+		INNER JOIN songs s ON t.song_id = s.id
+		WHERE t.album_id = $1 AND t.disc_number = $2 AND s.deleted_at IS NULL
+	`
+	if err := tx.QueryRow(ctx, queryCheck, albumID, discNumber, songIDs).Scan(&total, &matching); err != nil {
+		return fmt.Errorf("error verificando tracks del álbum %d: %w", albumID, err)
+	}
+	if total != len(songIDs) || matching != len(songIDs) {
+		return domain.ErrTrackOrderMismatch
+	}
+
+	// Estacionar en negativo y luego asignar el número final de una sola vez
+	parkQuery := `UPDATE tracks SET track_number = -track_number WHERE album_id = $1 AND disc_number = $2`
+	if _, err := tx.Exec(ctx, parkQuery, albumID, discNumber); err != nil {
+		return fmt.Errorf("error preparando reordenamiento de tracks: %w", err)
+	}
+	reorderQuery := `
+		UPDATE tracks t
+		SET track_number = o.n
+		FROM unnest($3::bigint[]) WITH ORDINALITY AS o(song_id, n)
+		WHERE t.album_id = $1 AND t.disc_number = $2 AND t.song_id = o.song_id
+	`
+	if _, err := tx.Exec(ctx, reorderQuery, albumID, discNumber, songIDs); err != nil {
+		return fmt.Errorf("error reordenando tracks del álbum %d: %w", albumID, err)
+	}
+	if err := placeParkedTracksLast(ctx, tx, albumID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error confirmando el reordenamiento de tracks: %w", err)
+	}
+	return nil
+}
+
+// lockAlbum verifica que el álbum exista y bloquea su fila hasta el fin de la transacción,
+// serializando las operaciones que renumeran tracks del mismo álbum. Como el tracklist cambia,
+// también actualiza updated_at (versión/ETag del álbum)
+func lockAlbum(ctx context.Context, tx pgx.Tx, albumID int64) error {
+	var id int64
+	query := `UPDATE albums SET updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING id`
+	if err := tx.QueryRow(ctx, query, albumID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrAlbumNotFound
+		}
+		return fmt.Errorf("error bloqueando el álbum ID %d: %w", albumID, err)
+	}
+	return nil
+}
+
+// shiftTracks suma delta al número de todos los tracks del disco desde 'from' en adelante.
+// Se hace en dos UPDATE (a negativo y de vuelta) porque unique_track_number_per_disc
+// no es diferible y un solo UPDATE puede chocar consigo mismo a mitad de camino
+func shiftTracks(ctx context.Context, tx pgx.Tx, albumID int64, discNumber, from, delta int) error {
+	parkQuery := `
+		UPDATE tracks SET track_number = -(track_number + $4)
+		WHERE album_id = $1 AND disc_number = $2 AND track_number >= $3
+	`
+	if _, err := tx.Exec(ctx, parkQuery, albumID, discNumber, from, delta); err != nil {
+		return fmt.Errorf("error desplazando tracks del álbum %d: %w", albumID, err)
+	}
+	restoreQuery := `UPDATE tracks SET track_number = -track_number WHERE album_id = $1 AND disc_number = $2 AND track_number < 0`
+	if _, err := tx.Exec(ctx, restoreQuery, albumID, discNumber); err != nil {
+		return fmt.Errorf("error desplazando tracks del álbum %d: %w", albumID, err)
+	}
+	return nil
+}
+
 func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album, error) {
 	var album domain.Album
 
@@ -143,7 +309,7 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
 		FROM albums
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	err := r.db.QueryRow(ctx, queryAlbum, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, queryAlbum, id).Scan(
 		&album.ID,
 		&album.Title,
 		&album.ReleaseDate,
@@ -167,7 +333,7 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
 		INNER JOIN album_artists aa ON a.id = aa.artist_id
 		WHERE aa.album_id = $1 AND a.deleted_at IS NULL
 	`
-	artistRows, err := r.db.Query(ctx, queryArtist, id)
+	artistRows, err := conn(ctx, r.db).Query(ctx, queryArtist, id)
 	if err != nil {
 		return nil, fmt.Errorf("error consultando los artistas del álbum: %w", err)
 	}
@@ -184,15 +350,38 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
 		return nil, fmt.Errorf("error iterando los artistas del álbum: %w", err)
 	}
 
-	// 3. Obtener los tracks con artistas
+	// 3. Titulos de discos (solo si el álbum los define)
+	album.Discs = []domain.Disc{}
+	queryDiscs := `SELECT disc_number, title FROM album_discs WHERE album_id = $1 ORDER BY disc_number ASC`
+	discRows, err := conn(ctx, r.db).Query(ctx, queryDiscs, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando los discos del álbum: %w", err)
+	}
+	defer discRows.Close()
+
+	for discRows.Next() {
+		var disc domain.Disc
+		if err := discRows.Scan(&disc.DiscNumber, &disc.Title); err != nil {
+			return nil, fmt.Errorf("error escaneando disco del álbum: %w", err)
+		}
+		album.Discs = append(album.Discs, disc)
+	}
+	if err := discRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando los discos del álbum: %w", err)
+	}
+
+	// 4. Obtener los tracks con artistas
 	album.Tracks = []domain.Track{}
-	// JOIN con songs para traernos el título y la duración. Es vital el ORDER BY track_number
+	// JOIN con songs para traernos el título y la duración. Es vital el ORDER BY disc_number, track_number
 	queryTracks := `
     SELECT 
+        t.disc_number,
         t.track_number, 
         s.id, 
         s.title, 
         s.duration,
+        s.file_path,
+        s.audio_key IS NOT NULL,
         COALESCE(
             (SELECT jsonb_agg(jsonb_build_object(
                 'id', a.id,
@@ -206,9 +395,9 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
     FROM tracks t
     INNER JOIN songs s ON t.song_id = s.id
     WHERE t.album_id = $1 AND s.deleted_at IS NULL
-    ORDER BY t.track_number ASC
+    ORDER BY t.disc_number ASC, t.track_number ASC
 `
-	trackRows, err := r.db.Query(ctx, queryTracks, id)
+	trackRows, err := conn(ctx, r.db).Query(ctx, queryTracks, id)
 	if err != nil {
 		return nil, fmt.Errorf("error consultando los tracks del álbum: %w", err)
 	}
@@ -219,10 +408,13 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
 		var artistsJSON []byte
 
 		err := trackRows.Scan(
+			&track.DiscNumber,
 			&track.TrackNumber,
 			&track.SongID,
 			&track.Title,
 			&track.Duration,
+			&track.FilePath,
+			&track.HasAudio,
 			&artistsJSON, // Escaneamos el JSON como bytes
 		)
 		if err != nil {
@@ -242,66 +434,130 @@ func (r *albumRepository) GetByID(ctx context.Context, id int64) (*domain.Album,
 	return &album, nil
 }
 
+// Busca un álbum vigente del artista por título exacto sin distinguir mayúsculas.
+// Usado por la importación para reutilizar el álbum en vez de duplicarlo
+func (r *albumRepository) GetByTitleAndArtist(ctx context.Context, title string, artistID int64) (*domain.Album, error) {
+	query := `
+		SELECT al.id
+		FROM albums al
+		INNER JOIN album_artists aa ON aa.album_id = al.id
+		WHERE LOWER(al.title) = LOWER($1) AND aa.artist_id = $2 AND al.deleted_at IS NULL
+		ORDER BY al.id
+		LIMIT 1
+	`
+	var albumID int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, title, artistID).Scan(&albumID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAlbumNotFound
+		}
+		return nil, fmt.Errorf("error buscando el álbum '%s' del artista %d: %w", title, artistID, err)
+	}
+	return r.GetByID(ctx, albumID)
+}
+
+// Orden por defecto de los listados paginados de álbumes (más recientes primero) y campos permitidos en ?sort=
+var albumOrder = keysetOrder{
+	{name: "release_date", expr: "release_date", typ: "date", desc: true},
+	{name: "id", expr: "id", typ: "bigint"},
+}
+
+var albumSortOptions = sortOptions{
+	"id":           {name: "id", expr: "id", typ: "bigint"},
+	"title":        {expr: "title", typ: "text"},
+	"release_date": {expr: "release_date", typ: "date"},
+	"type":         {expr: "type", typ: "text"},
+	"created_at":   {expr: "created_at", typ: "timestamp"},
+	"updated_at":   {expr: "updated_at", typ: "timestamp"},
+	// Calculados sobre las canciones vigentes del tracklist
+	"total_duration": {expr: `(SELECT COALESCE(SUM(so.duration), 0) FROM tracks t INNER JOIN songs so ON so.id = t.song_id
+		WHERE t.album_id = albums.id AND so.deleted_at IS NULL)`, typ: "bigint"},
+	"track_count": {expr: `(SELECT COUNT(*) FROM tracks t INNER JOIN songs so ON so.id = t.song_id
+		WHERE t.album_id = albums.id AND so.deleted_at IS NULL)`, typ: "bigint"},
+}
+
+// Facetas del listado de álbumes (?facets=true)
+var albumFacets = []facetSpec{
+	{name: "type", expr: "type"},
+	{name: "year", expr: "EXTRACT(YEAR FROM release_date)::int"},
+}
+
+// GetAllPaginated aplica el umbral de similitud pedido (?threshold=) a todas las consultas del listado
 func (r *albumRepository) GetAllPaginated(ctx context.Context, filter domain.AlbumFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Album], error) {
-	baseQuery := `SELECT id, title, release_date, type, cover_url, created_at, updated_at FROM albums WHERE deleted_at IS NULL`
-	countQuery := `SELECT COUNT(*) FROM albums WHERE deleted_at IS NULL`
+	var result *domain.PaginatedResult[domain.Album]
+	err := withThreshold(ctx, r.db, params.Threshold, func(ctx context.Context) error {
+		var err error
+		result, err = r.getAllPaginated(ctx, filter, params)
+		return err
+	})
+	return result, err
+}
 
-	var args []interface{}
-	var argID int64 = 1
+func (r *albumRepository) getAllPaginated(ctx context.Context, filter domain.AlbumFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Album], error) {
+	var conds conditions
+	var rel *sortKey // Puntaje contra el filtro de título
 	// 1. Filtro por Título
 	if filter.Title != "" {
-		condition := fmt.Sprintf(" AND title %% $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Title)
-		argID++
+		rel = relevance("title", len(conds)+1)
+		conds.add("", "normalize_text(title) %% $%d", validation.NormalizeText(filter.Title))
 	}
 
-	// 2. Filtro por Tipo (EP, LP, Single)
-	if filter.Type != "" {
-		condition := fmt.Sprintf(" AND type = $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Type)
-		argID++
+	// 2. Filtro por Tipo (EP, LP, Single), uno o varios
+	if len(filter.Type) > 0 {
+		conds.add("type", "type = ANY($%d)", filter.Type)
 	}
 
-	// 3. Filtro por ID de Artista (Subconsulta)
+	// 3. Filtro por año de lanzamiento, uno o varios
+	if len(filter.Year) > 0 {
+		conds.add("year", "EXTRACT(YEAR FROM release_date)::int = ANY($%d)", filter.Year)
+	}
+
+	// 4. Filtro por ID de Artista (Subconsulta)
 	if filter.ArtistID > 0 {
-		condition := fmt.Sprintf(" AND id IN (SELECT album_id FROM album_artists WHERE artist_id = $%d)", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.ArtistID)
-		argID++
+		conds.add("", "id IN (SELECT album_id FROM album_artists WHERE artist_id = $%d)", filter.ArtistID)
 	}
 
-	// 4. Filtro por Nombre de Artista (Subconsulta con JOIN)
+	// 5. Filtro por Nombre de Artista (Subconsulta con JOIN)
 	if filter.ArtistName != "" {
-		condition := fmt.Sprintf(` AND id IN (
+		conds.add("", `id IN (
 			SELECT aa.album_id 
 			FROM album_artists aa 
 			INNER JOIN artists a ON aa.artist_id = a.id 
-			WHERE a.name %% $%d AND a.deleted_at IS NULL
-		)`, argID)
-
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.ArtistName)
-		argID++
+			WHERE normalize_text(a.name) %% $%d AND a.deleted_at IS NULL
+		)`, validation.NormalizeText(filter.ArtistName))
 	}
 
-	// Contar items
-	var totalItems int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+	// 6. Rangos de fecha de lanzamiento y de creación
+	conds.addTimeRange("release_date", filter.Released)
+	conds.addTimeRange("created_at", filter.Created)
+
+	// Orden pedido (?sort=), validado contra la allowlist. Con filtro de título el default es por relevancia
+	order, err := albumSortOptions.resolveRelevance(params.OrderBy, albumOrder, rel)
 	if err != nil {
-		return nil, fmt.Errorf("error contando álbumes: %w", err)
+		return nil, err
 	}
 
-	// Consulta de paginacion
-	baseQuery += fmt.Sprintf(" ORDER BY release_date DESC, id ASC LIMIT $%d OFFSET $%d", argID, argID+1)
-	args = append(args, params.Limit, params.GetOffset())
+	where, args := conds.where("")
+	baseQuery := `SELECT id, title, release_date, type, cover_url, created_at, updated_at, ` + scoreColumn(rel) + `, ` + order.keysColumn() + `
+		FROM albums WHERE deleted_at IS NULL` + where
+	countQuery := `SELECT COUNT(*) FROM albums WHERE deleted_at IS NULL` + where
+
+	// Contar items (opcional en modo cursor)
+	var totalItems *int
+	if params.WithTotal {
+		var n int
+		if err := conn(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&n); err != nil {
+			return nil, fmt.Errorf("error contando álbumes: %w", err)
+		}
+		totalItems = &n
+	}
+
+	// Consulta de paginacion (OFFSET o cursor)
+	baseQuery, pageArgs, err := order.paginate(baseQuery, args, params)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(ctx, baseQuery, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, baseQuery, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo álbumes paginados: %w", err)
 	}
@@ -309,21 +565,27 @@ func (r *albumRepository) GetAllPaginated(ctx context.Context, filter domain.Alb
 
 	// Mapear rows a respuesta
 	var albums []domain.Album
-	var albumIDs []int64
+	var keys [][]string
 	for rows.Next() {
 		var a domain.Album
-		err := rows.Scan(&a.ID, &a.Title, &a.ReleaseDate, &a.Type, &a.CoverURL, &a.CreatedAt, &a.UpdatedAt)
+		var rowKeys []string
+		err := rows.Scan(&a.ID, &a.Title, &a.ReleaseDate, &a.Type, &a.CoverURL, &a.CreatedAt, &a.UpdatedAt, &a.Score, &rowKeys)
 		if err != nil {
 			return nil, fmt.Errorf("error escaneando álbum: %w", err)
 		}
 		a.Artists = []domain.AlbumArtist{} // Inicializar
 		a.Tracks = []domain.Track{}        // Inicializar vacío intencionalmente (Summary View)
 		albums = append(albums, a)
-		albumIDs = append(albumIDs, a.ID)
+		keys = append(keys, rowKeys)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterando álbumes: %w", err)
 	}
+	albums, next := nextPage(albums, keys, order, params)
+	albumIDs := make([]int64, len(albums))
+	for i := range albums {
+		albumIDs[i] = albums[i].ID
+	}
 
 	// Traer bloque de artistas
 	if len(albums) > 0 {
@@ -333,7 +595,7 @@ func (r *albumRepository) GetAllPaginated(ctx context.Context, filter domain.Alb
 			INNER JOIN album_artists aa ON a.id = aa.artist_id
 			WHERE aa.album_id = ANY($1) AND a.deleted_at IS NULL
 		`
-		artistRows, err := r.db.Query(ctx, queryArtists, albumIDs)
+		artistRows, err := conn(ctx, r.db).Query(ctx, queryArtists, albumIDs)
 		if err != nil {
 			return nil, fmt.Errorf("error obteniendo artistas en bloque para álbumes: %w", err)
 		}
@@ -361,7 +623,16 @@ func (r *albumRepository) GetAllPaginated(ctx context.Context, filter domain.Alb
 		albums = []domain.Album{}
 	}
 
-	return domain.NewPaginatedResult(albums, totalItems, params.Page, params.Limit), nil
+	result := domain.NewPageResult(albums, params, totalItems, next)
+
+	// Conteos por tipo y año bajo los filtros aplicados (opcional)
+	if params.WithFacets {
+		result.Facets, err = countFacets(ctx, conn(ctx, r.db), "albums WHERE deleted_at IS NULL", albumFacets, conds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (r *albumRepository) GetAlbumsByArtistID(ctx context.Context, artistID int64) ([]domain.Album, error) {
@@ -372,7 +643,7 @@ func (r *albumRepository) GetAlbumsByArtistID(ctx context.Context, artistID int6
 		WHERE aa.artist_id = $1 AND al.deleted_at IS NULL
 		ORDER BY al.release_date DESC
 	`
-	rows, err := r.db.Query(ctx, queryAlbums, artistID)
+	rows, err := conn(ctx, r.db).Query(ctx, queryAlbums, artistID)
 	if err != nil {
 		return nil, fmt.Errorf("error ejecutando consulta de álbumes por artista: %w", err)
 	}
@@ -408,7 +679,7 @@ func (r *albumRepository) GetAlbumsByArtistID(ctx context.Context, artistID int6
 		WHERE aa.album_id = ANY($1) AND a.deleted_at IS NULL
 	`
 	// Consulta recibe conjunto de IDs
-	artistRows, err := r.db.Query(ctx, queryArtist, albumsIDs)
+	artistRows, err := conn(ctx, r.db).Query(ctx, queryArtist, albumsIDs)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo artistas colaboradores: %w", err)
 	}
@@ -433,9 +704,10 @@ func (r *albumRepository) GetAlbumsByArtistID(ctx context.Context, artistID int6
 	return albums, nil
 }
 
-// Editar Album con artistas. No edita track, estos editan en AddTrack y RemoveTrack
-func (r *albumRepository) Update(ctx context.Context, albumID int64, input *domain.AlbumInput) (*domain.Album, error) {
-	tx, err := r.db.Begin(ctx)
+// Editar Album con artistas. Si input.Tracks o input.Discs vienen en el JSON (aunque sea []) se toman
+// como la versión definitiva; si se omiten (nil) se mantienen sin cambios
+func (r *albumRepository) Update(ctx context.Context, albumID int64, input *domain.AlbumInput, version *time.Time) (*domain.Album, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error iniciando transacción para update de álbum: %w", err)
 	}
@@ -444,14 +716,14 @@ func (r *albumRepository) Update(ctx context.Context, albumID int64, input *doma
 	updateAlbumQuery := `
 		UPDATE albums
 		SET title = $1, release_date = $2, type = $3, cover_url = $4, updated_at = NOW()
-		WHERE id = $5 AND deleted_at IS NULL
+		WHERE id = $5 AND deleted_at IS NULL AND ($6::timestamp IS NULL OR updated_at = $6)
 	`
-	res, err := tx.Exec(ctx, updateAlbumQuery, input.Title, input.ReleaseDate, input.Type, input.CoverURL, albumID)
+	res, err := tx.Exec(ctx, updateAlbumQuery, input.Title, input.ReleaseDate, input.Type, input.CoverURL, albumID, versionArg(version))
 	if err != nil {
 		return nil, fmt.Errorf("error actualizando los datos del album: %w", err)
 	}
-	if res.RowsAffected() == 0 { // Si no afecta filas es porque la cancion no existe o fue borrada
-		return nil, domain.ErrAlbumNotFound
+	if res.RowsAffected() == 0 { // No existe, fue borrado o la versión enviada ya no es la actual
+		return nil, missingOrStale(ctx, tx, "albums", albumID, version, domain.ErrAlbumNotFound)
 	}
 
 	// Limpiar relaciones antiguas
@@ -479,6 +751,18 @@ func (r *albumRepository) Update(ctx context.Context, albumID int64, input *doma
 		}
 	}
 
+	// Reemplazar titulos de discos y tracklist dentro de la misma transacción
+	if input.Discs != nil {
+		if err := replaceDiscs(ctx, tx, albumID, input.Discs); err != nil {
+			return nil, err
+		}
+	}
+	if input.Tracks != nil {
+		if err := replaceTracks(ctx, tx, albumID, input.Tracks); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("error confirmando la transacción de actualización: %w", err)
 	}
@@ -490,11 +774,136 @@ func (r *albumRepository) Update(ctx context.Context, albumID int64, input *doma
 	return updatedAlbum, nil
 }
 
-func (r *albumRepository) Delete(ctx context.Context, id int64) error {
-	query := `UPDATE albums SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.Exec(ctx, query, id)
+// Soft delete del álbum aplicando la política indicada sobre sus canciones (tracks).
+// En dry run se calcula el mismo reporte dentro de la transacción y luego se hace rollback
+func (r *albumRepository) Delete(ctx context.Context, id int64, opts domain.DeleteOptions, version *time.Time) (*domain.DeleteReport, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción para eliminar álbum: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloquear el álbum validando la versión antes de evaluar la política
+	var lockedID int64
+	queryLock := `
+		SELECT id FROM albums
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::timestamp IS NULL OR updated_at = $2)
+		FOR UPDATE
+	`
+	if err := tx.QueryRow(ctx, queryLock, id, versionArg(version)).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missingOrStale(ctx, tx, "albums", id, version, domain.ErrAlbumNotFound)
+		}
+		return nil, fmt.Errorf("error bloqueando el álbum ID %d: %w", id, err)
+	}
+
+	// Canciones vigentes del álbum. exclusive = no aparece en ningún otro álbum vigente
+	querySongs := `
+		SELECT s.id, NOT EXISTS (
+			SELECT 1 FROM tracks o
+			INNER JOIN albums al ON o.album_id = al.id
+			WHERE o.song_id = s.id AND o.album_id <> $1 AND al.deleted_at IS NULL
+		)
+		FROM tracks t
+		INNER JOIN songs s ON t.song_id = s.id
+		WHERE t.album_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.id
+	`
+	exclusiveSongs, sharedSongs, err := splitExclusive(ctx, tx, querySongs, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando canciones del álbum ID %d: %w", id, err)
+	}
+
+	report := domain.NewDeleteReport(opts)
+	switch opts.Policy {
+	case domain.DeleteRestrict:
+		report.BlockingSongs = append(exclusiveSongs, sharedSongs...)
+		report.Allowed = len(report.BlockingSongs) == 0
+	case domain.DeleteCascade:
+		report.DeletedSongs = exclusiveSongs
+		report.DetachedSongs = sharedSongs
+	case domain.DeleteDetach:
+		report.DetachedSongs = append(exclusiveSongs, sharedSongs...)
+	}
+
+	if opts.DryRun {
+		return report, nil // Rollback por defer
+	}
+	if !report.Allowed {
+		return report, domain.ErrAlbumInUse
+	}
+
+	if len(report.DeletedSongs) > 0 {
+		query := `UPDATE songs SET deleted_at = NOW() WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, query, report.DeletedSongs); err != nil {
+			return nil, fmt.Errorf("error eliminando canciones del álbum ID %d: %w", id, err)
+		}
+	}
+	if len(report.DetachedSongs) > 0 {
+		query := `DELETE FROM tracks WHERE album_id = $1 AND song_id = ANY($2)`
+		if _, err := tx.Exec(ctx, query, id, report.DetachedSongs); err != nil {
+			return nil, fmt.Errorf("error desvinculando canciones del álbum ID %d: %w", id, err)
+		}
+	}
+
+	queryDelete := `UPDATE albums SET deleted_at = NOW() WHERE id = $1`
+	if _, err := tx.Exec(ctx, queryDelete, id); err != nil {
+		return nil, fmt.Errorf("error eliminando al álbum ID %d: %w", id, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error confirmando la eliminación del álbum: %w", err)
+	}
+	return report, nil
+}
+
+// PAPELERA
+
+// Lista álbumes con soft delete, los más recientes primero
+func (r *albumRepository) GetDeletedPaginated(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Album], error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM albums WHERE deleted_at IS NOT NULL`
+	if err := conn(ctx, r.db).QueryRow(ctx, countQuery).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("error contando álbumes eliminados: %w", err)
+	}
+
+	query := `
+		SELECT id, title, release_date, type, cover_url, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, params.Limit, params.GetOffset())
 	if err != nil {
-		return fmt.Errorf("error eliminando al álbum ID %d: %w", id, err)
+		return nil, fmt.Errorf("error obteniendo álbumes eliminados: %w", err)
+	}
+	defer rows.Close()
+
+	albums := []domain.Album{}
+	for rows.Next() {
+		var a domain.Album
+		err := rows.Scan(&a.ID, &a.Title, &a.ReleaseDate, &a.Type, &a.CoverURL, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando álbum eliminado: %w", err)
+		}
+		a.Artists = []domain.AlbumArtist{}
+		a.Tracks = []domain.Track{}
+		albums = append(albums, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando álbumes eliminados: %w", err)
+	}
+
+	return domain.NewPaginatedResult(albums, totalItems, params.Page, params.Limit), nil
+}
+
+// Restore quita el soft delete. Solo aplica a álbumes que estén en la papelera
+func (r *albumRepository) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE albums SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error restaurando el álbum ID %d: %w", id, err)
 	}
 	if res.RowsAffected() == 0 {
 		return domain.ErrAlbumNotFound
@@ -502,4 +911,169 @@ func (r *albumRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// (Opcional) Reordenar Tracks (PUT /albums/{id}/tracks)
+// Purge elimina definitivamente un álbum de la papelera. Tracks y artistas asociados caen en cascada,
+// las canciones en sí se mantienen. Retorna la URL de la portada para limpiar el blob store
+func (r *albumRepository) Purge(ctx context.Context, id int64) (*domain.Purged, error) {
+	query := `DELETE FROM albums WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, cover_url`
+	var purged domain.Purged
+	if err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&purged.ID, &purged.File); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAlbumNotFound
+		}
+		return nil, fmt.Errorf("error purgando el álbum ID %d: %w", id, err)
+	}
+	return &purged, nil
+}
+
+// Elimina definitivamente los álbumes que llevan en la papelera más tiempo que retention
+func (r *albumRepository) PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]domain.Purged, error) {
+	query := `DELETE FROM albums WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - ($1::float8 * INTERVAL '1 second')
+		RETURNING id, cover_url`
+	rows, err := conn(ctx, r.db).Query(ctx, query, retention.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error purgando álbumes eliminados: %w", err)
+	}
+	purged, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.Purged])
+	if err != nil {
+		return nil, fmt.Errorf("error purgando álbumes eliminados: %w", err)
+	}
+	return purged, nil
+}
+
+// replaceTracks deja la tabla tracks del álbum igual al tracklist recibido, haciendo diff con lo existente:
+// borra las canciones que ya no vienen, renumera las que cambiaron de posición e inserta las nuevas.
+// Las filas a renumerar se "estacionan" primero en números negativos, asi ningún UPDATE intermedio
+// choca con unique_track_number_per_disc (la restricción se valida fila a fila, no al final).
+// El tracklist del cliente viene de GetByID, que oculta las canciones en la papelera: esos tracks no
+// se borran (restaurar la canción la devuelve al álbum) y quedan al final de su disco
+func replaceTracks(ctx context.Context, tx pgx.Tx, albumID int64, tracks []domain.TrackInput) error {
+	// 1. Tracks actuales de canciones vigentes (bloqueados hasta el fin de la transacción)
+	queryCurrent := `
+		SELECT t.song_id, t.disc_number, t.track_number
+		FROM tracks t
+		INNER JOIN songs s ON t.song_id = s.id
+		WHERE t.album_id = $1 AND s.deleted_at IS NULL
+		FOR UPDATE OF t
+	`
+	rows, err := tx.Query(ctx, queryCurrent, albumID)
+	if err != nil {
+		return fmt.Errorf("error consultando tracks actuales del álbum %d: %w", albumID, err)
+	}
+	current := make(map[int64][2]int) // song_id -> [disc_number, track_number]
+	for rows.Next() {
+		var songID int64
+		var discNumber, trackNumber int
+		if err := rows.Scan(&songID, &discNumber, &trackNumber); err != nil {
+			rows.Close()
+			return fmt.Errorf("error escaneando track actual: %w", err)
+		}
+		current[songID] = [2]int{discNumber, trackNumber}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterando tracks actuales: %w", err)
+	}
+
+	// 2. Clasificar: se mantienen, se renumeran o se insertan
+	keepIDs := []int64{}
+	var moved, added []domain.TrackInput
+	var movedIDs []int64
+	for _, t := range tracks {
+		position, exists := current[t.SongID]
+		if !exists {
+			added = append(added, t)
+			continue
+		}
+		keepIDs = append(keepIDs, t.SongID)
+		if position != [2]int{t.DiscNumber, t.TrackNumber} {
+			moved = append(moved, t)
+			movedIDs = append(movedIDs, t.SongID)
+		}
+	}
+
+	// 3. Borrar las que ya no forman parte del tracklist
+	deleteQuery := `
+		DELETE FROM tracks t USING songs s
+		WHERE t.song_id = s.id AND t.album_id = $1 AND s.deleted_at IS NULL AND NOT (t.song_id = ANY($2))
+	`
+	if _, err := tx.Exec(ctx, deleteQuery, albumID, keepIDs); err != nil {
+		return fmt.Errorf("error eliminando tracks antiguos del álbum %d: %w", albumID, err)
+	}
+
+	// Los tracks en la papelera se apartan para no ocupar los números que pide el cliente
+	parkTrashedQuery := `
+		UPDATE tracks t SET track_number = -t.track_number
+		FROM songs s
+		WHERE t.song_id = s.id AND t.album_id = $1 AND s.deleted_at IS NOT NULL
+	`
+	if _, err := tx.Exec(ctx, parkTrashedQuery, albumID); err != nil {
+		return fmt.Errorf("error apartando tracks en la papelera del álbum %d: %w", albumID, err)
+	}
+
+	// 4. Renumerar en dos pasos: a negativo y luego al número final
+	if len(moved) > 0 {
+		parkQuery := `UPDATE tracks SET track_number = -track_number WHERE album_id = $1 AND song_id = ANY($2)`
+		if _, err := tx.Exec(ctx, parkQuery, albumID, movedIDs); err != nil {
+			return fmt.Errorf("error preparando renumeración de tracks: %w", err)
+		}
+		renumberQuery := `UPDATE tracks SET disc_number = $1, track_number = $2 WHERE album_id = $3 AND song_id = $4`
+		for _, t := range moved {
+			if _, err := tx.Exec(ctx, renumberQuery, t.DiscNumber, t.TrackNumber, albumID, t.SongID); err != nil {
+				return mapTrackError(err, albumID, t.SongID)
+			}
+		}
+	}
+
+	// 5. Insertar las nuevas
+	insertQuery := `
+		INSERT INTO tracks (album_id, song_id, disc_number, track_number)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, t := range added {
+		if _, err := tx.Exec(ctx, insertQuery, albumID, t.SongID, t.DiscNumber, t.TrackNumber); err != nil {
+			return mapTrackError(err, albumID, t.SongID)
+		}
+	}
+
+	return placeParkedTracksLast(ctx, tx, albumID)
+}
+
+// placeParkedTracksLast ubica los tracks que quedaron estacionados en negativo (las canciones en la
+// papelera) después del último track vigente de su disco, conservando su orden relativo
+func placeParkedTracksLast(ctx context.Context, tx pgx.Tx, albumID int64) error {
+	query := `
+		UPDATE tracks t
+		SET track_number = p.last + p.n
+		FROM (
+			SELECT song_id,
+				ROW_NUMBER() OVER (PARTITION BY disc_number ORDER BY track_number DESC) AS n,
+				(SELECT COALESCE(MAX(l.track_number), 0) FROM tracks l
+				 WHERE l.album_id = $1 AND l.disc_number = parked.disc_number AND l.track_number > 0) AS last
+			FROM tracks parked
+			WHERE album_id = $1 AND track_number < 0
+		) p
+		WHERE t.album_id = $1 AND t.song_id = p.song_id
+	`
+	if _, err := tx.Exec(ctx, query, albumID); err != nil {
+		return fmt.Errorf("error ubicando tracks apartados del álbum %d: %w", albumID, err)
+	}
+	return nil
+}
+
+// replaceDiscs reemplaza los titulos de discos del álbum. Los discos sin titulo no necesitan fila
+func replaceDiscs(ctx context.Context, tx pgx.Tx, albumID int64, discs []domain.DiscInput) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM album_discs WHERE album_id = $1`, albumID); err != nil {
+		return fmt.Errorf("error limpiando discos del álbum %d: %w", albumID, err)
+	}
+
+	insertQuery := `
+		INSERT INTO album_discs (album_id, disc_number, title)
+		VALUES ($1, $2, $3)
+	`
+	for _, d := range discs {
+		if _, err := tx.Exec(ctx, insertQuery, albumID, d.DiscNumber, d.Title); err != nil {
+			return fmt.Errorf("error insertando el disco %d del álbum %d: %w", d.DiscNumber, albumID, err)
+		}
+	}
+	return nil
+}
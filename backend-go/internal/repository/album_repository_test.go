@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Un PUT con el tracklist que devuelve GetByID no trae las canciones en la papelera: no deben perder su
+// lugar en el álbum ni bloquear los números que pide el cliente
+func TestReplaceTracksKeepsTrashedSongs(t *testing.T) {
+	ctx, pool := testDB(t)
+
+	repo := NewAlbumRepository(pool)
+	songIDs := insertSongs(t, ctx, pool, 3)
+	input := &domain.AlbumInput{Title: "Prueba papelera", ReleaseDate: "2001-01-01", Type: "LP"}
+	for i, id := range songIDs {
+		input.Tracks = append(input.Tracks, domain.TrackInput{SongID: id, DiscNumber: 1, TrackNumber: i + 1})
+	}
+	album, err := repo.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	exec(t, ctx, pool, `UPDATE songs SET deleted_at = NOW() WHERE id = $1`, songIDs[0])
+
+	// El cliente ve las pistas 2 y 3 y las deja como 1 y 2, el número que ocupaba la canción en la papelera
+	input.Tracks = []domain.TrackInput{
+		{SongID: songIDs[2], DiscNumber: 1, TrackNumber: 1},
+		{SongID: songIDs[1], DiscNumber: 1, TrackNumber: 2},
+	}
+	if _, err := repo.Update(ctx, album.ID, input, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	assertSongOrder(t, storedTrackOrder(t, ctx, pool, album.ID, 1), []int64{songIDs[2], songIDs[1], songIDs[0]})
+}
+
+// Reordenar un disco con una canción en la papelera usa solo las pistas visibles; la de la papelera
+// queda al final
+func TestReorderTracksIgnoresTrashedSongs(t *testing.T) {
+	ctx, pool := testDB(t)
+
+	repo := NewAlbumRepository(pool)
+	songIDs := insertSongs(t, ctx, pool, 4)
+	input := &domain.AlbumInput{Title: "Prueba reorden", ReleaseDate: "2001-01-01", Type: "LP"}
+	for i, id := range songIDs {
+		input.Tracks = append(input.Tracks, domain.TrackInput{SongID: id, DiscNumber: 1, TrackNumber: i + 1})
+	}
+	album, err := repo.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	exec(t, ctx, pool, `UPDATE songs SET deleted_at = NOW() WHERE id = $1`, songIDs[1])
+
+	visible := []int64{songIDs[3], songIDs[2], songIDs[0]}
+	if err := repo.ReorderTracks(ctx, album.ID, 1, visible); err != nil {
+		t.Fatalf("ReorderTracks: %v", err)
+	}
+
+	assertSongOrder(t, storedTrackOrder(t, ctx, pool, album.ID, 1), append(visible, songIDs[1]))
+}
+
+// storedTrackOrder devuelve los song_id de un disco según el número guardado, incluida la papelera
+func storedTrackOrder(t *testing.T, ctx context.Context, pool *pgxpool.Pool, albumID int64, discNumber int) []int64 {
+	t.Helper()
+	rows, err := conn(ctx, pool).Query(ctx,
+		`SELECT song_id FROM tracks WHERE album_id = $1 AND disc_number = $2 ORDER BY track_number`, albumID, discNumber)
+	if err != nil {
+		t.Fatalf("consultando tracks: %v", err)
+	}
+	defer rows.Close()
+	var songIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("escaneando track: %v", err)
+		}
+		songIDs = append(songIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterando tracks: %v", err)
+	}
+	return songIDs
+}
+
+func assertSongOrder(t *testing.T, got, want []int64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("tracks = %v, se esperaba %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("tracks = %v, se esperaba %v", got, want)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -29,7 +31,7 @@ func (r *artistRepository) Create(ctx context.Context, input *domain.ArtistInput
 		VALUES ($1, $2, $3, $4, $5) 
 		RETURNING id, name, genre, country, bio, image_url, created_at, updated_at
 	`
-	err := r.db.QueryRow(ctx, query, input.Name, input.Genre, input.Country, input.Bio, input.ImageURL).
+	err := conn(ctx, r.db).QueryRow(ctx, query, input.Name, input.Genre, input.Country, input.Bio, input.ImageURL).
 		Scan(
 			&artist.ID,
 			&artist.Name,
@@ -56,7 +58,7 @@ func (r *artistRepository) GetByID(ctx context.Context, id int64) (*domain.Artis
 	`
 
 	var a domain.Artist
-	err := r.db.QueryRow(ctx, query, id).Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrArtistNotFound
@@ -66,6 +68,27 @@ func (r *artistRepository) GetByID(ctx context.Context, id int64) (*domain.Artis
 	return &a, nil
 }
 
+// Busca por nombre exacto sin distinguir mayúsculas. Usado por la importación para no duplicar artistas
+func (r *artistRepository) GetByName(ctx context.Context, name string) (*domain.Artist, error) {
+	query := `
+		SELECT id, name, genre, country, bio, image_url, created_at, updated_at
+		FROM artists
+		WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+	`
+
+	var a domain.Artist
+	err := conn(ctx, r.db).QueryRow(ctx, query, name).Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrArtistNotFound
+		}
+		return nil, fmt.Errorf("error buscando al artista '%s': %w", name, err)
+	}
+	return &a, nil
+}
+
 // Retorno de slice, los artistas se guardan juntos en memoria y es mas facil de procesar.
 // Usa slice de punteros cuando la estructura es gigante
 func (r *artistRepository) GetAll(ctx context.Context) ([]domain.Artist, error) {
@@ -75,7 +98,7 @@ func (r *artistRepository) GetAll(ctx context.Context) ([]domain.Artist, error)
 		WHERE deleted_at IS NULL
 		ORDER BY id ASC
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error ejecutando query para obtener los artistas: %w", err)
 	}
@@ -114,75 +137,114 @@ func (r *artistRepository) Count(ctx context.Context) (int64, error) {
 	query := `SELECT COUNT(*) FROM artists WHERE deleted_at IS NULL`
 
 	var total int64
-	err := r.db.QueryRow(ctx, query).Scan(&total)
+	err := conn(ctx, r.db).QueryRow(ctx, query).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
-// No es recomendable usar transaccion en este metodo porque no modifican la base de datos.
-// Efectivamente puede haber un pequeño error de datos, pero nada grave. Es mejor asumir ese riesgo
-// que usar recursos adicionales de memoria para bloquear la db en ese momento
-func (r *artistRepository) GetAllPaginated(ctx context.Context, filter domain.ArtistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
-	// 1. Consultas base
-	baseQuery := `SELECT id, name, genre, country, bio, image_url, created_at, updated_at FROM artists WHERE deleted_at IS NULL`
-	countQuery := `SELECT COUNT(*) FROM artists WHERE deleted_at IS NULL`
+// Orden por defecto de los listados paginados de artistas y campos permitidos en ?sort=
+var artistOrder = keysetOrder{{name: "id", expr: "id", typ: "bigint"}}
+
+var artistSortOptions = sortOptions{
+	"id":         {name: "id", expr: "id", typ: "bigint"},
+	"name":       {expr: "name", typ: "text"},
+	"genre":      {expr: "genre", typ: "text"},
+	"country":    {expr: "country", typ: "text"},
+	"created_at": {expr: "created_at", typ: "timestamp"},
+	"updated_at": {expr: "updated_at", typ: "timestamp"},
+	// Calculados: canciones y álbumes vigentes en los que participa
+	"song_count": {expr: `(SELECT COUNT(*) FROM song_artists sa INNER JOIN songs so ON so.id = sa.song_id
+		WHERE sa.artist_id = artists.id AND so.deleted_at IS NULL)`, typ: "bigint"},
+	"album_count": {expr: `(SELECT COUNT(*) FROM album_artists aa INNER JOIN albums al ON al.id = aa.album_id
+		WHERE aa.artist_id = artists.id AND al.deleted_at IS NULL)`, typ: "bigint"},
+}
 
-	var args []interface{} // Slice de cualquier tipo
-	argID := 1             // Rastreador de la posición del parámetro ($1, $2, etc.)
+// Facetas del listado de artistas (?facets=true)
+var artistFacets = []facetSpec{
+	{name: "genre", expr: "genre"},
+	{name: "country", expr: "country"},
+}
 
-	// 2. Construir filtros dinámicamente
+// GetAllPaginated aplica el umbral de similitud pedido (?threshold=) a todas las consultas del listado.
+// La transacción de withThreshold solo acota ese ajuste, no aísla el conteo de la página: entre ambas
+// lecturas puede haber un pequeño desfase, riesgo que se asume en vez de bloquear la db en ese momento
+func (r *artistRepository) GetAllPaginated(ctx context.Context, filter domain.ArtistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
+	var result *domain.PaginatedResult[domain.Artist]
+	err := withThreshold(ctx, r.db, params.Threshold, func(ctx context.Context) error {
+		var err error
+		result, err = r.getAllPaginated(ctx, filter, params)
+		return err
+	})
+	return result, err
+}
+
+func (r *artistRepository) getAllPaginated(ctx context.Context, filter domain.ArtistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
+	// 1. Construir filtros dinámicamente, cada uno asociado a su faceta
+	var conds conditions
+	var rel *sortKey // Puntaje contra el filtro de nombre
 	if filter.Name != "" {
-		// Agregamos la condición a ambas consultas
-		// Uso de extensin pg_trgm para busqueda tolerante a errores (%), pero en Sprintf usamos %%
-		condition := fmt.Sprintf(" AND name %% $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Name)
-		argID++
-	}
-	if filter.Genre != "" {
-		condition := fmt.Sprintf(" AND genre %% $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Genre)
-		argID++
-	}
-	if filter.Country != "" {
-		condition := fmt.Sprintf(" AND country %% $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Country)
-		argID++
-	}
-
-	// 3. Obtener el total de elementos (para calcular las páginas)
-	var totalItems int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+		// Uso de extensin pg_trgm para busqueda tolerante a errores (%), pero en Sprintf usamos %%.
+		// Se compara el texto normalizado (sin tildes ni mayúsculas) de ambos lados
+		rel = relevance("name", len(conds)+1)
+		conds.add("", "normalize_text(name) %% $%d", validation.NormalizeText(filter.Name))
+	}
+	if len(filter.Genre) > 0 {
+		// Varios valores: basta con que el género coincida con alguno. Son selecciones de faceta, se comparan
+		// exactas (sin tildes ni mayúsculas) y no por similitud para no arrastrar valores vecinos
+		conds.add("genre", "normalize_text(genre) = ANY($%d)", normalizeAll(filter.Genre))
+	}
+	if len(filter.Country) > 0 {
+		conds.add("country", "normalize_text(country) = ANY($%d)", normalizeAll(filter.Country))
+	}
+
+	// Orden pedido (?sort=), validado contra la allowlist. Con filtro de nombre el default es por relevancia
+	order, err := artistSortOptions.resolveRelevance(params.OrderBy, artistOrder, rel)
 	if err != nil {
-		return nil, fmt.Errorf("error contando los artistas para paginación: %w", err)
+		return nil, err
 	}
 
-	// 4. Agregar Paginación a la consulta principal
-	baseQuery += fmt.Sprintf(" ORDER BY id ASC LIMIT $%d OFFSET $%d", argID, argID+1)
-	args = append(args, params.Limit, params.GetOffset())
+	// 2. Consultas base
+	where, args := conds.where("")
+	baseQuery := `SELECT id, name, genre, country, bio, image_url, created_at, updated_at, ` + scoreColumn(rel) + `, ` + order.keysColumn() + `
+		FROM artists WHERE deleted_at IS NULL` + where
+	countQuery := `SELECT COUNT(*) FROM artists WHERE deleted_at IS NULL` + where
+
+	// 3. Obtener el total de elementos (opcional en modo cursor)
+	var totalItems *int
+	if params.WithTotal {
+		var n int
+		if err := conn(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&n); err != nil {
+			return nil, fmt.Errorf("error contando los artistas para paginación: %w", err)
+		}
+		totalItems = &n
+	}
+
+	// 4. Agregar orden y paginación (OFFSET o cursor) a la consulta principal
+	baseQuery, pageArgs, err := order.paginate(baseQuery, args, params)
+	if err != nil {
+		return nil, err
+	}
 
 	// 5. Ejecutar la consulta final
-	rows, err := r.db.Query(ctx, baseQuery, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, baseQuery, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("error ejecutando query paginada de artistas: %w", err)
 	}
 	defer rows.Close()
 
 	var artists []domain.Artist
+	var keys [][]string
 	for rows.Next() {
 		var a domain.Artist
-		err := rows.Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt)
+		var rowKeys []string
+		err := rows.Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt, &a.Score, &rowKeys)
 		if err != nil {
 			return nil, fmt.Errorf("error escaneando artista en paginación: %w", err)
 		}
 		artists = append(artists, a)
+		keys = append(keys, rowKeys)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -190,23 +252,33 @@ func (r *artistRepository) GetAllPaginated(ctx context.Context, filter domain.Ar
 	}
 
 	// 6. Retornar la estructura genérica armada
+	artists, next := nextPage(artists, keys, order, params)
 	if artists == nil {
 		artists = []domain.Artist{} // Evitar null en JSON
 	}
-	return domain.NewPaginatedResult(artists, totalItems, params.Page, params.Limit), nil
+	result := domain.NewPageResult(artists, params, totalItems, next)
+
+	// 7. Conteos por género y país bajo los filtros aplicados (opcional)
+	if params.WithFacets {
+		result.Facets, err = countFacets(ctx, conn(ctx, r.db), "artists WHERE deleted_at IS NULL", artistFacets, conds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 // 3. Update
-func (r *artistRepository) Update(ctx context.Context, id int64, input *domain.ArtistInput) (*domain.Artist, error) {
+func (r *artistRepository) Update(ctx context.Context, id int64, input *domain.ArtistInput, version *time.Time) (*domain.Artist, error) {
 	var artist domain.Artist
 	query := `
 		UPDATE artists 
 		SET name = $1, genre = $2, country = $3, bio = $4, image_url = $5, updated_at = NOW() 
-		WHERE id = $6 AND deleted_at IS NULL
+		WHERE id = $6 AND deleted_at IS NULL AND ($7::timestamp IS NULL OR updated_at = $7)
 		RETURNING id, name, genre, country, bio, image_url, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, input.Name, input.Genre, input.Country, input.Bio, input.ImageURL, id).
+	err := conn(ctx, r.db).QueryRow(ctx, query, input.Name, input.Genre, input.Country, input.Bio, input.ImageURL, id, versionArg(version)).
 		Scan(
 			&artist.ID,
 			&artist.Name,
@@ -219,7 +291,7 @@ func (r *artistRepository) Update(ctx context.Context, id int64, input *domain.A
 		)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrArtistNotFound
+			return nil, missingOrStale(ctx, conn(ctx, r.db), "artists", id, version, domain.ErrArtistNotFound)
 		}
 		return nil, fmt.Errorf("error actualizando al artista ID %d: %w", id, err)
 	}
@@ -227,11 +299,360 @@ func (r *artistRepository) Update(ctx context.Context, id int64, input *domain.A
 }
 
 // 4. Delete
-func (r *artistRepository) Delete(ctx context.Context, id int64) error {
-	query := `UPDATE artists SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.Exec(ctx, query, id)
+// Soft delete del artista aplicando la política indicada sobre sus canciones y álbumes.
+// En dry run se calcula el mismo reporte dentro de la transacción y luego se hace rollback
+func (r *artistRepository) Delete(ctx context.Context, id int64, opts domain.DeleteOptions, version *time.Time) (*domain.DeleteReport, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción para eliminar artista: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloquear al artista para que nadie lo vincule mientras se evalúa. La versión se valida aquí,
+	// antes de la política, para que un cliente desactualizado reciba 412 y no un reporte
+	var lockedID int64
+	queryLock := `
+		SELECT id FROM artists
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::timestamp IS NULL OR updated_at = $2)
+		FOR UPDATE
+	`
+	if err := tx.QueryRow(ctx, queryLock, id, versionArg(version)).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missingOrStale(ctx, tx, "artists", id, version, domain.ErrArtistNotFound)
+		}
+		return nil, fmt.Errorf("error bloqueando al artista ID %d: %w", id, err)
+	}
+
+	// Canciones vigentes del artista. exclusive = ningún otro artista vigente participa en ella
+	querySongs := `
+		SELECT s.id, NOT EXISTS (
+			SELECT 1 FROM song_artists o
+			INNER JOIN artists a ON o.artist_id = a.id
+			WHERE o.song_id = s.id AND o.artist_id <> $1 AND a.deleted_at IS NULL
+		)
+		FROM song_artists sa
+		INNER JOIN songs s ON sa.song_id = s.id
+		WHERE sa.artist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.id
+	`
+	exclusiveSongs, sharedSongs, err := splitExclusive(ctx, tx, querySongs, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando canciones del artista ID %d: %w", id, err)
+	}
+
+	queryAlbums := `
+		SELECT al.id, NOT EXISTS (
+			SELECT 1 FROM album_artists o
+			INNER JOIN artists a ON o.artist_id = a.id
+			WHERE o.album_id = al.id AND o.artist_id <> $1 AND a.deleted_at IS NULL
+		)
+		FROM album_artists aa
+		INNER JOIN albums al ON aa.album_id = al.id
+		WHERE aa.artist_id = $1 AND al.deleted_at IS NULL
+		ORDER BY al.id
+	`
+	exclusiveAlbums, sharedAlbums, err := splitExclusive(ctx, tx, queryAlbums, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando álbumes del artista ID %d: %w", id, err)
+	}
+
+	report := domain.NewDeleteReport(opts)
+	switch opts.Policy {
+	case domain.DeleteRestrict:
+		report.BlockingSongs = append(exclusiveSongs, sharedSongs...)
+		report.BlockingAlbums = append(exclusiveAlbums, sharedAlbums...)
+		report.Allowed = len(report.BlockingSongs) == 0 && len(report.BlockingAlbums) == 0
+	case domain.DeleteCascade:
+		report.DeletedSongs = exclusiveSongs
+		report.DeletedAlbums = exclusiveAlbums
+		report.DetachedSongs = sharedSongs
+		report.DetachedAlbums = sharedAlbums
+	case domain.DeleteDetach:
+		report.DetachedSongs = append(exclusiveSongs, sharedSongs...)
+		report.DetachedAlbums = append(exclusiveAlbums, sharedAlbums...)
+	}
+
+	if opts.DryRun {
+		return report, nil // Rollback por defer
+	}
+	if !report.Allowed {
+		return report, domain.ErrArtistInUse
+	}
+
+	// Cascade: soft delete de lo exclusivo. Sus relaciones se conservan para poder restaurar
+	if len(report.DeletedSongs) > 0 {
+		query := `UPDATE songs SET deleted_at = NOW() WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, query, report.DeletedSongs); err != nil {
+			return nil, fmt.Errorf("error eliminando canciones del artista ID %d: %w", id, err)
+		}
+	}
+	if len(report.DeletedAlbums) > 0 {
+		query := `UPDATE albums SET deleted_at = NOW() WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, query, report.DeletedAlbums); err != nil {
+			return nil, fmt.Errorf("error eliminando álbumes del artista ID %d: %w", id, err)
+		}
+	}
+
+	// Desvincular, asi el artista eliminado no queda como "fantasma" en canciones y álbumes vigentes.
+	// Cambia la lista de artistas, por eso también la versión (ETag) de cada canción y álbum
+	if len(report.DetachedSongs) > 0 {
+		query := `
+			WITH removed AS (
+				DELETE FROM song_artists WHERE artist_id = $1 AND song_id = ANY($2) RETURNING song_id
+			)
+			UPDATE songs SET updated_at = NOW() WHERE id IN (SELECT song_id FROM removed)
+		`
+		if _, err := tx.Exec(ctx, query, id, report.DetachedSongs); err != nil {
+			return nil, fmt.Errorf("error desvinculando canciones del artista ID %d: %w", id, err)
+		}
+	}
+	if len(report.DetachedAlbums) > 0 {
+		query := `
+			WITH removed AS (
+				DELETE FROM album_artists WHERE artist_id = $1 AND album_id = ANY($2) RETURNING album_id
+			)
+			UPDATE albums SET updated_at = NOW() WHERE id IN (SELECT album_id FROM removed)
+		`
+		if _, err := tx.Exec(ctx, query, id, report.DetachedAlbums); err != nil {
+			return nil, fmt.Errorf("error desvinculando álbumes del artista ID %d: %w", id, err)
+		}
+	}
+
+	queryDelete := `UPDATE artists SET deleted_at = NOW() WHERE id = $1`
+	if _, err := tx.Exec(ctx, queryDelete, id); err != nil {
+		return nil, fmt.Errorf("error eliminando al artista ID %d: %w", id, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error confirmando la eliminación del artista: %w", err)
+	}
+	return report, nil
+}
+
+// splitExclusive ejecuta una query que retorna (id, exclusivo) y separa los IDs en dos slices
+func splitExclusive(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) ([]int64, []int64, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	exclusive, shared := []int64{}, []int64{}
+	for rows.Next() {
+		var id int64
+		var isExclusive bool
+		if err := rows.Scan(&id, &isExclusive); err != nil {
+			return nil, nil, err
+		}
+		if isExclusive {
+			exclusive = append(exclusive, id)
+		} else {
+			shared = append(shared, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return exclusive, shared, nil
+}
+
+// FUSION DE DUPLICADOS
+
+// Merge mueve todas las relaciones del artista source al artista target en una sola transacción.
+// Si ambos están en la misma canción se conserva el rol más fuerte (main > ft > producer) y si
+// ambos están en el mismo álbum se conserva is_primary si alguno lo era. Al final el source queda
+// con soft delete y su ID redirige al target
+func (r *artistRepository) Merge(ctx context.Context, targetID, sourceID int64) (*domain.ArtistMergeReport, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("error eliminando al artista ID %d: %w", id, err)
+		return nil, fmt.Errorf("error iniciando transacción para fusionar artistas: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloquear ambos artistas siempre en el mismo orden (por ID) para evitar deadlocks
+	queryLock := `SELECT id FROM artists WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+	rows, err := tx.Query(ctx, queryLock, []int64{targetID, sourceID})
+	if err != nil {
+		return nil, fmt.Errorf("error bloqueando artistas a fusionar: %w", err)
+	}
+	found := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error escaneando artista a fusionar: %w", err)
+		}
+		found[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando artistas a fusionar: %w", err)
+	}
+	if !found[targetID] {
+		return nil, domain.ErrArtistNotFound
+	}
+	if !found[sourceID] {
+		return nil, domain.ErrArtistNotInDB
+	}
+
+	report := &domain.ArtistMergeReport{SourceID: sourceID}
+
+	// 0. Toda canción o álbum del source cambia su lista de artistas: nueva versión (ETag),
+	// asi un PUT con la versión anterior no vuelve a vincular al artista fusionado
+	querySongVersion := `UPDATE songs SET updated_at = NOW() WHERE id IN (SELECT song_id FROM song_artists WHERE artist_id = $1)`
+	if _, err := tx.Exec(ctx, querySongVersion, sourceID); err != nil {
+		return nil, fmt.Errorf("error actualizando versión de canciones del artista ID %d: %w", sourceID, err)
+	}
+	queryAlbumVersion := `UPDATE albums SET updated_at = NOW() WHERE id IN (SELECT album_id FROM album_artists WHERE artist_id = $1)`
+	if _, err := tx.Exec(ctx, queryAlbumVersion, sourceID); err != nil {
+		return nil, fmt.Errorf("error actualizando versión de álbumes del artista ID %d: %w", sourceID, err)
+	}
+
+	// 1. Canciones compartidas: quedarse con el rol más fuerte y borrar la fila del source
+	queryRole := `
+		UPDATE song_artists t
+		SET role = src.role
+		FROM song_artists src
+		WHERE t.artist_id = $1 AND src.artist_id = $2 AND t.song_id = src.song_id
+		  AND (CASE src.role WHEN 'main' THEN 3 WHEN 'ft' THEN 2 ELSE 1 END)
+		    > (CASE t.role WHEN 'main' THEN 3 WHEN 'ft' THEN 2 ELSE 1 END)
+	`
+	if _, err := tx.Exec(ctx, queryRole, targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("error combinando roles de canciones: %w", err)
+	}
+	queryDupSongs := `
+		DELETE FROM song_artists
+		WHERE artist_id = $2 AND song_id IN (SELECT song_id FROM song_artists WHERE artist_id = $1)
+	`
+	res, err := tx.Exec(ctx, queryDupSongs, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error combinando canciones compartidas: %w", err)
+	}
+	report.MergedSongs = res.RowsAffected()
+
+	// 2. El resto de canciones pasan directo al target (ya no hay colisión de PK)
+	res, err = tx.Exec(ctx, `UPDATE song_artists SET artist_id = $1 WHERE artist_id = $2`, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error moviendo canciones al artista ID %d: %w", targetID, err)
+	}
+	report.MovedSongs = res.RowsAffected()
+
+	// 3. Álbumes compartidos: is_primary se conserva si cualquiera de los dos lo era
+	queryPrimary := `
+		UPDATE album_artists t
+		SET is_primary = true
+		FROM album_artists src
+		WHERE t.artist_id = $1 AND src.artist_id = $2 AND t.album_id = src.album_id
+		  AND src.is_primary AND NOT t.is_primary
+	`
+	if _, err := tx.Exec(ctx, queryPrimary, targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("error combinando artistas principales de álbumes: %w", err)
+	}
+	queryDupAlbums := `
+		DELETE FROM album_artists
+		WHERE artist_id = $2 AND album_id IN (SELECT album_id FROM album_artists WHERE artist_id = $1)
+	`
+	res, err = tx.Exec(ctx, queryDupAlbums, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error combinando álbumes compartidos: %w", err)
+	}
+	report.MergedAlbums = res.RowsAffected()
+
+	// 4. El resto de álbumes pasan directo al target
+	res, err = tx.Exec(ctx, `UPDATE album_artists SET artist_id = $1 WHERE artist_id = $2`, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error moviendo álbumes al artista ID %d: %w", targetID, err)
+	}
+	report.MovedAlbums = res.RowsAffected()
+
+	// 5. Soft delete del source y redirección. Redirecciones que apuntaban al source pasan al target
+	if _, err := tx.Exec(ctx, `UPDATE artists SET deleted_at = NOW() WHERE id = $1`, sourceID); err != nil {
+		return nil, fmt.Errorf("error eliminando al artista fusionado ID %d: %w", sourceID, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE artists SET updated_at = NOW() WHERE id = $1`, targetID); err != nil {
+		return nil, fmt.Errorf("error actualizando al artista ID %d: %w", targetID, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE artist_redirects SET new_id = $1 WHERE new_id = $2`, targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("error actualizando redirecciones previas: %w", err)
+	}
+	queryRedirect := `
+		INSERT INTO artist_redirects (old_id, new_id)
+		VALUES ($1, $2)
+		ON CONFLICT (old_id) DO UPDATE SET new_id = EXCLUDED.new_id, created_at = NOW()
+	`
+	if _, err := tx.Exec(ctx, queryRedirect, sourceID, targetID); err != nil {
+		return nil, fmt.Errorf("error registrando la redirección del artista ID %d: %w", sourceID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error confirmando la fusión de artistas: %w", err)
+	}
+
+	report.Artist, err = r.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("artistas fusionados, pero error al obtener detalles: %w", err)
+	}
+	return report, nil
+}
+
+// ResolveRedirect retorna el ID del artista que absorbió a id en una fusión
+func (r *artistRepository) ResolveRedirect(ctx context.Context, id int64) (int64, error) {
+	var newID int64
+	query := `SELECT new_id FROM artist_redirects WHERE old_id = $1`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&newID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrArtistNotFound
+		}
+		return 0, fmt.Errorf("error resolviendo redirección del artista ID %d: %w", id, err)
+	}
+	return newID, nil
+}
+
+// PAPELERA
+
+// Lista artistas con soft delete, los más recientes primero
+func (r *artistRepository) GetDeletedPaginated(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM artists WHERE deleted_at IS NOT NULL`
+	if err := conn(ctx, r.db).QueryRow(ctx, countQuery).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("error contando artistas eliminados: %w", err)
+	}
+
+	query := `
+		SELECT id, name, genre, country, bio, image_url, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, params.Limit, params.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo artistas eliminados: %w", err)
+	}
+	defer rows.Close()
+
+	artists := []domain.Artist{}
+	for rows.Next() {
+		var a domain.Artist
+		err := rows.Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando artista eliminado: %w", err)
+		}
+		artists = append(artists, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando artistas eliminados: %w", err)
+	}
+
+	return domain.NewPaginatedResult(artists, totalItems, params.Page, params.Limit), nil
+}
+
+// Restore quita el soft delete. Solo aplica a artistas que estén en la papelera
+func (r *artistRepository) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE artists SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error restaurando al artista ID %d: %w", id, err)
 	}
 	if res.RowsAffected() == 0 {
 		return domain.ErrArtistNotFound
@@ -239,6 +660,36 @@ func (r *artistRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// Purge elimina definitivamente un artista de la papelera. Sus relaciones caen por ON DELETE CASCADE.
+// Retorna la URL de su imagen para limpiar el blob store
+func (r *artistRepository) Purge(ctx context.Context, id int64) (*domain.Purged, error) {
+	query := `DELETE FROM artists WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, image_url`
+	var purged domain.Purged
+	if err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&purged.ID, &purged.File); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrArtistNotFound
+		}
+		return nil, fmt.Errorf("error purgando al artista ID %d: %w", id, err)
+	}
+	return &purged, nil
+}
+
+// Elimina definitivamente los artistas que llevan en la papelera más tiempo que retention
+func (r *artistRepository) PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]domain.Purged, error) {
+	// El corte se calcula en la DB para no mezclar zonas horarias (columnas TIMESTAMP sin zona)
+	query := `DELETE FROM artists WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - ($1::float8 * INTERVAL '1 second')
+		RETURNING id, image_url`
+	rows, err := conn(ctx, r.db).Query(ctx, query, retention.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error purgando artistas eliminados: %w", err)
+	}
+	purged, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.Purged])
+	if err != nil {
+		return nil, fmt.Errorf("error purgando artistas eliminados: %w", err)
+	}
+	return purged, nil
+}
+
 // Get artistas segun busqueda de nombre
 func (r *artistRepository) SearchArtists(ctx context.Context, searchTerm string) ([]domain.ArtistSeachResult, error) {
 	query := `
@@ -246,12 +697,12 @@ func (r *artistRepository) SearchArtists(ctx context.Context, searchTerm string)
 			a.id, 
 			a.name
 		FROM artists a
-		WHERE (a.name % $1 OR a.country % $1)
-		ORDER BY similarity(a.name, $1) DESC
+		WHERE (normalize_text(a.name) % $1 OR normalize_text(a.country) % $1)
+		ORDER BY similarity(normalize_text(a.name), $1) DESC
 		LIMIT 15;
 	`
 
-	rows, err := r.db.Query(ctx, query, searchTerm)
+	rows, err := conn(ctx, r.db).Query(ctx, query, validation.NormalizeText(searchTerm))
 	if err != nil {
 		return nil, fmt.Errorf("error buscando artistas: %w", err)
 	}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Elegir un valor de faceta filtra por ese valor (sin tildes ni mayúsculas), no por los que se le parecen
+func TestGenreFacetMatchesExactly(t *testing.T) {
+	ctx, pool := testDB(t)
+
+	repo := NewArtistRepository(pool)
+	for _, genre := range []string{"Zzróck", "ZZROCK", "Pop zzrock"} {
+		input := &domain.ArtistInput{Name: "Prueba faceta " + genre, Genre: genre, Country: "Zzlandia"}
+		if _, err := repo.Create(ctx, input); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	filter := domain.ArtistFilter{Genre: []string{"zzrock"}}
+	params := domain.PaginationParams{Page: 1, Limit: 10, WithTotal: true, WithFacets: true}
+	result, err := repo.GetAllPaginated(ctx, filter, params)
+	if err != nil {
+		t.Fatalf("GetAllPaginated: %v", err)
+	}
+
+	if result.TotalItems == nil || *result.TotalItems != 2 {
+		t.Fatalf("total = %v, se esperaban los 2 artistas de Zzróck/ZZROCK", result.TotalItems)
+	}
+	for _, a := range result.Data {
+		if a.Genre == "Pop zzrock" {
+			t.Errorf("el filtro genre=zzrock incluyó %q", a.Genre)
+		}
+	}
+	// La faceta de país se calcula con el filtro de género aplicado
+	if got := result.Facets["country"]; len(got) != 1 || got[0].Value != "Zzlandia" || got[0].Count != 2 {
+		t.Errorf("faceta country = %+v, se esperaba Zzlandia con 2", got)
+	}
+}
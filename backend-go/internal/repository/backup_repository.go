@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type backupRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBackupRepository(db *pgxpool.Pool) domain.BackupRepository {
+	return &backupRepository{db: db}
+}
+
+// backupTable es la consulta de una tabla y cómo convertir cada fila en su registro
+type backupTable struct {
+	recordType string
+	query      string
+	scan       func(rows pgx.Rows) (any, error)
+}
+
+var backupTables = []backupTable{
+	{
+		recordType: domain.BackupTypeArtist,
+		query:      `SELECT id, name, genre, country, bio, image_url, created_at, updated_at, deleted_at FROM artists ORDER BY id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var a domain.BackupArtist
+			err := rows.Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt)
+			return &a, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeSong,
+		query:      `SELECT id, title, duration, file_path, audio_key, audio_content_type, audio_size, created_at, updated_at, deleted_at FROM songs ORDER BY id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var s domain.BackupSong
+			err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.FilePath, &s.AudioKey, &s.AudioContentType, &s.AudioSize, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt)
+			return &s, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeAlbum,
+		query:      `SELECT id, title, release_date, type, cover_url, created_at, updated_at, deleted_at FROM albums ORDER BY id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var a domain.BackupAlbum
+			var releaseDate time.Time
+			err := rows.Scan(&a.ID, &a.Title, &releaseDate, &a.Type, &a.CoverURL, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt)
+			a.ReleaseDate = releaseDate.Format(time.DateOnly)
+			return &a, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeAlbumDisc,
+		query:      `SELECT album_id, disc_number, title FROM album_discs ORDER BY album_id, disc_number`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var d domain.BackupAlbumDisc
+			err := rows.Scan(&d.AlbumID, &d.DiscNumber, &d.Title)
+			return &d, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeSongArtist,
+		query:      `SELECT song_id, artist_id, role FROM song_artists ORDER BY song_id, artist_id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var sa domain.BackupSongArtist
+			err := rows.Scan(&sa.SongID, &sa.ArtistID, &sa.Role)
+			return &sa, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeAlbumArtist,
+		query:      `SELECT album_id, artist_id, is_primary FROM album_artists ORDER BY album_id, artist_id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var aa domain.BackupAlbumArtist
+			err := rows.Scan(&aa.AlbumID, &aa.ArtistID, &aa.IsPrimary)
+			return &aa, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeTrack,
+		query:      `SELECT album_id, song_id, disc_number, track_number FROM tracks ORDER BY album_id, disc_number, track_number`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var t domain.BackupTrack
+			err := rows.Scan(&t.AlbumID, &t.SongID, &t.DiscNumber, &t.TrackNumber)
+			return &t, err
+		},
+	},
+	{
+		recordType: domain.BackupTypePlaylist,
+		query:      `SELECT id, name, description, created_at, updated_at, deleted_at FROM playlists ORDER BY id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var p domain.BackupPlaylist
+			err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+			return &p, err
+		},
+	},
+	{
+		recordType: domain.BackupTypePlaylistSong,
+		query:      `SELECT playlist_id, song_id, position, added_at FROM playlist_songs ORDER BY playlist_id, position`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var ps domain.BackupPlaylistSong
+			err := rows.Scan(&ps.PlaylistID, &ps.SongID, &ps.Position, &ps.AddedAt)
+			return &ps, err
+		},
+	},
+	{
+		recordType: domain.BackupTypeArtistRedirect,
+		query:      `SELECT old_id, new_id, created_at FROM artist_redirects ORDER BY old_id`,
+		scan: func(rows pgx.Rows) (any, error) {
+			var ar domain.BackupArtistRedirect
+			err := rows.Scan(&ar.OldID, &ar.NewID, &ar.CreatedAt)
+			return &ar, err
+		},
+	},
+}
+
+// Export emite las tablas en el orden de backupTables, padres antes que hijos,
+// para que el restore pueda reasignar cada referencia al leerla
+func (r *backupRepository) Export(ctx context.Context, emit func(recordType string, record any) error) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción de exportación: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Una sola foto de la base para todas las tablas
+	if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`); err != nil {
+		return fmt.Errorf("error configurando la transacción de exportación: %w", err)
+	}
+
+	for _, table := range backupTables {
+		if err := exportTable(ctx, tx, table, emit); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func exportTable(ctx context.Context, tx pgx.Tx, table backupTable, emit func(string, any) error) error {
+	rows, err := tx.Query(ctx, table.query)
+	if err != nil {
+		return fmt.Errorf("error exportando %s: %w", table.recordType, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := table.scan(rows)
+		if err != nil {
+			return fmt.Errorf("error leyendo fila de %s: %w", table.recordType, err)
+		}
+		if err := emit(table.recordType, record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterando %s: %w", table.recordType, err)
+	}
+	return nil
+}
+
+// IsCatalogEmpty considera también los registros en la papelera
+func (r *backupRepository) IsCatalogEmpty(ctx context.Context) (bool, error) {
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM artists)
+			AND NOT EXISTS (SELECT 1 FROM songs)
+			AND NOT EXISTS (SELECT 1 FROM albums)
+			AND NOT EXISTS (SELECT 1 FROM playlists)
+	`
+	var empty bool
+	if err := conn(ctx, r.db).QueryRow(ctx, query).Scan(&empty); err != nil {
+		return false, fmt.Errorf("error verificando si el catálogo está vacío: %w", err)
+	}
+	return empty, nil
+}
+
+func (r *backupRepository) InsertArtist(ctx context.Context, a *domain.BackupArtist) (int64, error) {
+	query := `
+		INSERT INTO artists (name, genre, country, bio, image_url, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	var id int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, a.Name, a.Genre, a.Country, a.Bio, a.ImageURL, a.CreatedAt, a.UpdatedAt, a.DeletedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error restaurando artista %d: %w", a.ID, err)
+	}
+	return id, nil
+}
+
+func (r *backupRepository) InsertSong(ctx context.Context, s *domain.BackupSong) (int64, error) {
+	query := `
+		INSERT INTO songs (title, duration, file_path, audio_key, audio_content_type, audio_size, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	var id int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, s.Title, s.Duration, s.FilePath, s.AudioKey, s.AudioContentType, s.AudioSize,
+		s.CreatedAt, s.UpdatedAt, s.DeletedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error restaurando canción %d: %w", s.ID, err)
+	}
+	return id, nil
+}
+
+func (r *backupRepository) InsertAlbum(ctx context.Context, a *domain.BackupAlbum) (int64, error) {
+	query := `
+		INSERT INTO albums (title, release_date, type, cover_url, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	var id int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, a.Title, a.ReleaseDate, a.Type, a.CoverURL, a.CreatedAt, a.UpdatedAt, a.DeletedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error restaurando álbum %d: %w", a.ID, err)
+	}
+	return id, nil
+}
+
+func (r *backupRepository) InsertAlbumDisc(ctx context.Context, d *domain.BackupAlbumDisc) error {
+	query := `INSERT INTO album_discs (album_id, disc_number, title) VALUES ($1, $2, $3)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, d.AlbumID, d.DiscNumber, d.Title); err != nil {
+		return fmt.Errorf("error restaurando disco %d del álbum %d: %w", d.DiscNumber, d.AlbumID, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) InsertSongArtist(ctx context.Context, sa *domain.BackupSongArtist) error {
+	query := `INSERT INTO song_artists (song_id, artist_id, role) VALUES ($1, $2, $3)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, sa.SongID, sa.ArtistID, sa.Role); err != nil {
+		return fmt.Errorf("error restaurando artista %d de la canción %d: %w", sa.ArtistID, sa.SongID, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) InsertAlbumArtist(ctx context.Context, aa *domain.BackupAlbumArtist) error {
+	query := `INSERT INTO album_artists (album_id, artist_id, is_primary) VALUES ($1, $2, $3)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, aa.AlbumID, aa.ArtistID, aa.IsPrimary); err != nil {
+		return fmt.Errorf("error restaurando artista %d del álbum %d: %w", aa.ArtistID, aa.AlbumID, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) InsertTrack(ctx context.Context, t *domain.BackupTrack) error {
+	query := `INSERT INTO tracks (album_id, song_id, disc_number, track_number) VALUES ($1, $2, $3, $4)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, t.AlbumID, t.SongID, t.DiscNumber, t.TrackNumber); err != nil {
+		return fmt.Errorf("error restaurando pista %d del álbum %d: %w", t.TrackNumber, t.AlbumID, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) InsertPlaylist(ctx context.Context, p *domain.BackupPlaylist) (int64, error) {
+	query := `
+		INSERT INTO playlists (name, description, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	var id int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, p.Name, p.Description, p.CreatedAt, p.UpdatedAt, p.DeletedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error restaurando playlist %d: %w", p.ID, err)
+	}
+	return id, nil
+}
+
+func (r *backupRepository) InsertPlaylistSong(ctx context.Context, ps *domain.BackupPlaylistSong) error {
+	query := `INSERT INTO playlist_songs (playlist_id, song_id, position, added_at) VALUES ($1, $2, $3, $4)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, ps.PlaylistID, ps.SongID, ps.Position, ps.AddedAt); err != nil {
+		return fmt.Errorf("error restaurando la posición %d de la playlist %d: %w", ps.Position, ps.PlaylistID, err)
+	}
+	return nil
+}
+
+func (r *backupRepository) InsertArtistRedirect(ctx context.Context, ar *domain.BackupArtistRedirect) error {
+	query := `INSERT INTO artist_redirects (old_id, new_id, created_at) VALUES ($1, $2, $3)`
+	if _, err := conn(ctx, r.db).Exec(ctx, query, ar.OldID, ar.NewID, ar.CreatedAt); err != nil {
+		return fmt.Errorf("error restaurando la redirección del artista %d: %w", ar.OldID, err)
+	}
+	return nil
+}
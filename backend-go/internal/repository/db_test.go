@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB abre una transacción sobre TEST_DATABASE_URL (una base con las migraciones aplicadas) y la
+// deja en el ctx, asi los repositorios la toman con conn(). Todo se revierte al terminar el test.
+// Sin TEST_DATABASE_URL el test se omite
+func testDB(t *testing.T) (context.Context, *pgxpool.Pool) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL no definida, se omite el test de integración")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("error conectando a la base de pruebas: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("error iniciando la transacción de prueba: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback(ctx) })
+	return context.WithValue(ctx, txKey{}, tx), pool
+}
+
+// exec ejecuta SQL auxiliar del test dentro de la transacción
+func exec(t *testing.T, ctx context.Context, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := conn(ctx, pool).Exec(ctx, sql, args...); err != nil {
+		t.Fatalf("error ejecutando %q: %v", sql, err)
+	}
+}
+
+// insertSongs crea n canciones de prueba y devuelve sus IDs en orden de creación
+func insertSongs(t *testing.T, ctx context.Context, pool *pgxpool.Pool, n int) []int64 {
+	t.Helper()
+	songIDs := make([]int64, n)
+	for i := range songIDs {
+		err := conn(ctx, pool).QueryRow(ctx, `INSERT INTO songs (title, duration) VALUES ($1, 180) RETURNING id`,
+			"Canción de prueba").Scan(&songIDs[i])
+		if err != nil {
+			t.Fatalf("insertando canción: %v", err)
+		}
+	}
+	return songIDs
+}
+
+// storedPositions devuelve las posiciones guardadas de la playlist, incluidas las de canciones en la papelera
+func storedPositions(t *testing.T, ctx context.Context, pool *pgxpool.Pool, playlistID int64) []int {
+	t.Helper()
+	rows, err := conn(ctx, pool).Query(ctx, `SELECT position FROM playlist_songs WHERE playlist_id = $1 ORDER BY position`, playlistID)
+	if err != nil {
+		t.Fatalf("consultando posiciones: %v", err)
+	}
+	defer rows.Close()
+	var positions []int
+	for rows.Next() {
+		var p int
+		if err := rows.Scan(&p); err != nil {
+			t.Fatalf("escaneando posición: %v", err)
+		}
+		positions = append(positions, p)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterando posiciones: %v", err)
+	}
+	return positions
+}
+
+func assertPositions(t *testing.T, got, want []int) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("posiciones = %v, se esperaba %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("posiciones = %v, se esperaba %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// duplicateRepository consultas de solo lectura para detectar registros duplicados con pg_trgm.
+// Los nombres se comparan normalizados, "Cancion" y "Canción" cuentan como iguales
+type duplicateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDuplicateRepository(db *pgxpool.Pool) domain.DuplicateRepository {
+	return &duplicateRepository{db: db}
+}
+
+// Pares de canciones con título similar, puntuados además por duración y artistas en común
+func (r *duplicateRepository) FindSongPairs(ctx context.Context, opts domain.DuplicateOptions) ([]domain.DuplicatePair, error) {
+	query := `
+		SELECT a_id, b_id, title_score, duration_score, artist_score,
+			(title_score * $2 + duration_score * $3 + artist_score * $4) AS score
+		FROM (
+			SELECT
+				a.id AS a_id,
+				b.id AS b_id,
+				similarity(normalize_text(a.title), normalize_text(b.title)) AS title_score,
+				GREATEST(0, 1 - ABS(a.duration - b.duration) / ($1::float8 + 1)) AS duration_score,
+				COALESCE(
+					(SELECT COUNT(*) FROM song_artists x
+					 INNER JOIN song_artists y ON x.artist_id = y.artist_id
+					 WHERE x.song_id = a.id AND y.song_id = b.id)::float8
+					/ NULLIF((SELECT COUNT(DISTINCT artist_id) FROM song_artists WHERE song_id IN (a.id, b.id)), 0),
+				0) AS artist_score
+			FROM songs a
+			INNER JOIN songs b ON a.id < b.id AND normalize_text(a.title) % normalize_text(b.title)
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+		) candidates
+		ORDER BY score DESC, a_id, b_id
+		LIMIT $5
+	`
+	pairs := []domain.DuplicatePair{}
+	err := withThreshold(ctx, r.db, opts.Threshold, func(ctx context.Context) error {
+		rows, err := conn(ctx, r.db).Query(ctx, query, opts.DurationTolerance,
+			domain.SongTitleWeight, domain.SongDurationWeight, domain.SongArtistWeight, opts.Limit)
+		if err != nil {
+			return fmt.Errorf("error buscando canciones duplicadas: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p domain.DuplicatePair
+			if err := rows.Scan(&p.AID, &p.BID, &p.NameScore, &p.DurationScore, &p.ArtistScore, &p.Score); err != nil {
+				return fmt.Errorf("error escaneando par de canciones: %w", err)
+			}
+			pairs = append(pairs, p)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterando pares de canciones: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// Pares de artistas con nombre similar
+func (r *duplicateRepository) FindArtistPairs(ctx context.Context, opts domain.DuplicateOptions) ([]domain.DuplicatePair, error) {
+	query := `
+		SELECT a.id, b.id, similarity(normalize_text(a.name), normalize_text(b.name)) AS score
+		FROM artists a
+		INNER JOIN artists b ON a.id < b.id AND normalize_text(a.name) % normalize_text(b.name)
+		WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+		ORDER BY score DESC, a.id, b.id
+		LIMIT $1
+	`
+	pairs := []domain.DuplicatePair{}
+	err := withThreshold(ctx, r.db, opts.Threshold, func(ctx context.Context) error {
+		rows, err := conn(ctx, r.db).Query(ctx, query, opts.Limit)
+		if err != nil {
+			return fmt.Errorf("error buscando artistas duplicados: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p domain.DuplicatePair
+			if err := rows.Scan(&p.AID, &p.BID, &p.Score); err != nil {
+				return fmt.Errorf("error escaneando par de artistas: %w", err)
+			}
+			p.NameScore = p.Score
+			pairs = append(pairs, p)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterando pares de artistas: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// Canciones con sus artistas, para mostrar los integrantes de cada grupo
+func (r *duplicateRepository) GetSongsByIDs(ctx context.Context, ids []int64) ([]domain.Song, error) {
+	querySongs := `
+		SELECT id, title, duration, created_at, updated_at
+		FROM songs
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, querySongs, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo canciones duplicadas: %w", err)
+	}
+	defer rows.Close()
+
+	songs := []domain.Song{}
+	for rows.Next() {
+		var s domain.Song
+		if err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando canción duplicada: %w", err)
+		}
+		s.Artists = []domain.ArtistWithRole{}
+		songs = append(songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando canciones duplicadas: %w", err)
+	}
+
+	queryArtists := `
+		SELECT asg.song_id, a.id, a.name, asg.role
+		FROM artists a
+		INNER JOIN song_artists asg ON a.id = asg.artist_id
+		WHERE asg.song_id = ANY($1) AND a.deleted_at IS NULL
+	`
+	artistRows, err := conn(ctx, r.db).Query(ctx, queryArtists, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo artistas de canciones duplicadas: %w", err)
+	}
+	defer artistRows.Close()
+
+	songMap := make(map[int64]*domain.Song)
+	for i := range songs {
+		songMap[songs[i].ID] = &songs[i]
+	}
+	for artistRows.Next() {
+		var songID int64
+		var artist domain.ArtistWithRole
+		if err := artistRows.Scan(&songID, &artist.ID, &artist.Name, &artist.Role); err != nil {
+			return nil, fmt.Errorf("error escaneando artista relacional: %w", err)
+		}
+		if song, exists := songMap[songID]; exists {
+			song.Artists = append(song.Artists, artist)
+		}
+	}
+	if err := artistRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando artistas de canciones duplicadas: %w", err)
+	}
+
+	return songs, nil
+}
+
+func (r *duplicateRepository) GetArtistsByIDs(ctx context.Context, ids []int64) ([]domain.Artist, error) {
+	query := `
+		SELECT id, name, genre, country, bio, image_url, created_at, updated_at
+		FROM artists
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo artistas duplicados: %w", err)
+	}
+	defer rows.Close()
+
+	artists := []domain.Artist{}
+	for rows.Next() {
+		var a domain.Artist
+		if err := rows.Scan(&a.ID, &a.Name, &a.Genre, &a.Country, &a.Bio, &a.ImageURL, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando artista duplicado: %w", err)
+		}
+		artists = append(artists, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando artistas duplicados: %w", err)
+	}
+	return artists, nil
+}
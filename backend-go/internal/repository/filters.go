@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// condition es un filtro de un listado con un único parámetro, escrito con un %d donde va su número ($%d).
+// facet indica a qué faceta pertenece, vacío si no corresponde a ninguna
+type condition struct {
+	facet string
+	expr  string
+	arg   interface{}
+}
+
+// conditions acumula los filtros de un listado. Se numeran al armar cada consulta porque las
+// facetas reutilizan los mismos filtros omitiendo uno, y Postgres no acepta parámetros sin usar
+type conditions []condition
+
+func (c *conditions) add(facet, expr string, arg interface{}) {
+	*c = append(*c, condition{facet: facet, expr: expr, arg: arg})
+}
+
+// where arma " AND ..." con todos los filtros menos los de la faceta skip ("" no omite ninguno)
+func (c conditions) where(skip string) (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+	for _, cond := range c {
+		if skip != "" && cond.facet == skip {
+			continue
+		}
+		args = append(args, cond.arg)
+		sb.WriteString(" AND ")
+		sb.WriteString(fmt.Sprintf(cond.expr, len(args)))
+	}
+	return sb.String(), args
+}
+
+// facetSpec define una faceta: nombre en la respuesta y expresión por la que se agrupa
+type facetSpec struct {
+	name string
+	expr string
+}
+
+// countFacets calcula cada faceta sobre from (tabla y condiciones fijas, ej. "artists WHERE deleted_at IS NULL").
+// Las opciones se ordenan por conteo y luego por valor; los valores NULL no se cuentan
+func countFacets(ctx context.Context, q dbtx, from string, specs []facetSpec, conds conditions) (domain.Facets, error) {
+	facets := make(domain.Facets, len(specs))
+	for _, spec := range specs {
+		where, args := conds.where(spec.name)
+		query := fmt.Sprintf(`SELECT (%s)::text AS value, COUNT(*) FROM %s%s AND (%s) IS NOT NULL
+			GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d`, spec.expr, from, where, spec.expr, domain.MaxFacetValues)
+
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("error calculando la faceta %s: %w", spec.name, err)
+		}
+		values := []domain.FacetValue{}
+		for rows.Next() {
+			var v domain.FacetValue
+			if err := rows.Scan(&v.Value, &v.Count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("error escaneando la faceta %s: %w", spec.name, err)
+			}
+			values = append(values, v)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterando la faceta %s: %w", spec.name, err)
+		}
+		facets[spec.name] = values
+	}
+	return facets, nil
+}
+
+// normalizeAll pliega cada valor de un filtro múltiple, para compararlo con normalize_text(columna)
+func normalizeAll(values []string) []string {
+	normalized := make([]string, len(values))
+	for i, v := range values {
+		normalized[i] = validation.NormalizeText(v)
+	}
+	return normalized
+}
+
+// addIntRange agrega expr >= min y expr <= max según los extremos presentes. Los rangos no son facetas
+func (c *conditions) addIntRange(expr string, rng domain.IntRange) {
+	if rng.Min != nil {
+		c.add("", expr+" >= $%d", *rng.Min)
+	}
+	if rng.Max != nil {
+		c.add("", expr+" <= $%d", *rng.Max)
+	}
+}
+
+// addTimeRange agrega expr >= from y expr < until según los extremos presentes
+func (c *conditions) addTimeRange(expr string, rng domain.TimeRange) {
+	if rng.From != nil {
+		c.add("", expr+" >= $%d", *rng.From)
+	}
+	if rng.Until != nil {
+		c.add("", expr+" < $%d", *rng.Until)
+	}
+}
+
+// relevance puntúa expr contra el texto buscado, que va en el parámetro $argPos ya normalizado.
+// similarity() compara los textos completos y word_similarity() premia que la búsqueda calce con una
+// parte del texto ("laferte" en "Mon Laferte"), se toma el mayor
+func relevance(expr string, argPos int) *sortKey {
+	return &sortKey{
+		name: domain.SortRelevance,
+		expr: fmt.Sprintf("GREATEST(similarity(normalize_text(%s), $%d), word_similarity($%d, normalize_text(%s)))", expr, argPos, argPos, expr),
+		typ:  "real",
+		desc: true,
+	}
+}
+
+// scoreColumn se agrega al SELECT para entregar el puntaje de cada fila, NULL sin filtro de texto
+func scoreColumn(rel *sortKey) string {
+	if rel == nil {
+		return "NULL::float8"
+	}
+	return "round((" + rel.expr + ")::numeric, 4)::float8"
+}
+
+// withThreshold ejecuta fn con pg_trgm.similarity_threshold ajustado, que es el umbral del operador %.
+// SET LOCAL solo vive dentro de la transacción, asi no afecta otras conexiones del pool, y filtrar con %
+// en vez de similarity() >= x permite usar los índices GIN. Las consultas de fn toman la transacción
+// del ctx con conn(). Sin umbral fn corre tal cual, con el default de pg_trgm. Lo usan los listados y
+// la detección de duplicados
+func withThreshold(ctx context.Context, db *pgxpool.Pool, threshold *float64, fn func(ctx context.Context) error) error {
+	if threshold == nil {
+		return fn(ctx)
+	}
+	tx, err := conn(ctx, db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción con umbral de similitud: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	value := strconv.FormatFloat(*threshold, 'f', 3, 64)
+	if _, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, value); err != nil {
+		return fmt.Errorf("error configurando umbral de similitud: %w", err)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
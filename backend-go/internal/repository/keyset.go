@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+/*
+Paginación por cursor (keyset). En vez de OFFSET se filtra por "después de la última fila entregada"
+según las columnas del ORDER BY, asi el costo no crece con la profundidad y las filas insertadas o
+borradas entre peticiones no desplazan las páginas.
+
+Cada listado define su orden como una lista de sortKey terminada en el ID (desempate único). Las
+expresiones son SQL fijo del repositorio, nunca texto del cliente, y deben ser NOT NULL (los campos
+calculados usan COALESCE).
+*/
+
+type sortKey struct {
+	name string // Nombre público, forma parte de la firma del cursor
+	expr string // Columna o expresión SQL
+	typ  string // Tipo SQL con que se compara el valor del cursor
+	desc bool
+}
+
+type keysetOrder []sortKey
+
+// sortOptions es la allowlist de ?sort= de un listado: nombre público -> columna o expresión SQL.
+// Nada del texto del cliente llega a la consulta, solo se usa como clave de este mapa
+type sortOptions map[string]sortKey
+
+// resolve arma el orden pedido por el cliente, o fallback si no pidió ninguno. El ID se agrega al
+// final como desempate para que el orden sea total (lo necesita el cursor)
+func (opts sortOptions) resolve(fields []domain.SortField, fallback keysetOrder) (keysetOrder, error) {
+	if len(fields) == 0 {
+		return fallback, nil
+	}
+
+	var order keysetOrder
+	for _, f := range fields {
+		key, ok := opts[f.Name]
+		if !ok {
+			return nil, domain.ValidationError{"sort": fmt.Sprintf("no se puede ordenar por '%s', use: %s", f.Name, opts.names())}
+		}
+		key.name = f.Name
+		key.desc = f.Desc
+		order = append(order, key)
+		if f.Name == "id" {
+			return order, nil // Lo que venga después del ID no cambia el orden
+		}
+	}
+	return append(order, opts["id"]), nil
+}
+
+// resolveRelevance es resolve para listados con filtro de texto: rel (nil si no hay filtro) se
+// suma a las opciones como "relevance" y pasa a ser el orden por defecto
+func (opts sortOptions) resolveRelevance(fields []domain.SortField, fallback keysetOrder, rel *sortKey) (keysetOrder, error) {
+	if rel == nil {
+		for _, f := range fields {
+			if f.Name == domain.SortRelevance {
+				return nil, domain.ValidationError{"sort": "el orden por relevancia requiere un filtro de texto"}
+			}
+		}
+		return opts.resolve(fields, fallback)
+	}
+
+	withRelevance := make(sortOptions, len(opts)+1)
+	for name, key := range opts {
+		withRelevance[name] = key
+	}
+	withRelevance[domain.SortRelevance] = *rel
+	return withRelevance.resolve(fields, keysetOrder{*rel, opts["id"]})
+}
+
+func (opts sortOptions) names() string {
+	names := make([]string, 0, len(opts))
+	for name := range opts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// signature identifica el orden: "-release_date,id"
+func (o keysetOrder) signature() string {
+	parts := make([]string, len(o))
+	for i, k := range o {
+		parts[i] = k.name
+		if k.desc {
+			parts[i] = "-" + k.name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (o keysetOrder) orderBy() string {
+	parts := make([]string, len(o))
+	for i, k := range o {
+		dir := "ASC"
+		if k.desc {
+			dir = "DESC"
+		}
+		parts[i] = k.expr + " " + dir
+	}
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// keysColumn se agrega al SELECT para leer de cada fila los valores con que se arma el cursor
+func (o keysetOrder) keysColumn() string {
+	parts := make([]string, len(o))
+	for i, k := range o {
+		parts[i] = "(" + k.expr + ")::text"
+	}
+	return "ARRAY[" + strings.Join(parts, ", ") + "]"
+}
+
+// after arma la condición "fila posterior al cursor" respetando la dirección de cada columna:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...  Los valores van como parámetros desde argID
+func (o keysetOrder) after(cursor *domain.Cursor, argID int) (string, []interface{}, error) {
+	if cursor.Sort != o.signature() || len(cursor.Values) != len(o) {
+		return "", nil, domain.ValidationError{"cursor": "el cursor pertenece a otro orden, vuelva a la primera página"}
+	}
+	// El cursor no va firmado: un valor editado a mano debe ser un 400 y no un error de cast en la db
+	for i, k := range o {
+		if !validCursorValue(k.typ, cursor.Values[i]) {
+			return "", nil, domain.ValidationError{"cursor": "el cursor es inválido, use el next_cursor de la respuesta anterior"}
+		}
+	}
+
+	var args []interface{}
+	var branches []string
+	for i, k := range o {
+		var conds []string
+		for j := 0; j < i; j++ {
+			conds = append(conds, fmt.Sprintf("%s = $%d::%s", o[j].expr, argID+j, o[j].typ))
+		}
+		op := ">"
+		if k.desc {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("%s %s $%d::%s", k.expr, op, argID+i, k.typ))
+		branches = append(branches, "("+strings.Join(conds, " AND ")+")")
+		args = append(args, cursor.Values[i])
+	}
+	return " AND (" + strings.Join(branches, " OR ") + ")", args, nil
+}
+
+// validCursorValue revisa que el valor tenga la forma con que Postgres escribe el tipo en keysColumn
+func validCursorValue(typ, value string) bool {
+	var err error
+	switch typ {
+	case "bigint", "int":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "real":
+		_, err = strconv.ParseFloat(value, 64)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "timestamp":
+		_, err = time.Parse("2006-01-02 15:04:05.999999", value)
+	}
+	return err == nil
+}
+
+// paginate completa la consulta con el orden y el LIMIT/OFFSET o el filtro del cursor.
+// En modo cursor se pide una fila extra para saber si hay página siguiente
+func (o keysetOrder) paginate(query string, args []interface{}, params domain.PaginationParams) (string, []interface{}, error) {
+	if !params.Keyset {
+		query += o.orderBy() + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		return query, append(args, params.Limit, params.GetOffset()), nil
+	}
+
+	if params.After != nil {
+		condition, afterArgs, err := o.after(params.After, len(args)+1)
+		if err != nil {
+			return "", nil, err
+		}
+		query += condition
+		args = append(args, afterArgs...)
+	}
+	query += o.orderBy() + fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	return query, append(args, params.Limit+1), nil
+}
+
+// nextPage recorta la fila extra del modo cursor y arma el cursor de la última fila entregada
+func nextPage[T any](items []T, keys [][]string, order keysetOrder, params domain.PaginationParams) ([]T, *domain.Cursor) {
+	if !params.Keyset || len(items) <= params.Limit {
+		return items, nil
+	}
+	items = items[:params.Limit]
+	return items, &domain.Cursor{Sort: order.signature(), Values: keys[params.Limit-1]}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+func TestKeysetAfter(t *testing.T) {
+	order := keysetOrder{
+		{name: "release_date", expr: "release_date", typ: "date", desc: true},
+		{name: "id", expr: "id", typ: "bigint"},
+	}
+
+	cond, args, err := order.after(&domain.Cursor{Sort: "-release_date,id", Values: []string{"1997-05-20", "7"}}, 3)
+	if err != nil {
+		t.Fatalf("after: %v", err)
+	}
+	wantCond := " AND ((release_date < $3::date) OR (release_date = $3::date AND id > $4::bigint))"
+	if cond != wantCond {
+		t.Errorf("condición = %q, se esperaba %q", cond, wantCond)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"1997-05-20", "7"}) {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestKeysetAfterRejectsTamperedCursor(t *testing.T) {
+	order := keysetOrder{
+		{name: "created_at", expr: "created_at", typ: "timestamp"},
+		{name: "relevance", expr: "score", typ: "real", desc: true},
+		{name: "id", expr: "id", typ: "bigint"},
+	}
+	sig := "created_at,-relevance,id"
+
+	tests := []struct {
+		name    string
+		cursor  domain.Cursor
+		wantErr bool
+	}{
+		{name: "válido", cursor: domain.Cursor{Sort: sig, Values: []string{"2026-10-16 21:05:50.123456", "0.5", "7"}}},
+		{name: "timestamp sin fracción", cursor: domain.Cursor{Sort: sig, Values: []string{"2026-10-16 21:05:50", "1", "7"}}},
+		{name: "otro orden", cursor: domain.Cursor{Sort: "created_at,id", Values: []string{"2026-10-16 21:05:50", "1", "7"}}, wantErr: true},
+		{name: "faltan valores", cursor: domain.Cursor{Sort: sig, Values: []string{"2026-10-16 21:05:50", "7"}}, wantErr: true},
+		{name: "ID no numérico", cursor: domain.Cursor{Sort: sig, Values: []string{"2026-10-16 21:05:50", "1", "7 OR 1=1"}}, wantErr: true},
+		{name: "puntaje no numérico", cursor: domain.Cursor{Sort: sig, Values: []string{"2026-10-16 21:05:50", "alto", "7"}}, wantErr: true},
+		{name: "fecha inválida", cursor: domain.Cursor{Sort: sig, Values: []string{"ayer", "1", "7"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := order.after(&tt.cursor, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("after() error = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if _, ok := err.(domain.ValidationError); !ok {
+					t.Errorf("after() error = %T, se esperaba ValidationError", err)
+				}
+			}
+		})
+	}
+}
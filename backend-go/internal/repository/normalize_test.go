@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+)
+
+// validation.NormalizeText y normalize_text deben plegar igual: los filtros comparan el texto
+// normalizado en Go contra las columnas normalizadas en la base
+func TestNormalizeTextMatchesDatabase(t *testing.T) {
+	ctx, pool := testDB(t)
+
+	inputs := []string{"Peña", "Canción", "MÜNCHEN", "São Paulo", "Straße", "Ærø", "Œuvre", "Łódź", "Đorđe", "AC/DC"}
+	for _, in := range inputs {
+		var db string
+		if err := conn(ctx, pool).QueryRow(ctx, `SELECT normalize_text($1)`, in).Scan(&db); err != nil {
+			t.Fatalf("normalize_text(%q): %v", in, err)
+		}
+		if got := validation.NormalizeText(in); got != db {
+			t.Errorf("NormalizeText(%q) = %q, normalize_text = %q", in, got, db)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type playlistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPlaylistRepository(db *pgxpool.Pool) domain.PlaylistRepository {
+	return &playlistRepository{db: db}
+}
+
+func (r *playlistRepository) Create(ctx context.Context, input *domain.PlaylistInput) (*domain.Playlist, error) {
+	var p domain.Playlist
+	query := `
+		INSERT INTO playlists (name, description)
+		VALUES ($1, $2)
+		RETURNING id, name, description, created_at, updated_at
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query, input.Name, input.Description).
+		Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creando la playlist: %w", err)
+	}
+	p.Entries = []domain.PlaylistEntry{}
+	return &p, nil
+}
+
+func (r *playlistRepository) GetByID(ctx context.Context, id int64) (*domain.Playlist, error) {
+	var p domain.Playlist
+
+	// 1. Datos principales de la playlist
+	queryPlaylist := `
+		SELECT id, name, description, created_at, updated_at
+		FROM playlists
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, queryPlaylist, id).Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPlaylistNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo la playlist: %w", err)
+	}
+
+	// 2. Entradas con datos de la cancion y sus artistas (mismo formato que tracks de album).
+	// Las canciones en papelera conservan su lugar guardado pero no se muestran, por eso la
+	// posición expuesta se numera sobre las entradas visibles y no queda con saltos (1,2,4)
+	p.Entries = []domain.PlaylistEntry{}
+	queryEntries := `
+		SELECT
+			ps.id,
+			ROW_NUMBER() OVER (ORDER BY ps.position) as position,
+			ps.added_at,
+			s.id,
+			s.title,
+			s.duration,
+			(SELECT al.cover_url FROM albums al
+			 INNER JOIN tracks t ON t.album_id = al.id
+			 WHERE t.song_id = s.id LIMIT 1) as cover_url,
+			COALESCE(
+				(SELECT jsonb_agg(jsonb_build_object(
+					'id', a.id,
+					'name', a.name,
+					'role', sa.role
+				))
+				FROM song_artists sa
+				INNER JOIN artists a ON sa.artist_id = a.id
+				WHERE sa.song_id = s.id AND a.deleted_at IS NULL
+			), '[]') as artists
+		FROM playlist_songs ps
+		INNER JOIN songs s ON ps.song_id = s.id
+		WHERE ps.playlist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY ps.position ASC
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, queryEntries, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando las canciones de la playlist: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.PlaylistEntry
+		var artistsJSON []byte
+		err := rows.Scan(&e.ID, &e.Position, &e.AddedAt, &e.SongID, &e.Title, &e.Duration, &e.CoverURL, &artistsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando entrada de playlist: %w", err)
+		}
+		if err := json.Unmarshal(artistsJSON, &e.Artists); err != nil {
+			return nil, fmt.Errorf("error unmarshaling artistas: %w", err)
+		}
+		p.Entries = append(p.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando las canciones de la playlist: %w", err)
+	}
+	p.SongCount = len(p.Entries)
+
+	return &p, nil
+}
+
+// GetAllPaginated aplica el umbral de similitud pedido (?threshold=) a todas las consultas del listado
+func (r *playlistRepository) GetAllPaginated(ctx context.Context, filter domain.PlaylistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Playlist], error) {
+	var result *domain.PaginatedResult[domain.Playlist]
+	err := withThreshold(ctx, r.db, params.Threshold, func(ctx context.Context) error {
+		var err error
+		result, err = r.getAllPaginated(ctx, filter, params)
+		return err
+	})
+	return result, err
+}
+
+func (r *playlistRepository) getAllPaginated(ctx context.Context, filter domain.PlaylistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Playlist], error) {
+	var conds conditions
+	var rel *sortKey // Puntaje contra el filtro de nombre, con filtro se ordena por relevancia
+	if filter.Name != "" {
+		rel = relevance("p.name", len(conds)+1)
+		conds.add("", "normalize_text(p.name) %% $%d", validation.NormalizeText(filter.Name))
+	}
+	order := " ORDER BY p.id ASC"
+	if rel != nil {
+		order = " ORDER BY " + rel.expr + " DESC, p.id ASC"
+	}
+
+	// Subquery cuenta solo canciones vigentes
+	where, args := conds.where("")
+	baseQuery := `
+		SELECT p.id, p.name, p.description, p.created_at, p.updated_at,
+		(SELECT COUNT(*) FROM playlist_songs ps
+		 INNER JOIN songs s ON ps.song_id = s.id
+		 WHERE ps.playlist_id = p.id AND s.deleted_at IS NULL) as song_count,
+		` + scoreColumn(rel) + `
+		FROM playlists p
+		WHERE p.deleted_at IS NULL` + where
+	countQuery := `SELECT COUNT(*) FROM playlists p WHERE p.deleted_at IS NULL` + where
+
+	var totalItems int
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+	if err != nil {
+		return nil, fmt.Errorf("error contando playlists: %w", err)
+	}
+
+	baseQuery += order + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.GetOffset())
+
+	rows, err := conn(ctx, r.db).Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo playlists paginadas: %w", err)
+	}
+	defer rows.Close()
+
+	playlists := []domain.Playlist{}
+	for rows.Next() {
+		var p domain.Playlist
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt, &p.SongCount, &p.Score); err != nil {
+			return nil, fmt.Errorf("error escaneando playlist: %w", err)
+		}
+		p.Entries = []domain.PlaylistEntry{} // Vista resumen, sin entradas
+		playlists = append(playlists, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando playlists: %w", err)
+	}
+
+	return domain.NewPaginatedResult(playlists, totalItems, params.Page, params.Limit), nil
+}
+
+func (r *playlistRepository) Update(ctx context.Context, id int64, input *domain.PlaylistInput) (*domain.Playlist, error) {
+	query := `
+		UPDATE playlists
+		SET name = $1, description = $2, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	res, err := conn(ctx, r.db).Exec(ctx, query, input.Name, input.Description, id)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando la playlist ID %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, domain.ErrPlaylistNotFound
+	}
+
+	updated, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("playlist actualizada, pero error al obtener detalles: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *playlistRepository) Delete(ctx context.Context, id int64) error {
+	query := `UPDATE playlists SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando la playlist ID %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrPlaylistNotFound
+	}
+	return nil
+}
+
+// lockPlaylist bloquea la fila de la playlist hasta el fin de la transacción y retorna
+// la última posición ocupada. Serializa las escrituras concurrentes sobre la misma playlist,
+// asi dos inserciones simultaneas no calculan la misma posicion.
+// Se usa MAX y no COUNT: si quedara un hueco en la numeración, COUNT+1 apuntaría a una posición tomada
+func lockPlaylist(ctx context.Context, tx pgx.Tx, playlistID int64) (int, error) {
+	var id int64
+	queryLock := `SELECT id FROM playlists WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.QueryRow(ctx, queryLock, playlistID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrPlaylistNotFound
+		}
+		return 0, fmt.Errorf("error bloqueando la playlist ID %d: %w", playlistID, err)
+	}
+
+	var last int
+	queryLast := `SELECT COALESCE(MAX(position), 0) FROM playlist_songs WHERE playlist_id = $1`
+	if err := tx.QueryRow(ctx, queryLast, playlistID).Scan(&last); err != nil {
+		return 0, fmt.Errorf("error obteniendo la última posición de la playlist ID %d: %w", playlistID, err)
+	}
+	return last, nil
+}
+
+// renumberPlaylists deja las posiciones de cada playlist como 1..n conservando el orden,
+// cerrando los huecos que dejan los borrados en cascada de playlist_songs
+func renumberPlaylists(ctx context.Context, tx pgx.Tx, playlistIDs []int64) error {
+	if len(playlistIDs) == 0 {
+		return nil
+	}
+	query := `
+		UPDATE playlist_songs ps
+		SET position = r.rn
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY position) AS rn
+			FROM playlist_songs
+			WHERE playlist_id = ANY($1)
+		) r
+		WHERE ps.id = r.id AND ps.position <> r.rn
+	`
+	if _, err := tx.Exec(ctx, query, playlistIDs); err != nil {
+		return fmt.Errorf("error renumerando las playlists: %w", err)
+	}
+	return nil
+}
+
+// storedPosition traduce una posición vista por el cliente (solo canciones vigentes, ver GetByID)
+// a la posición almacenada de la entrada que la ocupa. ok es false si no hay tantas entradas visibles
+func storedPosition(ctx context.Context, tx pgx.Tx, playlistID int64, visible int) (position int, ok bool, err error) {
+	query := `
+		SELECT ps.position FROM playlist_songs ps
+		INNER JOIN songs s ON ps.song_id = s.id
+		WHERE ps.playlist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY ps.position
+		OFFSET $2 LIMIT 1
+	`
+	err = tx.QueryRow(ctx, query, playlistID, visible-1).Scan(&position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("error resolviendo la posición %d de la playlist ID %d: %w", visible, playlistID, err)
+	}
+	return position, true, nil
+}
+
+// touchPlaylist actualiza updated_at de la playlist dentro de la transacción
+func touchPlaylist(ctx context.Context, tx pgx.Tx, playlistID int64) error {
+	_, err := tx.Exec(ctx, `UPDATE playlists SET updated_at = NOW() WHERE id = $1`, playlistID)
+	if err != nil {
+		return fmt.Errorf("error actualizando la fecha de la playlist ID %d: %w", playlistID, err)
+	}
+	return nil
+}
+
+// AddEntry inserta la cancion en la posicion indicada desplazando las siguientes,
+// o al final si no se indica posicion
+func (r *playlistRepository) AddEntry(ctx context.Context, playlistID int64, input *domain.PlaylistEntryInput) (*domain.PlaylistEntry, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción para agregar a playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	last, err := lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := last + 1
+	if input.Position > 0 {
+		stored, ok, err := storedPosition(ctx, tx, playlistID, input.Position)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			position = stored
+		}
+	}
+
+	// Abrir espacio. La restricción unique es diferida, se valida al hacer commit
+	shiftQuery := `UPDATE playlist_songs SET position = position + 1 WHERE playlist_id = $1 AND position >= $2`
+	if _, err := tx.Exec(ctx, shiftQuery, playlistID, position); err != nil {
+		return nil, fmt.Errorf("error desplazando entradas de la playlist: %w", err)
+	}
+
+	// Solo canciones vigentes, una cancion con soft delete no puede agregarse
+	insertQuery := `
+		INSERT INTO playlist_songs (playlist_id, song_id, position)
+		SELECT $1, s.id, $3 FROM songs s WHERE s.id = $2 AND s.deleted_at IS NULL
+		RETURNING id
+	`
+	var entryID int64
+	err = tx.QueryRow(ctx, insertQuery, playlistID, input.SongID, position).Scan(&entryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSongNotInDB
+		}
+		return nil, fmt.Errorf("error agregando la canción %d a la playlist %d: %w", input.SongID, playlistID, err)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error confirmando la transacción de la playlist: %w", err)
+	}
+
+	return r.getEntry(ctx, playlistID, entryID)
+}
+
+func (r *playlistRepository) RemoveEntry(ctx context.Context, playlistID, entryID int64) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción para remover de playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	var position int
+	deleteQuery := `DELETE FROM playlist_songs WHERE id = $1 AND playlist_id = $2 RETURNING position`
+	if err := tx.QueryRow(ctx, deleteQuery, entryID, playlistID).Scan(&position); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPlaylistEntryNotFound
+		}
+		return fmt.Errorf("error eliminando la entrada %d de la playlist %d: %w", entryID, playlistID, err)
+	}
+
+	// Cerrar el hueco que deja la entrada
+	shiftQuery := `UPDATE playlist_songs SET position = position - 1 WHERE playlist_id = $1 AND position > $2`
+	if _, err := tx.Exec(ctx, shiftQuery, playlistID, position); err != nil {
+		return fmt.Errorf("error reordenando entradas de la playlist: %w", err)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error confirmando la transacción de la playlist: %w", err)
+	}
+	return nil
+}
+
+// MoveEntry mueve una entrada a otra posicion, desplazando las que quedan entre medio.
+// Posiciones mayores al largo de la playlist mueven la entrada al final
+func (r *playlistRepository) MoveEntry(ctx context.Context, playlistID, entryID int64, position int) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción para mover en playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	last, err := lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	queryCurrent := `SELECT position FROM playlist_songs WHERE id = $1 AND playlist_id = $2`
+	if err := tx.QueryRow(ctx, queryCurrent, entryID, playlistID).Scan(&current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPlaylistEntryNotFound
+		}
+		return fmt.Errorf("error obteniendo la entrada %d de la playlist %d: %w", entryID, playlistID, err)
+	}
+
+	stored, ok, err := storedPosition(ctx, tx, playlistID, position)
+	if err != nil {
+		return err
+	}
+	if ok {
+		position = stored
+	} else {
+		position = last
+	}
+	if position == current {
+		return nil // Nada que mover
+	}
+
+	var shiftQuery string
+	if position < current {
+		// Sube: las entradas entre la nueva y la antigua posicion bajan un lugar
+		shiftQuery = `UPDATE playlist_songs SET position = position + 1 WHERE playlist_id = $1 AND position >= $2 AND position < $3`
+		_, err = tx.Exec(ctx, shiftQuery, playlistID, position, current)
+	} else {
+		// Baja: las entradas entre la antigua y la nueva posicion suben un lugar
+		shiftQuery = `UPDATE playlist_songs SET position = position - 1 WHERE playlist_id = $1 AND position > $2 AND position <= $3`
+		_, err = tx.Exec(ctx, shiftQuery, playlistID, current, position)
+	}
+	if err != nil {
+		return fmt.Errorf("error desplazando entradas de la playlist: %w", err)
+	}
+
+	moveQuery := `UPDATE playlist_songs SET position = $1 WHERE id = $2`
+	if _, err := tx.Exec(ctx, moveQuery, position, entryID); err != nil {
+		return fmt.Errorf("error moviendo la entrada %d: %w", entryID, err)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error confirmando la transacción de la playlist: %w", err)
+	}
+	return nil
+}
+
+// getEntry obtiene una entrada con los datos de la cancion y sus artistas
+func (r *playlistRepository) getEntry(ctx context.Context, playlistID, entryID int64) (*domain.PlaylistEntry, error) {
+	query := `
+		SELECT
+			ps.id,
+			(SELECT COUNT(*) FROM playlist_songs ps2
+			 INNER JOIN songs s2 ON ps2.song_id = s2.id
+			 WHERE ps2.playlist_id = ps.playlist_id AND s2.deleted_at IS NULL
+			   AND ps2.position <= ps.position) as position,
+			ps.added_at,
+			s.id,
+			s.title,
+			s.duration,
+			(SELECT al.cover_url FROM albums al
+			 INNER JOIN tracks t ON t.album_id = al.id
+			 WHERE t.song_id = s.id LIMIT 1) as cover_url,
+			COALESCE(
+				(SELECT jsonb_agg(jsonb_build_object(
+					'id', a.id,
+					'name', a.name,
+					'role', sa.role
+				))
+				FROM song_artists sa
+				INNER JOIN artists a ON sa.artist_id = a.id
+				WHERE sa.song_id = s.id AND a.deleted_at IS NULL
+			), '[]') as artists
+		FROM playlist_songs ps
+		INNER JOIN songs s ON ps.song_id = s.id
+		WHERE ps.id = $1 AND ps.playlist_id = $2
+	`
+	var e domain.PlaylistEntry
+	var artistsJSON []byte
+	err := conn(ctx, r.db).QueryRow(ctx, query, entryID, playlistID).
+		Scan(&e.ID, &e.Position, &e.AddedAt, &e.SongID, &e.Title, &e.Duration, &e.CoverURL, &artistsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPlaylistEntryNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo la entrada %d de la playlist: %w", entryID, err)
+	}
+	if err := json.Unmarshal(artistsJSON, &e.Artists); err != nil {
+		return nil, fmt.Errorf("error unmarshaling artistas: %w", err)
+	}
+	return &e, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Agregar al final de una playlist con un hueco en la numeración (ej. 1,2,4 tras purgar una canción)
+// no debe caer en una posición tomada
+func TestAddEntryAfterGap(t *testing.T) {
+	ctx, pool := testDB(t)
+	// Con la restricción inmediata la colisión aparece en la sentencia y no recién en el commit
+	exec(t, ctx, pool, `SET CONSTRAINTS ALL IMMEDIATE`)
+
+	repo := NewPlaylistRepository(pool)
+	playlist, err := repo.Create(ctx, &domain.PlaylistInput{Name: "Prueba huecos"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	songIDs := insertSongs(t, ctx, pool, 5)
+	for _, id := range songIDs[:4] {
+		if _, err := repo.AddEntry(ctx, playlist.ID, &domain.PlaylistEntryInput{SongID: id}); err != nil {
+			t.Fatalf("AddEntry: %v", err)
+		}
+	}
+	// Hueco en la posición 3, como lo dejaría un borrado en cascada sin renumerar
+	exec(t, ctx, pool, `DELETE FROM playlist_songs WHERE playlist_id = $1 AND position = 3`, playlist.ID)
+
+	for i, id := range []int64{songIDs[4], songIDs[0]} {
+		entry, err := repo.AddEntry(ctx, playlist.ID, &domain.PlaylistEntryInput{SongID: id})
+		if err != nil {
+			t.Fatalf("AddEntry tras el hueco: %v", err)
+		}
+		if want := 5 + i; entry.Position != want {
+			t.Fatalf("posición de la entrada = %d, se esperaba %d", entry.Position, want)
+		}
+	}
+
+	assertPositions(t, storedPositions(t, ctx, pool, playlist.ID), []int{1, 2, 4, 5, 6})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// searchRepository alimenta la búsqueda unificada. Cada consulta filtra con el operador % de pg_trgm
+// sobre el texto normalizado (usa los índices GIN de normalize_text) y ordena por similarity(). COUNT(*) OVER() entrega el total de
+// coincidencias en la misma consulta, antes del LIMIT
+type searchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSearchRepository(db *pgxpool.Pool) domain.SearchRepository {
+	return &searchRepository{db: db}
+}
+
+func (r *searchRepository) SearchArtists(ctx context.Context, query string, limit int) ([]domain.SearchHit, int, error) {
+	sql := `
+		SELECT a.id, a.name, a.country, similarity(normalize_text(a.name), $1) AS score, COUNT(*) OVER() AS total
+		FROM artists a
+		WHERE a.deleted_at IS NULL AND normalize_text(a.name) % $1
+		ORDER BY score DESC, a.id
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, validation.NormalizeText(query), limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error buscando artistas: %w", err)
+	}
+	return scanSearchHits(rows, domain.SearchTypeArtist)
+}
+
+func (r *searchRepository) SearchSongs(ctx context.Context, query string, limit int) ([]domain.SearchHit, int, error) {
+	sql := `
+		SELECT s.id, s.title, COALESCE(credits.names, '') AS subtitle,
+			similarity(normalize_text(s.title), $1) AS score, COUNT(*) OVER() AS total
+		FROM songs s
+		LEFT JOIN LATERAL (
+			SELECT string_agg(a.name, ', ' ORDER BY CASE sa.role WHEN 'main' THEN 1 WHEN 'ft' THEN 2 ELSE 3 END, a.name) AS names
+			FROM song_artists sa
+			INNER JOIN artists a ON a.id = sa.artist_id AND a.deleted_at IS NULL
+			WHERE sa.song_id = s.id AND sa.role <> 'producer'
+		) credits ON true
+		WHERE s.deleted_at IS NULL AND normalize_text(s.title) % $1
+		ORDER BY score DESC, s.id
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, validation.NormalizeText(query), limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error buscando canciones: %w", err)
+	}
+	return scanSearchHits(rows, domain.SearchTypeSong)
+}
+
+func (r *searchRepository) SearchAlbums(ctx context.Context, query string, limit int) ([]domain.SearchHit, int, error) {
+	sql := `
+		SELECT al.id, al.title, COALESCE(credits.names, '') AS subtitle,
+			similarity(normalize_text(al.title), $1) AS score, COUNT(*) OVER() AS total
+		FROM albums al
+		LEFT JOIN LATERAL (
+			SELECT string_agg(a.name, ', ' ORDER BY aa.is_primary DESC, a.name) AS names
+			FROM album_artists aa
+			INNER JOIN artists a ON a.id = aa.artist_id AND a.deleted_at IS NULL
+			WHERE aa.album_id = al.id
+		) credits ON true
+		WHERE al.deleted_at IS NULL AND normalize_text(al.title) % $1
+		ORDER BY score DESC, al.id
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, validation.NormalizeText(query), limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error buscando álbumes: %w", err)
+	}
+	return scanSearchHits(rows, domain.SearchTypeAlbum)
+}
+
+// scanSearchHits lee filas (id, título, subtítulo, score, total)
+func scanSearchHits(rows pgx.Rows, hitType string) ([]domain.SearchHit, int, error) {
+	defer rows.Close()
+
+	hits := []domain.SearchHit{}
+	total := 0
+	for rows.Next() {
+		hit := domain.SearchHit{Type: hitType}
+		if err := rows.Scan(&hit.ID, &hit.Title, &hit.Subtitle, &hit.Score, &total); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando resultado de búsqueda (%s): %w", hitType, err)
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterando resultados de búsqueda (%s): %w", hitType, err)
+	}
+	return hits, total, nil
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -23,7 +25,7 @@ func NewSongRepository(db *pgxpool.Pool) domain.SongRepository {
 // Create inserta una canción y sus relaciones de forma transaccional
 func (r *songRepository) Create(ctx context.Context, input *domain.SongInput) (*domain.Song, error) {
 	// 1. Iniciar la transacción
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error iniciando transacción para create: %w", err)
 	}
@@ -84,20 +86,23 @@ func (r *songRepository) GetByID(ctx context.Context, id int64) (*domain.Song, e
 	// 1. Obtener los datos principales de la Canción
 	var song domain.Song
 	querySong := `
-        SELECT s.id, s.title, s.duration, s.created_at, s.updated_at, a.cover_url
+        SELECT s.id, s.title, s.duration, s.created_at, s.updated_at, a.cover_url, s.file_path,
+            s.audio_key IS NOT NULL
         FROM songs s
         LEFT JOIN tracks t ON s.id = t.song_id
         LEFT JOIN albums a ON t.album_id = a.id
         WHERE s.id = $1 AND s.deleted_at IS NULL
         LIMIT 1
     `
-	err := r.db.QueryRow(ctx, querySong, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, querySong, id).Scan(
 		&song.ID,
 		&song.Title,
 		&song.Duration,
 		&song.CreatedAt,
 		&song.UpdatedAt,
 		&song.CoverURL,
+		&song.FilePath,
+		&song.HasAudio,
 	)
 
 	if err != nil {
@@ -115,7 +120,7 @@ func (r *songRepository) GetByID(ctx context.Context, id int64) (*domain.Song, e
 		INNER JOIN song_artists asg ON a.id = asg.artist_id
 		WHERE asg.song_id = $1 AND a.deleted_at IS NULL
 	`
-	rows, err := r.db.Query(ctx, queryArtists, id)
+	rows, err := conn(ctx, r.db).Query(ctx, queryArtists, id)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo artistas de la canción: %w", err)
 	}
@@ -148,7 +153,7 @@ func (r *songRepository) GetAll(ctx context.Context) ([]domain.Song, error) {
 		WHERE deleted_at IS NULL 
 		ORDER BY id ASC
 	`
-	rows, err := r.db.Query(ctx, querySongs)
+	rows, err := conn(ctx, r.db).Query(ctx, querySongs)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo canciones: %w", err)
 	}
@@ -185,7 +190,7 @@ func (r *songRepository) GetAll(ctx context.Context) ([]domain.Song, error) {
 		INNER JOIN song_artists asg ON a.id = asg.artist_id
 		WHERE asg.song_id = ANY($1) AND a.deleted_at IS NULL
 	`
-	artistRows, err := r.db.Query(ctx, queryArtists, songIDs)
+	artistRows, err := conn(ctx, r.db).Query(ctx, queryArtists, songIDs)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo artistas en bloque: %w", err)
 	}
@@ -213,64 +218,92 @@ func (r *songRepository) GetAll(ctx context.Context) ([]domain.Song, error) {
 	return songs, nil
 }
 
-// Utilizar subconsultas y no INNER JOIN, esto evita duplicados que rompan paginacion
-func (r *songRepository) GetAllPaginated(ctx context.Context, filter domain.SongFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
-	// Subqury obtiene caratula del album de la cancion
-	baseQuery := `
-        SELECT s.id, s.title, s.duration, s.created_at, s.updated_at,
-        (SELECT a.cover_url FROM albums a 
-         INNER JOIN tracks t ON t.album_id = a.id 
-         WHERE t.song_id = s.id LIMIT 1) as cover_url
-        FROM songs s 
-        WHERE s.deleted_at IS NULL`
-	countQuery := `SELECT COUNT(*) FROM songs WHERE deleted_at IS NULL`
+// Orden por defecto de los listados paginados de canciones y campos permitidos en ?sort=
+var songOrder = keysetOrder{{name: "id", expr: "s.id", typ: "bigint"}}
+
+var songSortOptions = sortOptions{
+	"id":         {name: "id", expr: "s.id", typ: "bigint"},
+	"title":      {expr: "s.title", typ: "text"},
+	"duration":   {expr: "s.duration", typ: "int"},
+	"created_at": {expr: "s.created_at", typ: "timestamp"},
+	"updated_at": {expr: "s.updated_at", typ: "timestamp"},
+	// Calculado: álbumes vigentes que incluyen la canción
+	"album_count": {expr: `(SELECT COUNT(*) FROM tracks t INNER JOIN albums al ON al.id = t.album_id
+		WHERE t.song_id = s.id AND al.deleted_at IS NULL)`, typ: "bigint"},
+}
 
-	var args []interface{}
-	argID := 1
+// GetAllPaginated aplica el umbral de similitud pedido (?threshold=) a todas las consultas del listado
+func (r *songRepository) GetAllPaginated(ctx context.Context, filter domain.SongFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
+	var result *domain.PaginatedResult[domain.Song]
+	err := withThreshold(ctx, r.db, params.Threshold, func(ctx context.Context) error {
+		var err error
+		result, err = r.getAllPaginated(ctx, filter, params)
+		return err
+	})
+	return result, err
+}
 
+func (r *songRepository) getAllPaginated(ctx context.Context, filter domain.SongFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
+	var conds conditions
+	var rel *sortKey // Puntaje contra el filtro de título
 	// Busqueda parcial nombre cancion
 	if filter.Title != "" {
-		condition := fmt.Sprintf(" AND title %% $%d", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.Title)
-		argID++
+		rel = relevance("s.title", len(conds)+1)
+		conds.add("", "normalize_text(title) %% $%d", validation.NormalizeText(filter.Title))
 	}
 	// Busqueda exacta artista id
 	if filter.ArtistID > 0 {
-		condition := fmt.Sprintf(" AND id IN (SELECT song_id FROM song_artists WHERE artist_id = $%d)", argID)
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.ArtistID)
-		argID++
+		conds.add("", "id IN (SELECT song_id FROM song_artists WHERE artist_id = $%d)", filter.ArtistID)
 	}
 	// Busqueda parcial nombre artista
 	if filter.ArtistName != "" {
-		condition := fmt.Sprintf(` AND id IN (
+		conds.add("", `id IN (
 			SELECT asg.song_id 
 			FROM song_artists asg 
 			INNER JOIN artists a ON asg.artist_id = a.id 
-			WHERE a.name %% $%d AND a.deleted_at IS NULL
-		)`, argID)
-
-		baseQuery += condition
-		countQuery += condition
-		args = append(args, filter.ArtistName)
-		argID++
+			WHERE normalize_text(a.name) %% $%d AND a.deleted_at IS NULL
+		)`, validation.NormalizeText(filter.ArtistName))
 	}
+	// Rangos de duración (segundos) y fecha de creación
+	conds.addIntRange("duration", filter.Duration)
+	conds.addTimeRange("created_at", filter.Created)
 
-	// Conteo total
-	var totalItems int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+	// Orden pedido (?sort=), validado contra la allowlist. Con filtro de título el default es por relevancia
+	order, err := songSortOptions.resolveRelevance(params.OrderBy, songOrder, rel)
 	if err != nil {
-		return nil, fmt.Errorf("error contando las canciones para paginación: %w", err)
+		return nil, err
+	}
+
+	// Utilizar subconsultas y no INNER JOIN, esto evita duplicados que rompan paginacion
+	// Subqury obtiene caratula del album de la cancion
+	where, args := conds.where("")
+	baseQuery := `
+        SELECT s.id, s.title, s.duration, s.created_at, s.updated_at,
+        (SELECT a.cover_url FROM albums a 
+         INNER JOIN tracks t ON t.album_id = a.id 
+         WHERE t.song_id = s.id LIMIT 1) as cover_url,
+        ` + scoreColumn(rel) + `, ` + order.keysColumn() + `
+        FROM songs s 
+        WHERE s.deleted_at IS NULL` + where
+	countQuery := `SELECT COUNT(*) FROM songs WHERE deleted_at IS NULL` + where
+
+	// Conteo total (opcional en modo cursor)
+	var totalItems *int
+	if params.WithTotal {
+		var n int
+		if err := conn(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&n); err != nil {
+			return nil, fmt.Errorf("error contando las canciones para paginación: %w", err)
+		}
+		totalItems = &n
 	}
 
-	// Obtener canciones paginadas
-	baseQuery += fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", argID, argID+1)
-	args = append(args, params.Limit, params.GetOffset())
+	// Obtener canciones paginadas (OFFSET o cursor)
+	baseQuery, args, err = order.paginate(baseQuery, args, params)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(ctx, baseQuery, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, baseQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error ejecutando query paginada de canciones: %w", err)
 	}
@@ -278,19 +311,25 @@ func (r *songRepository) GetAllPaginated(ctx context.Context, filter domain.Song
 
 	// Crear slice de songs (sin artistas todavia)
 	var songs []domain.Song
-	var songIDs []int64
+	var keys [][]string
 	for rows.Next() {
 		var s domain.Song
-		if err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.CreatedAt, &s.UpdatedAt, &s.CoverURL); err != nil {
+		var rowKeys []string
+		if err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.CreatedAt, &s.UpdatedAt, &s.CoverURL, &s.Score, &rowKeys); err != nil {
 			return nil, fmt.Errorf("error escaneando canción paginada: %w", err)
 		}
 		s.Artists = []domain.ArtistWithRole{}
 		songs = append(songs, s)
-		songIDs = append(songIDs, s.ID)
+		keys = append(keys, rowKeys)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterando canciones paginadas: %w", err)
 	}
+	songs, next := nextPage(songs, keys, order, params)
+	songIDs := make([]int64, len(songs))
+	for i := range songs {
+		songIDs[i] = songs[i].ID
+	}
 
 	// Asignar artistas en bloquea songs
 	if len(songs) > 0 {
@@ -300,7 +339,7 @@ func (r *songRepository) GetAllPaginated(ctx context.Context, filter domain.Song
 			INNER JOIN song_artists asg ON a.id = asg.artist_id
 			WHERE asg.song_id = ANY($1) AND a.deleted_at IS NULL
 		`
-		artistRows, err := r.db.Query(ctx, queryArtists, songIDs)
+		artistRows, err := conn(ctx, r.db).Query(ctx, queryArtists, songIDs)
 		if err != nil {
 			return nil, fmt.Errorf("error obteniendo artistas para canciones paginadas: %w", err)
 		}
@@ -324,13 +363,123 @@ func (r *songRepository) GetAllPaginated(ctx context.Context, filter domain.Song
 		songs = []domain.Song{} // [] vacio en vez de nil
 	}
 
-	return domain.NewPaginatedResult(songs, totalItems, params.Page, params.Limit), nil
+	return domain.NewPageResult(songs, params, totalItems, next), nil
+}
+
+// Canciones vigentes del artista (con cualquier rol), ordenadas como su discografía:
+// por fecha del álbum, disco y pista. Las canciones sin álbum quedan al final por título
+func (r *songRepository) GetByArtistID(ctx context.Context, artistID int64) ([]domain.Song, error) {
+	query := `
+		SELECT s.id, s.title, s.duration, s.created_at, s.updated_at, s.file_path, s.audio_key IS NOT NULL, al.cover_url,
+			COALESCE(
+				(SELECT jsonb_agg(jsonb_build_object('id', a.id, 'name', a.name, 'role', sa.role))
+				FROM song_artists sa
+				INNER JOIN artists a ON sa.artist_id = a.id
+				WHERE sa.song_id = s.id AND a.deleted_at IS NULL
+			), '[]') AS artists
+		FROM songs s
+		LEFT JOIN LATERAL (
+			SELECT a.cover_url, a.release_date, t.disc_number, t.track_number
+			FROM tracks t
+			INNER JOIN albums a ON t.album_id = a.id AND a.deleted_at IS NULL
+			WHERE t.song_id = s.id
+			ORDER BY a.release_date
+			LIMIT 1
+		) al ON true
+		WHERE s.deleted_at IS NULL
+			AND s.id IN (SELECT song_id FROM song_artists WHERE artist_id = $1)
+		ORDER BY al.release_date NULLS LAST, al.disc_number, al.track_number, s.title
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, artistID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo canciones del artista %d: %w", artistID, err)
+	}
+	defer rows.Close()
+
+	songs := []domain.Song{}
+	for rows.Next() {
+		var s domain.Song
+		var artistsJSON []byte
+		if err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.CreatedAt, &s.UpdatedAt, &s.FilePath, &s.HasAudio, &s.CoverURL, &artistsJSON); err != nil {
+			return nil, fmt.Errorf("error escaneando canción del artista: %w", err)
+		}
+		if err := json.Unmarshal(artistsJSON, &s.Artists); err != nil {
+			return nil, fmt.Errorf("error unmarshaling artistas: %w", err)
+		}
+		songs = append(songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando canciones del artista: %w", err)
+	}
+	return songs, nil
+}
+
+// SetFilePath registra (o borra con nil) la ubicación local del audio. No cambia updated_at:
+// es un dato del archivo y no una edición del catálogo
+func (r *songRepository) SetFilePath(ctx context.Context, id int64, path *string) error {
+	query := `UPDATE songs SET file_path = $2 WHERE id = $1 AND deleted_at IS NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, path)
+	if err != nil {
+		return fmt.Errorf("error actualizando la ruta del archivo de la canción %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrSongNotFound
+	}
+	return nil
+}
+
+func (r *songRepository) GetAudio(ctx context.Context, id int64) (*domain.SongAudio, error) {
+	query := `SELECT audio_key, audio_content_type, audio_size FROM songs WHERE id = $1 AND deleted_at IS NULL`
+	var key, contentType *string
+	var size *int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&key, &contentType, &size); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSongNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo el audio de la canción %d: %w", id, err)
+	}
+	if key == nil {
+		return nil, domain.ErrSongAudioNotFound
+	}
+
+	audio := &domain.SongAudio{Key: *key}
+	if contentType != nil {
+		audio.ContentType = *contentType
+	}
+	if size != nil {
+		audio.Size = *size
+	}
+	return audio, nil
+}
+
+func (r *songRepository) SetAudio(ctx context.Context, id int64, audio *domain.SongAudio, duration int) error {
+	var key, contentType *string
+	var size *int64
+	if audio != nil {
+		key, contentType, size = &audio.Key, &audio.ContentType, &audio.Size
+	}
+
+	query := `
+		UPDATE songs
+		SET audio_key = $2, audio_content_type = $3, audio_size = $4,
+			duration = CASE WHEN $5::int > 0 THEN $5::int ELSE duration END,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, key, contentType, size, duration)
+	if err != nil {
+		return fmt.Errorf("error guardando el audio de la canción %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrSongNotFound
+	}
+	return nil
 }
 
 // UPDATE
 // PUT clasico, actualiza todo slos datos de la tabla principal, elimina las relaciones existentes y las inserta de nuevo.
-func (r *songRepository) Update(ctx context.Context, id int64, input *domain.SongInput) (*domain.Song, error) {
-	tx, err := r.db.Begin(ctx)
+func (r *songRepository) Update(ctx context.Context, id int64, input *domain.SongInput, version *time.Time) (*domain.Song, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error iniciando transacción para update: %w", err)
 	}
@@ -340,17 +489,18 @@ func (r *songRepository) Update(ctx context.Context, id int64, input *domain.Son
 	updateSongQuery := `
 		UPDATE songs
 		SET title = $1, duration = $2, updated_at = NOW()
-		WHERE id = $3 AND deleted_at IS NULL
+		WHERE id = $3 AND deleted_at IS NULL AND ($4::timestamp IS NULL OR updated_at = $4)
 	`
 	// Usar Exec porque no necesitamos hacer Scan de nada. Solo saber si afecto una fila
-	res, err := tx.Exec(ctx, updateSongQuery, input.Title, input.Duration, id)
+	res, err := tx.Exec(ctx, updateSongQuery, input.Title, input.Duration, id, versionArg(version))
 	if err != nil {
 		return nil, fmt.Errorf("error actualizando los datos de la canción: %w", err)
 	}
 
-	// Si no se afectó ninguna fila, es porque el ID no existe o la canción fue borrada lógicamente
+	// Si no se afectó ninguna fila, es porque el ID no existe, la canción fue borrada lógicamente
+	// o la versión enviada ya no es la actual
 	if res.RowsAffected() == 0 {
-		return nil, domain.ErrSongNotFound
+		return nil, missingOrStale(ctx, tx, "songs", id, version, domain.ErrSongNotFound)
 	}
 
 	// 2. Limpiar relaciones antiguas
@@ -394,22 +544,154 @@ func (r *songRepository) Update(ctx context.Context, id int64, input *domain.Son
 }
 
 // DELETE
-func (r *songRepository) Delete(ctx context.Context, id int64) error {
-	query := `UPDATE songs SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.Exec(ctx, query, id)
+func (r *songRepository) Delete(ctx context.Context, id int64, version *time.Time) error {
+	query := `
+		UPDATE songs SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::timestamp IS NULL OR updated_at = $2)
+	`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, versionArg(version))
 	if err != nil {
 		return fmt.Errorf("error eliminando a la canción ID %d: %w", id, err)
 	}
+	if res.RowsAffected() == 0 {
+		return missingOrStale(ctx, conn(ctx, r.db), "songs", id, version, domain.ErrSongNotFound)
+	}
+	return nil
+}
+
+// PAPELERA
+
+// Lista canciones con soft delete, las más recientes primero
+func (r *songRepository) GetDeletedPaginated(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM songs WHERE deleted_at IS NOT NULL`
+	if err := conn(ctx, r.db).QueryRow(ctx, countQuery).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("error contando canciones eliminadas: %w", err)
+	}
+
+	query := `
+		SELECT id, title, duration, created_at, updated_at, deleted_at
+		FROM songs
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, params.Limit, params.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo canciones eliminadas: %w", err)
+	}
+	defer rows.Close()
+
+	songs := []domain.Song{}
+	for rows.Next() {
+		var s domain.Song
+		if err := rows.Scan(&s.ID, &s.Title, &s.Duration, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando canción eliminada: %w", err)
+		}
+		s.Artists = []domain.ArtistWithRole{}
+		songs = append(songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando canciones eliminadas: %w", err)
+	}
+
+	return domain.NewPaginatedResult(songs, totalItems, params.Page, params.Limit), nil
+}
+
+// Restore quita el soft delete. Solo aplica a canciones que estén en la papelera
+func (r *songRepository) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE songs SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error restaurando la canción ID %d: %w", id, err)
+	}
 	if res.RowsAffected() == 0 {
 		return domain.ErrSongNotFound
 	}
 	return nil
 }
 
+// Purge elimina definitivamente una canción de la papelera (ver purgeSongs)
+func (r *songRepository) Purge(ctx context.Context, id int64) (*domain.Purged, error) {
+	purged, err := r.purgeSongs(ctx, `s.id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error purgando la canción ID %d: %w", id, err)
+	}
+	if len(purged) == 0 {
+		return nil, domain.ErrSongNotFound
+	}
+	return &purged[0], nil
+}
+
+// Elimina definitivamente las canciones que llevan en la papelera más tiempo que retention
+func (r *songRepository) PurgeDeletedOlderThan(ctx context.Context, retention time.Duration) ([]domain.Purged, error) {
+	purged, err := r.purgeSongs(ctx, `s.deleted_at < NOW() - ($1::float8 * INTERVAL '1 second')`, retention.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error purgando canciones eliminadas: %w", err)
+	}
+	return purged, nil
+}
+
+// purgeSongs borra las canciones en papelera que cumplen cond (sobre el alias s, con arg como $1) y
+// retorna sus claves de audio. Tracks, artistas y entradas de playlist caen en cascada; las playlists
+// que pierden entradas se renumeran en la misma transacción para no dejar huecos en las posiciones
+func (r *songRepository) purgeSongs(ctx context.Context, cond string, arg any) ([]domain.Purged, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción para purgar: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Playlists afectadas, bloqueadas como en lockPlaylist para no competir con altas concurrentes
+	queryAffected := `
+		SELECT p.id FROM playlists p
+		WHERE p.id IN (
+			SELECT ps.playlist_id FROM playlist_songs ps
+			INNER JOIN songs s ON ps.song_id = s.id
+			WHERE s.deleted_at IS NOT NULL AND ` + cond + `
+		)
+		ORDER BY p.id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, queryAffected, arg)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo playlists afectadas: %w", err)
+	}
+	playlistIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("error escaneando playlists afectadas: %w", err)
+	}
+
+	queryDelete := `DELETE FROM songs s WHERE s.deleted_at IS NOT NULL AND ` + cond + ` RETURNING s.id, s.audio_key`
+	rows, err = tx.Query(ctx, queryDelete, arg)
+	if err != nil {
+		return nil, err
+	}
+	purged, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.Purged])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := renumberPlaylists(ctx, tx, playlistIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error confirmando la transacción de purga: %w", err)
+	}
+	return purged, nil
+}
+
 // Add Remove Artist
+// Los cambios de artistas también actualizan updated_at de la canción (su versión/ETag)
 func (r *songRepository) AddArtist(ctx context.Context, songID int64, input *domain.ArtistSongInput) error {
-	query := `INSERT INTO song_artists (song_id, artist_id, role) VALUES ($1, $2, $3)`
-	_, err := r.db.Exec(ctx, query, songID, input.ArtistID, input.Role)
+	query := `
+		WITH inserted AS (
+			INSERT INTO song_artists (song_id, artist_id, role) VALUES ($1, $2, $3)
+		)
+		UPDATE songs SET updated_at = NOW() WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, songID, input.ArtistID, input.Role)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -422,14 +704,20 @@ func (r *songRepository) AddArtist(ctx context.Context, songID int64, input *dom
 				return domain.ErrArtistNotInDB
 			}
 		}
+		return fmt.Errorf("error agregando artista a la canción %d: %w", songID, err)
 	}
 	return nil
 }
 
 func (r *songRepository) RemoveArtist(ctx context.Context, songID, artistID int64) error {
-	query := `DELETE FROM song_artists WHERE song_id = $1 AND artist_id = $2`
+	query := `
+		WITH removed AS (
+			DELETE FROM song_artists WHERE song_id = $1 AND artist_id = $2 RETURNING song_id
+		)
+		UPDATE songs SET updated_at = NOW() WHERE id IN (SELECT song_id FROM removed)
+	`
 
-	res, err := r.db.Exec(ctx, query, songID, artistID)
+	res, err := conn(ctx, r.db).Exec(ctx, query, songID, artistID)
 	if err != nil {
 		return fmt.Errorf("error eliminando el artista %d del álbum %d: %w", artistID, songID, err)
 	}
@@ -449,13 +737,13 @@ func (r *songRepository) SearchSongs(ctx context.Context, searchTerm string) ([]
 		FROM songs s
 		JOIN song_artists sa ON s.id = sa.song_id
 		JOIN artists a ON sa.artist_id = a.id
-		WHERE (s.title % $1 OR a.name % $1)
+		WHERE (normalize_text(s.title) % $1 OR normalize_text(a.name) % $1)
 		GROUP BY s.id, s.title
-		ORDER BY similarity(s.title, $1) DESC
+		ORDER BY similarity(normalize_text(s.title), $1) DESC
 		LIMIT 15;
 	`
 
-	rows, err := r.db.Query(ctx, query, searchTerm)
+	rows, err := conn(ctx, r.db).Query(ctx, query, validation.NormalizeText(searchTerm))
 	if err != nil {
 		return nil, fmt.Errorf("error buscando canciones: %w", err)
 	}
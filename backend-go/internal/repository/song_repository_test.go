@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Purgar una canción que está en una playlist no debe dejar huecos en las posiciones,
+// ni en las guardadas ni en las que ve el cliente mientras la canción está en la papelera
+func TestPurgeRenumbersPlaylists(t *testing.T) {
+	ctx, pool := testDB(t)
+	exec(t, ctx, pool, `SET CONSTRAINTS ALL IMMEDIATE`)
+
+	songs := NewSongRepository(pool)
+	playlists := NewPlaylistRepository(pool)
+	playlist, err := playlists.Create(ctx, &domain.PlaylistInput{Name: "Prueba purga"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	songIDs := insertSongs(t, ctx, pool, 3)
+	for _, id := range songIDs {
+		if _, err := playlists.AddEntry(ctx, playlist.ID, &domain.PlaylistEntryInput{SongID: id}); err != nil {
+			t.Fatalf("AddEntry: %v", err)
+		}
+	}
+
+	// En la papelera la canción conserva su lugar, pero las posiciones visibles no saltan
+	exec(t, ctx, pool, `UPDATE songs SET deleted_at = NOW() - INTERVAL '1 day' WHERE id = $1`, songIDs[1])
+	got, err := playlists.GetByID(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	assertPositions(t, entryPositions(got.Entries), []int{1, 2})
+
+	if _, err := songs.PurgeDeletedOlderThan(ctx, time.Hour); err != nil {
+		t.Fatalf("PurgeDeletedOlderThan: %v", err)
+	}
+
+	assertPositions(t, storedPositions(t, ctx, pool, playlist.ID), []int{1, 2})
+}
+
+func entryPositions(entries []domain.PlaylistEntry) []int {
+	positions := make([]int, len(entries))
+	for i, e := range entries {
+		positions[i] = e.Position
+	}
+	return positions
+}
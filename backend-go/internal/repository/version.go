@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// Control de concurrencia optimista: la versión de un registro es su updated_at (ver ETag en handler).
+// Los UPDATE condicionados agregan "AND ($n::timestamp IS NULL OR updated_at = $n)" con el valor de
+// versionArg, asi una versión nil no impone precondición
+
+// versionArg prepara la versión para compararla con updated_at, que es TIMESTAMP sin zona. pgx envía
+// la hora de pared del time.Time, por eso se pasa a UTC, la misma con que pgx entrega la columna al leerla.
+// Con un parámetro timestamptz Postgres convertiría la columna con el TimeZone de la sesión y la
+// igualdad solo se cumpliría en sesiones UTC
+func versionArg(version *time.Time) any {
+	if version == nil {
+		return nil
+	}
+	return version.UTC()
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// missingOrStale se usa cuando un UPDATE condicionado por versión no afectó filas: si el registro
+// sigue vigente es porque el cliente tenía una versión vieja, si no, el registro no existe
+func missingOrStale(ctx context.Context, q rowQuerier, table string, id int64, version *time.Time, notFound error) error {
+	if version == nil {
+		return notFound
+	}
+
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)`, table)
+	if err := q.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("error verificando versión en %s ID %d: %w", table, id, err)
+	}
+	if exists {
+		return domain.ErrPreconditionFailed
+	}
+	return notFound
+}
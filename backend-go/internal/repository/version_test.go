@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+func TestVersionArg(t *testing.T) {
+	if got := versionArg(nil); got != nil {
+		t.Fatalf("versionArg(nil) = %v, se esperaba nil", got)
+	}
+
+	santiago := time.FixedZone("CLT", -3*60*60)
+	version := time.Date(2026, 10, 16, 17, 30, 0, 123456000, santiago)
+	got, ok := versionArg(&version).(time.Time)
+	if !ok {
+		t.Fatalf("versionArg devolvió %T, se esperaba time.Time", versionArg(&version))
+	}
+	if got.Location() != time.UTC || !got.Equal(version) {
+		t.Errorf("versionArg = %v, se esperaba %v en UTC", got, version.UTC())
+	}
+}
+
+// La comparación de versiones no debe depender del TimeZone de la sesión
+func TestUpdateWithVersionNonUTCSession(t *testing.T) {
+	ctx, pool := testDB(t)
+	exec(t, ctx, pool, `SET LOCAL TimeZone = 'America/Santiago'`)
+
+	repo := NewArtistRepository(pool)
+	created, err := repo.Create(ctx, &domain.ArtistInput{Name: "Prueba Versión", Genre: "Rock", Country: "Chile"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	current, err := repo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	// La versión viaja por el ETag en microsegundos y vuelve en la zona local del servidor
+	version := time.UnixMicro(current.UpdatedAt.UnixMicro())
+	input := current.ToInput()
+	input.Genre = "Pop"
+	updated, err := repo.Update(ctx, created.ID, &input, &version)
+	if err != nil {
+		t.Fatalf("Update con la versión vigente: %v", err)
+	}
+
+	// La versión anterior ya no es la vigente
+	exec(t, ctx, pool, `UPDATE artists SET updated_at = updated_at + INTERVAL '1 second' WHERE id = $1`, created.ID)
+	stale := time.UnixMicro(updated.UpdatedAt.UnixMicro())
+	if _, err := repo.Update(ctx, created.ID, &input, &stale); !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Fatalf("Update con versión vieja: err = %v, se esperaba ErrPreconditionFailed", err)
+	}
+}
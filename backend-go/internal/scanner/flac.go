@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Lectura de FLAC: STREAMINFO da la duración exacta (muestras / frecuencia) y el bloque
+// VORBIS_COMMENT trae las etiquetas
+
+var errNoFLAC = errors.New("el archivo no es un FLAC válido")
+
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+)
+
+// ReadFLAC lee los bloques de metadatos del inicio del archivo; el audio no se recorre
+func ReadFLAC(path string) (*Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	// Algunos programas anteponen una etiqueta ID3v2 al FLAC, se salta
+	if head, err := r.Peek(id3HeaderSize); err == nil && string(head[:3]) == "ID3" {
+		h, err := readID3Header(r)
+		if err != nil {
+			return nil, errNoFLAC
+		}
+		if _, err := r.Discard(h.tagSize - id3HeaderSize); err != nil {
+			return nil, errNoFLAC
+		}
+	}
+
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || string(magic[:]) != "fLaC" {
+		return nil, errNoFLAC
+	}
+
+	t := &Track{Path: path}
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, fmt.Errorf("metadatos FLAC truncados: %w", err)
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7F
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+
+		switch blockType {
+		case flacStreamInfo, flacVorbisComment:
+			block := make([]byte, size)
+			if _, err := io.ReadFull(r, block); err != nil {
+				return nil, fmt.Errorf("metadatos FLAC truncados: %w", err)
+			}
+			if blockType == flacStreamInfo {
+				t.Duration = flacDuration(block)
+			} else {
+				if err := parseVorbisComment(block, t); err != nil {
+					return nil, err
+				}
+			}
+		default: // Imágenes, seektable, padding...
+			if _, err := r.Discard(size); err != nil {
+				return nil, fmt.Errorf("metadatos FLAC truncados: %w", err)
+			}
+		}
+		if last {
+			break
+		}
+	}
+
+	return t, nil
+}
+
+// flacDuration usa la frecuencia (20 bits) y el total de muestras (36 bits) de STREAMINFO
+func flacDuration(info []byte) int {
+	if len(info) < 18 {
+		return 0
+	}
+	packed := binary.BigEndian.Uint64(info[10:18])
+	sampleRate := packed >> 44
+	totalSamples := packed & 0xFFFFFFFFF
+	if sampleRate == 0 || totalSamples == 0 { // 0 muestras = desconocido
+		return 0
+	}
+	return roundSeconds(float64(totalSamples) / float64(sampleRate))
+}
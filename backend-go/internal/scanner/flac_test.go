@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// flacBlock arma un bloque de metadatos: bit de último bloque + tipo y tamaño de 24 bits
+func flacBlock(last bool, blockType byte, data []byte) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	size := len(data)
+	return concat([]byte{blockType, byte(size >> 16), byte(size >> 8), byte(size)}, data)
+}
+
+// streamInfo arma un STREAMINFO de 34 bytes, estéreo de 16 bits, con la frecuencia (20 bits) y el total
+// de muestras (36 bits)
+func streamInfo(sampleRate, totalSamples uint64) []byte {
+	info := make([]byte, 34)
+	binary.BigEndian.PutUint64(info[10:18], sampleRate<<44|1<<41|15<<36|totalSamples)
+	return info
+}
+
+// writeFixture deja el archivo en un directorio temporal; los lectores reciben rutas
+func writeFixture(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("escribiendo %s: %v", name, err)
+	}
+	return path
+}
+
+func TestReadFLAC(t *testing.T) {
+	tags := vorbisComment("reference libFLAC 1.4.3", "TITLE=Persiana americana", "ARTIST=Soda Stereo", "ARTIST=Gustavo Cerati", "TRACKNUMBER=5")
+	tagged := Track{Title: "Persiana americana", Artists: []string{"Soda Stereo", "Gustavo Cerati"}, TrackNumber: 5}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Track
+		wantErr error
+	}{
+		{
+			name: "duración y etiquetas",
+			data: concat([]byte("fLaC"),
+				flacBlock(false, flacStreamInfo, streamInfo(44100, 44100*215+22050)),
+				flacBlock(true, flacVorbisComment, tags)),
+			want: withDuration(tagged, 216),
+		},
+		{
+			name: "etiqueta ID3 antepuesta",
+			data: concat([]byte("ID3"), []byte{3, 0, 0}, synchsafeBytes(20), make([]byte, 20), []byte("fLaC"),
+				flacBlock(false, flacStreamInfo, streamInfo(48000, 48000*90)),
+				flacBlock(true, flacVorbisComment, tags)),
+			want: withDuration(tagged, 90),
+		},
+		{
+			name: "salta padding y otros bloques",
+			data: concat([]byte("fLaC"),
+				flacBlock(false, flacStreamInfo, streamInfo(44100, 44100*60)),
+				flacBlock(false, 3, make([]byte, 18)), // seektable
+				flacBlock(false, flacVorbisComment, tags),
+				flacBlock(true, 1, make([]byte, 1024))), // padding
+			want: withDuration(tagged, 60),
+		},
+		{
+			name: "total de muestras desconocido",
+			data: concat([]byte("fLaC"), flacBlock(true, flacStreamInfo, streamInfo(44100, 0))),
+			want: Track{},
+		},
+		{
+			name:    "no es FLAC",
+			data:    []byte("OggS\x00\x02 no es un FLAC"),
+			wantErr: errNoFLAC,
+		},
+		{
+			name:    "ID3 sin FLAC detrás",
+			data:    concat([]byte("ID3"), []byte{3, 0, 0}, synchsafeBytes(4), []byte("RIFF....")),
+			wantErr: errNoFLAC,
+		},
+		{
+			name:    "comentarios con largo fuera del bloque",
+			data:    concat([]byte("fLaC"), flacBlock(true, flacVorbisComment, concat(le32(0), le32(1), le32(1<<20), []byte("TITLE=x")))),
+			wantErr: errVorbisComment,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFixture(t, "pista.flac", tt.data)
+			got, err := ReadFLAC(path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFLAC: %v", err)
+			}
+			tt.want.Path = path
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ReadFLAC = %+v, se esperaba %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// Bloques cortados antes de lo que declara su cabecera o sin bloque final
+func TestReadFLACTruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "sin bloques", data: []byte("fLaC")},
+		{name: "STREAMINFO incompleto", data: concat([]byte("fLaC"), flacBlock(true, flacStreamInfo, streamInfo(44100, 1))[:20])},
+		{name: "padding incompleto", data: concat([]byte("fLaC"), flacBlock(true, 1, make([]byte, 100))[:50])},
+		{name: "falta el último bloque", data: concat([]byte("fLaC"), flacBlock(false, flacStreamInfo, streamInfo(44100, 1)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadFLAC(writeFixture(t, "pista.flac", tt.data)); err == nil {
+				t.Fatal("se esperaba error de metadatos truncados")
+			}
+		})
+	}
+}
+
+func withDuration(t Track, seconds int) Track {
+	t.Duration = seconds
+	return t
+}
@@ -0,0 +1,313 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Lectura de etiquetas ID3v2 (2.2, 2.3 y 2.4) solo con la librería estándar.
+// Se leen los frames de texto necesarios para el catálogo; imágenes, comentarios, etc. se ignoran
+
+var errNoID3 = errors.New("el archivo no tiene etiqueta ID3v2")
+
+// id3Header son los 10 bytes iniciales de la etiqueta
+type id3Header struct {
+	major   byte
+	flags   byte
+	size    int // Tamaño del cuerpo, sin cabecera ni footer
+	tagSize int // Bytes totales que ocupa la etiqueta al inicio del archivo
+}
+
+const (
+	id3FlagUnsync    = 0x80
+	id3FlagExtended  = 0x40
+	id3FlagFooter    = 0x10
+	id3HeaderSize    = 10
+	id3MaxSupportVer = 4
+)
+
+func readID3Header(r io.Reader) (*id3Header, error) {
+	var buf [id3HeaderSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, errNoID3
+	}
+	if string(buf[:3]) != "ID3" || buf[3] < 2 || buf[3] > id3MaxSupportVer {
+		return nil, errNoID3
+	}
+	h := &id3Header{major: buf[3], flags: buf[5], size: synchsafe(buf[6:10])}
+	h.tagSize = id3HeaderSize + h.size
+	if h.flags&id3FlagFooter != 0 {
+		h.tagSize += id3HeaderSize
+	}
+	return h, nil
+}
+
+// parseID3v2 llena los campos de t a partir del cuerpo de la etiqueta
+func parseID3v2(h *id3Header, body []byte, t *Track) {
+	// En 2.2 y 2.3 la desincronización aplica a toda la etiqueta; en 2.4 es por frame
+	if h.major < 4 && h.flags&id3FlagUnsync != 0 {
+		body = removeUnsync(body)
+	}
+	if h.flags&id3FlagExtended != 0 && h.major >= 3 && len(body) >= 4 {
+		var extSize int
+		if h.major == 3 {
+			extSize = int(binary.BigEndian.Uint32(body[:4])) + 4 // En 2.3 el tamaño no se incluye a sí mismo
+		} else {
+			extSize = synchsafe(body[:4])
+		}
+		if extSize > len(body) {
+			return
+		}
+		body = body[extSize:]
+	}
+
+	idLen, headerLen := 4, 10
+	if h.major == 2 {
+		idLen, headerLen = 3, 6
+	}
+
+	for len(body) >= headerLen {
+		id := string(body[:idLen])
+		if body[0] == 0 { // Inicio del padding
+			break
+		}
+
+		var size int
+		var formatFlags byte
+		switch h.major {
+		case 2:
+			size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(body[4:8]))
+			formatFlags = body[9]
+		default:
+			size = synchsafe(body[4:8])
+			formatFlags = body[9]
+		}
+		if size <= 0 || headerLen+size > len(body) {
+			break
+		}
+		data := body[headerLen : headerLen+size]
+		body = body[headerLen+size:]
+
+		data, ok := frameData(h, formatFlags, data)
+		if !ok {
+			continue
+		}
+		applyID3Frame(id, data, t)
+	}
+}
+
+// frameData quita los bytes extra que agregan los flags de formato del frame.
+// Frames comprimidos o cifrados se descartan (ok = false)
+func frameData(h *id3Header, flags byte, data []byte) ([]byte, bool) {
+	switch h.major {
+	case 3:
+		if flags&0x80 != 0 || flags&0x40 != 0 { // Compresión / cifrado
+			return nil, false
+		}
+		if flags&0x20 != 0 && len(data) > 0 { // Byte de grupo
+			data = data[1:]
+		}
+	case 4:
+		if flags&0x08 != 0 || flags&0x04 != 0 {
+			return nil, false
+		}
+		if flags&0x40 != 0 && len(data) > 0 {
+			data = data[1:]
+		}
+		if flags&0x01 != 0 && len(data) >= 4 { // Indicador de largo de datos
+			data = data[4:]
+		}
+		if flags&0x02 != 0 || h.flags&id3FlagUnsync != 0 {
+			data = removeUnsync(data)
+		}
+	}
+	return data, true
+}
+
+func applyID3Frame(id string, data []byte, t *Track) {
+	switch id {
+	case "TIT2", "TT2":
+		t.Title = firstValue(decodeText(data))
+	case "TPE1", "TP1":
+		t.Artists = splitArtists(decodeText(data))
+	case "TPE2", "TP2":
+		t.AlbumArtist = firstValue(decodeText(data))
+	case "TALB", "TAL":
+		t.Album = firstValue(decodeText(data))
+	case "TRCK", "TRK":
+		t.TrackNumber = parseNumberOf(firstValue(decodeText(data)))
+	case "TPOS", "TPA":
+		t.DiscNumber = parseNumberOf(firstValue(decodeText(data)))
+	case "TYER", "TYE", "TDRC", "TDOR", "TOR":
+		if t.Year == 0 || id == "TDRC" {
+			t.Year = parseYear(firstValue(decodeText(data)))
+		}
+	case "TCON", "TCO":
+		t.Genre = cleanGenre(firstValue(decodeText(data)))
+	case "TLEN", "TLE":
+		if ms, err := strconv.Atoi(firstValue(decodeText(data))); err == nil && ms > 0 {
+			t.Duration = (ms + 500) / 1000
+		}
+	}
+}
+
+// decodeText decodifica un frame de texto. En 2.4 un frame puede traer varios valores separados por NUL
+func decodeText(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	encoding, raw := data[0], data[1:]
+
+	var text string
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(raw))
+		for i, b := range raw {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	case 1, 2: // UTF-16 con BOM / UTF-16BE
+		text = decodeUTF16(raw, encoding == 2)
+	default: // 3: UTF-8
+		text = string(raw)
+	}
+
+	var values []string
+	for _, v := range strings.Split(text, "\x00") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func decodeUTF16(raw []byte, bigEndian bool) string {
+	var units []uint16
+	for i := 0; i+1 < len(raw); i += 2 {
+		// El BOM puede repetirse al inicio de cada valor de la lista
+		if raw[i] == 0xFF && raw[i+1] == 0xFE {
+			bigEndian = false
+			continue
+		}
+		if raw[i] == 0xFE && raw[i+1] == 0xFF {
+			bigEndian = true
+			continue
+		}
+		if bigEndian {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		} else {
+			units = append(units, uint16(raw[i+1])<<8|uint16(raw[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// parseID3v1 lee los 128 bytes finales "TAG". Solo se usa si el archivo no trae ID3v2
+func parseID3v1(tag []byte, t *Track) bool {
+	if len(tag) != 128 || string(tag[:3]) != "TAG" {
+		return false
+	}
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return strings.TrimSpace(string(runes))
+	}
+	t.Title = field(tag[3:33])
+	if artist := field(tag[33:63]); artist != "" {
+		t.Artists = []string{artist}
+	}
+	t.Album = field(tag[63:93])
+	t.Year = parseYear(field(tag[93:97]))
+	if tag[125] == 0 && tag[126] != 0 { // ID3v1.1: número de pista al final del comentario
+		t.TrackNumber = int(tag[126])
+	}
+	return true
+}
+
+// Helpers
+
+func synchsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// removeUnsync revierte la desincronización: cada 0xFF 0x00 vuelve a ser 0xFF
+func removeUnsync(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		out = append(out, b[i])
+		if b[i] == 0xFF && i+1 < len(b) && b[i+1] == 0x00 {
+			i++
+		}
+	}
+	return out
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// parseNumberOf lee "3" o "3/12"
+func parseNumberOf(value string) int {
+	value, _, _ = strings.Cut(value, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseYear toma los 4 primeros dígitos de "1997", "1997-05-20" o "1997-05-20T10:00"
+func parseYear(value string) int {
+	if len(value) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(value[:4])
+	if err != nil || year < 1000 {
+		return 0
+	}
+	return year
+}
+
+// cleanGenre quita las referencias numéricas de ID3v1 como "(17)" o "(17)Rock"
+func cleanGenre(value string) string {
+	for strings.HasPrefix(value, "(") {
+		end := strings.IndexByte(value, ')')
+		if end < 0 {
+			break
+		}
+		value = value[end+1:]
+	}
+	if _, err := strconv.Atoi(value); err == nil {
+		return "" // Solo número de género, sin nombre
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitArtists separa los valores de TPE1. Además de NUL (2.4) se acepta ";" usado en 2.3.
+// "/" no se usa como separador porque aparece en nombres como "AC/DC"
+func splitArtists(values []string) []string {
+	var artists []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ";") {
+			if part = strings.TrimSpace(part); part != "" {
+				artists = append(artists, part)
+			}
+		}
+	}
+	return artists
+}
@@ -0,0 +1,195 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// id3Frame arma un frame con la cabecera de la versión indicada. flags son los de formato (segundo byte)
+func id3Frame(major byte, id string, flags byte, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(id)
+	size := len(data)
+	switch major {
+	case 2:
+		b.Write([]byte{byte(size >> 16), byte(size >> 8), byte(size)})
+	case 3:
+		binary.Write(&b, binary.BigEndian, uint32(size))
+		b.Write([]byte{0, flags})
+	default:
+		b.Write(synchsafeBytes(size))
+		b.Write([]byte{0, flags})
+	}
+	b.Write(data)
+	return b.Bytes()
+}
+
+func synchsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// latin1 y utf8 arman el cuerpo de un frame de texto con su byte de codificación
+func latin1(s string) []byte {
+	b := []byte{0}
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return b
+}
+
+func utf8(s string) []byte { return append([]byte{3}, s...) }
+
+func concat(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+func TestParseID3v2(t *testing.T) {
+	tests := []struct {
+		name   string
+		header id3Header
+		body   []byte
+		want   Track
+	}{
+		{
+			name:   "2.3 campos básicos",
+			header: id3Header{major: 3},
+			body: concat(
+				id3Frame(3, "TIT2", 0, latin1("Canción")),
+				id3Frame(3, "TPE1", 0, latin1("Soda Stereo; Andrea Echeverri")),
+				id3Frame(3, "TPE2", 0, latin1("Soda Stereo")),
+				id3Frame(3, "TALB", 0, latin1("Comfort y Música Para Volar")),
+				id3Frame(3, "TRCK", 0, latin1("3/12")),
+				id3Frame(3, "TPOS", 0, latin1("1/2")),
+				id3Frame(3, "TYER", 0, latin1("1996")),
+				id3Frame(3, "TCON", 0, latin1("(17)Rock")),
+				id3Frame(3, "TLEN", 0, latin1("181500")),
+			),
+			want: Track{
+				Title: "Canción", Artists: []string{"Soda Stereo", "Andrea Echeverri"}, AlbumArtist: "Soda Stereo",
+				Album: "Comfort y Música Para Volar", TrackNumber: 3, DiscNumber: 1, Year: 1996, Genre: "Rock", Duration: 182,
+			},
+		},
+		{
+			name:   "2.4 valores múltiples y TDRC sobre TYER",
+			header: id3Header{major: 4},
+			body: concat(
+				id3Frame(4, "TYER", 0, latin1("1990")),
+				id3Frame(4, "TDRC", 0, utf8("2001-05-20")),
+				id3Frame(4, "TPE1", 0, utf8("AC/DC\x00Invitado")),
+			),
+			want: Track{Artists: []string{"AC/DC", "Invitado"}, Year: 2001},
+		},
+		{
+			name:   "2.2 ids de tres letras",
+			header: id3Header{major: 2},
+			body:   concat(id3Frame(2, "TT2", 0, latin1("Vieja")), id3Frame(2, "TP1", 0, latin1("Artista"))),
+			want:   Track{Title: "Vieja", Artists: []string{"Artista"}},
+		},
+		{
+			name:   "el padding corta la lectura",
+			header: id3Header{major: 3},
+			body:   concat(id3Frame(3, "TIT2", 0, latin1("Antes")), make([]byte, 20), id3Frame(3, "TALB", 0, latin1("Después"))),
+			want:   Track{Title: "Antes"},
+		},
+		{
+			name:   "frame con tamaño mayor al cuerpo",
+			header: id3Header{major: 3},
+			body:   concat(id3Frame(3, "TIT2", 0, latin1("Válido")), []byte("TALB\x00\x00\x10\x00\x00\x00corto")),
+			want:   Track{Title: "Válido"},
+		},
+		{
+			name:   "2.3 con cabecera extendida",
+			header: id3Header{major: 3, flags: id3FlagExtended},
+			body:   concat([]byte{0, 0, 0, 6}, make([]byte, 6), id3Frame(3, "TIT2", 0, latin1("Tras extendida"))),
+			want:   Track{Title: "Tras extendida"},
+		},
+		{
+			name:   "cabecera extendida más larga que el cuerpo",
+			header: id3Header{major: 4, flags: id3FlagExtended},
+			body:   concat(synchsafeBytes(500), id3Frame(4, "TIT2", 0, latin1("Ignorado"))),
+			want:   Track{},
+		},
+		{
+			name:   "2.3 frame comprimido se descarta",
+			header: id3Header{major: 3},
+			body:   concat(id3Frame(3, "TIT2", 0x80, latin1("Comprimido")), id3Frame(3, "TALB", 0, latin1("Álbum"))),
+			want:   Track{Album: "Álbum"},
+		},
+		{
+			name:   "2.3 desincronización de toda la etiqueta",
+			header: id3Header{major: 3, flags: id3FlagUnsync},
+			// El tamaño del frame es el de los datos ya resincronizados
+			body: bytes.Replace(id3Frame(3, "TIT2", 0, []byte{0, 'A', 0xFF, 'B'}), []byte{0xFF}, []byte{0xFF, 0x00}, 1),
+			want: Track{Title: "AÿB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Track
+			parseID3v2(&tt.header, tt.body, &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseID3v2 = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameData(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6}
+	tests := []struct {
+		name   string
+		header id3Header
+		flags  byte
+		data   []byte
+		want   []byte
+		wantOK bool
+	}{
+		{name: "2.3 sin flags", header: id3Header{major: 3}, data: data, want: data, wantOK: true},
+		{name: "2.3 comprimido", header: id3Header{major: 3}, flags: 0x80, data: data, wantOK: false},
+		{name: "2.3 cifrado", header: id3Header{major: 3}, flags: 0x40, data: data, wantOK: false},
+		{name: "2.3 byte de grupo", header: id3Header{major: 3}, flags: 0x20, data: data, want: data[1:], wantOK: true},
+		{name: "2.4 comprimido", header: id3Header{major: 4}, flags: 0x08, data: data, wantOK: false},
+		{name: "2.4 cifrado", header: id3Header{major: 4}, flags: 0x04, data: data, wantOK: false},
+		{name: "2.4 byte de grupo", header: id3Header{major: 4}, flags: 0x40, data: data, want: data[1:], wantOK: true},
+		{name: "2.4 largo de datos", header: id3Header{major: 4}, flags: 0x01, data: data, want: data[4:], wantOK: true},
+		{name: "2.4 grupo y largo de datos", header: id3Header{major: 4}, flags: 0x41, data: data, want: data[5:], wantOK: true},
+		{name: "2.4 desincronización del frame", header: id3Header{major: 4}, flags: 0x02, data: []byte{0xFF, 0x00, 0xE0}, want: []byte{0xFF, 0xE0}, wantOK: true},
+		{name: "2.4 desincronización de la etiqueta", header: id3Header{major: 4, flags: id3FlagUnsync}, data: []byte{0xFF, 0x00, 0xE0}, want: []byte{0xFF, 0xE0}, wantOK: true},
+		{name: "2.2 sin cambios", header: id3Header{major: 2}, flags: 0xFF, data: data, want: data, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := frameData(&tt.header, tt.flags, tt.data)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, se esperaba %v", ok, tt.wantOK)
+			}
+			if ok && !bytes.Equal(got, tt.want) {
+				t.Errorf("frameData = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeUTF16(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       []byte
+		bigEndian bool
+		want      string
+	}{
+		{name: "BOM little endian", raw: []byte{0xFF, 0xFE, 'H', 0, 0xE9, 0}, want: "Hé"},
+		{name: "BOM big endian", raw: []byte{0xFE, 0xFF, 0, 'H', 0, 0xE9}, want: "Hé"},
+		{name: "UTF-16BE sin BOM", raw: []byte{0, 'O', 0, 'K'}, bigEndian: true, want: "OK"},
+		{name: "BOM repetido por valor", raw: []byte{0xFF, 0xFE, 'A', 0, 0, 0, 0xFE, 0xFF, 0, 'B'}, want: "A\x00B"},
+		{name: "par sustituto", raw: []byte{0xFF, 0xFE, 0x3C, 0xD8, 0xB5, 0xDF}, want: "🎵"},
+		{name: "byte suelto al final se ignora", raw: []byte{0xFF, 0xFE, 'A', 0, 'B'}, want: "A"},
+		{name: "vacío", raw: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeUTF16(tt.raw, tt.bigEndian); got != tt.want {
+				t.Errorf("decodeUTF16 = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
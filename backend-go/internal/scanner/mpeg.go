@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Cálculo de la duración de un MP3 a partir del primer frame MPEG de audio.
+// Con cabecera Xing/Info o VBRI se usa el total de frames (exacto también en VBR);
+// si no, se estima con el bitrate del primer frame (CBR)
+
+// Bitrates en kbps por [versión MPEG-1 / MPEG-2(.5)][capa][índice]
+var mpegBitrates = [2][3][16]int{
+	{ // MPEG-1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0}, // Capa I
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},    // Capa II
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},     // Capa III
+	},
+	{ // MPEG-2 y 2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+// Frecuencias de muestreo por versión (índice según los bits de versión: 0 = 2.5, 2 = 2, 3 = 1)
+var mpegSampleRates = map[byte][3]int{
+	0: {11025, 12000, 8000},
+	2: {22050, 24000, 16000},
+	3: {44100, 48000, 32000},
+}
+
+type mpegFrame struct {
+	version    byte // 0 = MPEG-2.5, 2 = MPEG-2, 3 = MPEG-1
+	layer      int  // 1, 2 o 3
+	bitrate    int  // kbps
+	sampleRate int
+	mono       bool
+}
+
+func (f mpegFrame) samplesPerFrame() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != 3:
+		return 576
+	default:
+		return 1152
+	}
+}
+
+func parseMPEGHeader(b []byte) (mpegFrame, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mpegFrame{}, false
+	}
+	version := (b[1] >> 3) & 0x03
+	layerBits := (b[1] >> 1) & 0x03
+	bitrateIdx := b[2] >> 4
+	rateIdx := (b[2] >> 2) & 0x03
+	rates, ok := mpegSampleRates[version]
+	if !ok || layerBits == 0 || bitrateIdx == 0 || bitrateIdx == 15 || rateIdx == 3 {
+		return mpegFrame{}, false
+	}
+
+	f := mpegFrame{version: version, layer: 4 - int(layerBits), sampleRate: rates[rateIdx], mono: b[3]>>6 == 3}
+	table := 0
+	if version != 3 {
+		table = 1
+	}
+	f.bitrate = mpegBitrates[table][f.layer-1][bitrateIdx]
+	return f, true
+}
+
+// mpegDuration recibe los datos de audio (sin la etiqueta ID3v2) y retorna la duración en segundos.
+// audioSize es el tamaño total del audio, para la estimación CBR cuando data es solo el inicio
+func mpegDuration(data []byte, audioSize int64) int {
+	for i := 0; i+4 <= len(data); i++ {
+		f, ok := parseMPEGHeader(data[i:])
+		if !ok {
+			continue
+		}
+		frame := data[i:]
+
+		if frames := xingFrames(frame, f); frames > 0 {
+			return roundSeconds(float64(frames) * float64(f.samplesPerFrame()) / float64(f.sampleRate))
+		}
+		if frames := vbriFrames(frame); frames > 0 {
+			return roundSeconds(float64(frames) * float64(f.samplesPerFrame()) / float64(f.sampleRate))
+		}
+		remaining := audioSize - int64(i)
+		if remaining <= 0 || f.bitrate == 0 {
+			return 0
+		}
+		return roundSeconds(float64(remaining*8) / float64(f.bitrate*1000))
+	}
+	return 0
+}
+
+// xingFrames lee la cabecera Xing/Info que los encoders escriben en el primer frame
+func xingFrames(frame []byte, f mpegFrame) int {
+	// Offset según versión y canales (lado de información de capa III)
+	offset := 4 + 32
+	switch {
+	case f.version == 3 && f.mono:
+		offset = 4 + 17
+	case f.version != 3 && !f.mono:
+		offset = 4 + 17
+	case f.version != 3 && f.mono:
+		offset = 4 + 9
+	}
+	if len(frame) < offset+12 {
+		return 0
+	}
+	tag := string(frame[offset : offset+4])
+	if tag != "Xing" && tag != "Info" {
+		return 0
+	}
+	flags := binary.BigEndian.Uint32(frame[offset+4 : offset+8])
+	if flags&0x01 == 0 { // Sin campo de frames
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(frame[offset+8 : offset+12]))
+}
+
+// vbriFrames lee la cabecera VBRI de Fraunhofer, siempre 32 bytes después de la cabecera del frame
+func vbriFrames(frame []byte) int {
+	const offset = 4 + 32
+	if len(frame) < offset+18 || !bytes.Equal(frame[offset:offset+4], []byte("VBRI")) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(frame[offset+14 : offset+18]))
+}
+
+func roundSeconds(seconds float64) int {
+	return int(seconds + 0.5)
+}
@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Lectura de Ogg Vorbis: el primer paquete (identificación) trae la frecuencia, el segundo los
+// comentarios y la duración sale de la posición granular de la última página del stream
+
+var errNoOgg = errors.New("el archivo no es un Ogg Vorbis válido")
+
+const (
+	oggPageHeaderSize = 27
+	oggMaxHeaderSize  = 16 << 20 // Tope para el paquete de comentarios (puede traer carátulas)
+	oggTailSize       = 64 * 1024
+)
+
+type oggPage struct {
+	granule  int64
+	serial   uint32
+	segments []byte
+}
+
+func readOggPage(r io.Reader) (*oggPage, error) {
+	var h [oggPageHeaderSize]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return nil, err
+	}
+	if string(h[:4]) != "OggS" {
+		return nil, errNoOgg
+	}
+	segments := make([]byte, h[26])
+	if _, err := io.ReadFull(r, segments); err != nil {
+		return nil, err
+	}
+	return &oggPage{
+		granule:  int64(binary.LittleEndian.Uint64(h[6:14])),
+		serial:   binary.LittleEndian.Uint32(h[14:18]),
+		segments: segments,
+	}, nil
+}
+
+// ReadOgg lee los dos primeros paquetes del stream y la última página para la duración
+func ReadOgg(path string) (*Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	// Se arman paquetes uniendo segmentos: un segmento de 255 bytes indica que el paquete sigue
+	var packets [][]byte
+	var current []byte
+	var serial uint32
+	for len(packets) < 2 {
+		page, err := readOggPage(r)
+		if err != nil {
+			return nil, errNoOgg
+		}
+		if len(packets) == 0 && current == nil {
+			serial = page.serial
+		}
+		for _, lace := range page.segments {
+			chunk := make([]byte, lace)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return nil, errNoOgg
+			}
+			if page.serial != serial { // Otro stream multiplexado
+				continue
+			}
+			current = append(current, chunk...)
+			if len(current) > oggMaxHeaderSize {
+				return nil, fmt.Errorf("cabecera Ogg demasiado grande")
+			}
+			if lace < 255 {
+				packets = append(packets, current)
+				current = []byte{}
+			}
+		}
+	}
+
+	ident, comments := packets[0], packets[1]
+	if len(ident) < 16 || !bytes.Equal(ident[:7], []byte("\x01vorbis")) {
+		return nil, errNoOgg
+	}
+	sampleRate := binary.LittleEndian.Uint32(ident[12:16])
+	if len(comments) < 7 || !bytes.Equal(comments[:7], []byte("\x03vorbis")) {
+		return nil, errNoOgg
+	}
+
+	t := &Track{Path: path}
+	if err := parseVorbisComment(comments[7:], t); err != nil {
+		return nil, err
+	}
+
+	granule, err := lastOggGranule(f, serial)
+	if err != nil {
+		return nil, err
+	}
+	if sampleRate > 0 && granule > 0 {
+		t.Duration = roundSeconds(float64(granule) / float64(sampleRate))
+	}
+	return t, nil
+}
+
+// lastOggGranule busca la última página del stream en el final del archivo
+func lastOggGranule(f *os.File, serial uint32) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	start := max(info.Size()-oggTailSize, 0)
+	tail := make([]byte, info.Size()-start)
+	if _, err := f.ReadAt(tail, start); err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+oggPageHeaderSize > len(tail) {
+			continue
+		}
+		page, err := readOggPage(bytes.NewReader(tail[i:]))
+		if err != nil || page.serial != serial || page.granule <= 0 {
+			continue
+		}
+		return page.granule, nil
+	}
+	return 0, nil
+}
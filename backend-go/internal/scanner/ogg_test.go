@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// oggPageBytes arma una página: cabecera de 27 bytes (el CRC no se valida), tabla de segmentos y cuerpo
+func oggPageBytes(granule int64, serial uint32, laces []byte, body []byte) []byte {
+	h := make([]byte, oggPageHeaderSize)
+	copy(h, "OggS")
+	binary.LittleEndian.PutUint64(h[6:14], uint64(granule))
+	binary.LittleEndian.PutUint32(h[14:18], serial)
+	h[26] = byte(len(laces))
+	return concat(h, laces, body)
+}
+
+// lacing devuelve los segmentos de un paquete completo: segmentos de 255 y uno final menor a 255
+func lacing(n int) []byte {
+	laces := make([]byte, 0, n/255+1)
+	for ; n >= 255; n -= 255 {
+		laces = append(laces, 255)
+	}
+	return append(laces, byte(n))
+}
+
+// vorbisIdent arma el paquete de identificación de 30 bytes con la frecuencia en el offset 12
+func vorbisIdent(sampleRate uint32) []byte {
+	return concat([]byte("\x01vorbis"), le32(0), []byte{2}, le32(sampleRate), make([]byte, 12), []byte{0xB8, 0x01})
+}
+
+func vorbisCommentPacket(vendor string, comments ...string) []byte {
+	return concat([]byte("\x03vorbis"), vorbisComment(vendor, comments...), []byte{1})
+}
+
+func TestReadOgg(t *testing.T) {
+	ident := vorbisIdent(44100)
+	comments := vorbisCommentPacket("Xiph.Org libVorbis I 20200704", "TITLE=Tren al sur", "ARTIST=Los Prisioneros", "ARTIST=Invitado", "DATE=1990")
+	tagged := Track{Title: "Tren al sur", Artists: []string{"Los Prisioneros", "Invitado"}, Year: 1990}
+	audio := oggPageBytes(44100*183+100, 1, lacing(40), make([]byte, 40))
+
+	// Comentarios de más de 255 bytes repartidos en dos páginas
+	long := vorbisCommentPacket(strings.Repeat("v", 300), "TITLE=Tren al sur", "ARTIST=Los Prisioneros", "ARTIST=Invitado", "DATE=1990")
+	longLaces := lacing(len(long))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Track
+		wantErr error
+	}{
+		{
+			name: "cabeceras en páginas separadas",
+			data: concat(
+				oggPageBytes(0, 1, lacing(len(ident)), ident),
+				oggPageBytes(0, 1, lacing(len(comments)), comments),
+				audio),
+			want: withDuration(tagged, 183),
+		},
+		{
+			name: "comentarios que cruzan de página",
+			data: concat(
+				oggPageBytes(0, 1, concat(lacing(len(ident)), longLaces[:1]), concat(ident, long[:255])),
+				oggPageBytes(0, 1, longLaces[1:], long[255:]),
+				audio),
+			want: withDuration(tagged, 183),
+		},
+		{
+			name: "ignora páginas de otro stream",
+			data: concat(
+				oggPageBytes(0, 1, lacing(len(ident)), ident),
+				oggPageBytes(0, 2, lacing(30), make([]byte, 30)),
+				oggPageBytes(0, 1, lacing(len(comments)), comments),
+				audio,
+				oggPageBytes(44100*999, 2, lacing(10), make([]byte, 10))),
+			want: withDuration(tagged, 183),
+		},
+		{
+			name: "sin páginas de audio",
+			data: concat(
+				oggPageBytes(0, 1, lacing(len(ident)), ident),
+				oggPageBytes(0, 1, lacing(len(comments)), comments)),
+			want: tagged,
+		},
+		{
+			name:    "no es Ogg",
+			data:    []byte("fLaC\x00\x00\x00\x22 no es un Ogg con 27 bytes"),
+			wantErr: errNoOgg,
+		},
+		{
+			name:    "no es Vorbis",
+			data:    concat(oggPageBytes(0, 1, lacing(19), []byte("OpusHead...........")), oggPageBytes(0, 1, lacing(len(comments)), comments)),
+			wantErr: errNoOgg,
+		},
+		{
+			name:    "paquete más corto que sus segmentos",
+			data:    concat(oggPageBytes(0, 1, lacing(len(ident)), ident), oggPageBytes(0, 1, lacing(len(comments)), comments[:20])),
+			wantErr: errNoOgg,
+		},
+		{
+			name: "comentarios con largo fuera del paquete",
+			data: concat(
+				oggPageBytes(0, 1, lacing(len(ident)), ident),
+				oggPageBytes(0, 1, lacing(19), concat([]byte("\x03vorbis"), le32(0), le32(1), le32(0xFFFFFFFF)))),
+			wantErr: errVorbisComment,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFixture(t, "pista.ogg", tt.data)
+			got, err := ReadOgg(path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadOgg: %v", err)
+			}
+			tt.want.Path = path
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ReadOgg = %+v, se esperaba %+v", *got, tt.want)
+			}
+		})
+	}
+}
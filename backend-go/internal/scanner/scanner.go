@@ -0,0 +1,205 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Track son los metadatos leídos de un archivo de audio
+type Track struct {
+	Path        string   `json:"path"`
+	Title       string   `json:"title"`
+	Artists     []string `json:"artists"`                // El primero es el principal, el resto invitados
+	AlbumArtist string   `json:"album_artist,omitempty"` // Artista del álbum si difiere del de la pista
+	Album       string   `json:"album"`
+	TrackNumber int      `json:"track_number"`
+	DiscNumber  int      `json:"disc_number"`
+	Year        int      `json:"year"`
+	Genre       string   `json:"genre,omitempty"`
+	Duration    int      `json:"duration"` // En segundos
+}
+
+// FileError es un archivo que no se pudo leer o le faltan datos obligatorios
+type FileError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// Bytes de audio que se leen para encontrar el primer frame MPEG
+const mpegProbeSize = 64 * 1024
+
+// Format es un tipo de archivo de audio soportado
+type Format struct {
+	Ext         string
+	ContentType string
+	Read        func(path string) (*Track, error)
+}
+
+var (
+	FormatMP3  = &Format{Ext: ".mp3", ContentType: "audio/mpeg", Read: ReadMP3}
+	FormatFLAC = &Format{Ext: ".flac", ContentType: "audio/flac", Read: ReadFLAC}
+	FormatOgg  = &Format{Ext: ".ogg", ContentType: "audio/ogg", Read: ReadOgg}
+)
+
+// readers por extensión soportada
+var readers = map[string]*Format{
+	".mp3":  FormatMP3,
+	".flac": FormatFLAC,
+	".ogg":  FormatOgg,
+	".oga":  FormatOgg,
+}
+
+// DetectFormat reconoce el formato por los primeros bytes del archivo (al menos 4)
+func DetectFormat(head []byte) (*Format, bool) {
+	switch {
+	case len(head) < 4:
+		return nil, false
+	case string(head[:4]) == "fLaC":
+		return FormatFLAC, true
+	case string(head[:4]) == "OggS":
+		return FormatOgg, true
+	case string(head[:3]) == "ID3":
+		return FormatMP3, true
+	case head[0] == 0xFF && head[1]&0xE0 == 0xE0: // Sincronización de frame MPEG sin etiqueta
+		return FormatMP3, true
+	}
+	return nil, false
+}
+
+// Scan recorre dir y lee los metadatos de cada archivo soportado, en orden de ruta.
+// Las rutas quedan absolutas porque se guardan como ubicación de cada canción
+func Scan(dir string) ([]Track, []FileError, error) {
+	var tracks []Track
+	var fileErrs []FileError
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fileErrs = append(fileErrs, FileError{Path: path, Error: err.Error()})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		format, ok := readers[strings.ToLower(filepath.Ext(path))]
+		if !ok {
+			return nil
+		}
+
+		t, err := format.Read(path)
+		if err != nil {
+			fileErrs = append(fileErrs, FileError{Path: path, Error: err.Error()})
+			return nil
+		}
+		if err := t.validate(); err != nil {
+			fileErrs = append(fileErrs, FileError{Path: path, Error: err.Error()})
+			return nil
+		}
+		tracks = append(tracks, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].Path < tracks[j].Path })
+	return tracks, fileErrs, nil
+}
+
+// ReadMP3 lee la etiqueta ID3v2 (o ID3v1 si no hay v2) y calcula la duración del audio.
+// Un archivo sin etiquetas no es error: queda solo con la duración
+func ReadMP3(path string) (*Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	t := &Track{Path: path}
+	var audioStart int64
+	h, err := readID3Header(f)
+	if err == nil {
+		body := make([]byte, h.size)
+		if _, err := io.ReadFull(f, body); err != nil {
+			return nil, fmt.Errorf("etiqueta ID3v2 truncada: %w", err)
+		}
+		parseID3v2(h, body, t)
+		audioStart = int64(h.tagSize)
+	}
+
+	// ID3v1 al final: se descuenta del audio y se usa solo si no había ID3v2
+	audioEnd := size
+	if size >= 128 {
+		tag := make([]byte, 128)
+		if _, err := f.ReadAt(tag, size-128); err == nil && string(tag[:3]) == "TAG" {
+			audioEnd -= 128
+			if h == nil {
+				parseID3v1(tag, t)
+			}
+		}
+	}
+	if t.Duration == 0 && audioEnd > audioStart {
+		probe := make([]byte, min(mpegProbeSize, audioEnd-audioStart))
+		n, err := f.ReadAt(probe, audioStart)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		t.Duration = mpegDuration(probe[:n], audioEnd-audioStart)
+	}
+	return t, nil
+}
+
+func (t *Track) validate() error {
+	var missing []string
+	if t.Title == "" {
+		missing = append(missing, "título")
+	}
+	if len(t.Artists) == 0 && t.AlbumArtist == "" {
+		missing = append(missing, "artista")
+	}
+	if t.Album == "" {
+		missing = append(missing, "álbum")
+	}
+	if t.TrackNumber <= 0 {
+		missing = append(missing, "número de pista")
+	}
+	if t.Duration <= 0 {
+		missing = append(missing, "duración")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("faltan datos: %s", strings.Join(missing, ", "))
+	}
+	if len(t.Artists) == 0 {
+		t.Artists = []string{t.AlbumArtist}
+	}
+	if t.DiscNumber <= 0 {
+		t.DiscNumber = 1
+	}
+	return nil
+}
+
+// albumArtist es el artista con el que se agrupa el álbum
+func (t *Track) albumArtist() string {
+	if t.AlbumArtist != "" {
+		return t.AlbumArtist
+	}
+	return t.Artists[0]
+}
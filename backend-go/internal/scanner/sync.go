@@ -0,0 +1,389 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Estados de un álbum en el reporte
+const (
+	StatusCreated   = "created"
+	StatusUpdated   = "updated"
+	StatusUnchanged = "unchanged"
+	StatusFailed    = "failed"
+)
+
+// errSyncRollback fuerza el rollback de la transacción de un álbum en dry run
+var errSyncRollback = errors.New("rollback de sincronización")
+
+type Options struct {
+	DryRun bool // Calcula las diferencias dentro de la transacción y hace rollback al final
+}
+
+// AlbumResult resume los cambios aplicados a un álbum (cada álbum es una transacción)
+type AlbumResult struct {
+	Artist         string `json:"artist"`
+	Album          string `json:"album"`
+	AlbumID        int64  `json:"album_id,omitempty"` // En dry run puede ser un ID que no llega a existir
+	Status         string `json:"status"`             // created | updated | unchanged | failed
+	CreatedArtists int    `json:"created_artists"`
+	CreatedSongs   int    `json:"created_songs"`
+	UpdatedSongs   int    `json:"updated_songs"`
+	UnchangedSongs int    `json:"unchanged_songs"`
+	Error          string `json:"error,omitempty"`
+}
+
+type Report struct {
+	DryRun    bool          `json:"dry_run"`
+	Files     int           `json:"files"` // Archivos leídos con metadatos completos
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Failed    int           `json:"failed"` // Archivos con error de lectura o de álbumes fallidos
+	Albums    []AlbumResult `json:"albums"`
+	Errors    []FileError   `json:"errors"`
+}
+
+// Syncer crea o actualiza artistas, álbumes y canciones a partir de los archivos leídos,
+// siempre a través de los servicios de dominio para respetar sus validaciones
+type Syncer struct {
+	tx      domain.Transactor
+	artists domain.ArtistService
+	albums  domain.AlbumService
+	songs   domain.SongService
+}
+
+func NewSyncer(tx domain.Transactor, artists domain.ArtistService, albums domain.AlbumService, songs domain.SongService) *Syncer {
+	return &Syncer{tx: tx, artists: artists, albums: albums, songs: songs}
+}
+
+// albumGroup son las pistas de un mismo álbum de un mismo artista
+type albumGroup struct {
+	artist string
+	album  string
+	tracks []Track
+}
+
+// Sync aplica solo las diferencias entre los archivos y el catálogo, por lo que volver a
+// escanear la misma biblioteca no cambia nada. Las pistas se identifican por álbum, disco y número
+func (s *Syncer) Sync(ctx context.Context, tracks []Track, fileErrs []FileError, opts Options) *Report {
+	report := &Report{
+		DryRun: opts.DryRun,
+		Files:  len(tracks),
+		Failed: len(fileErrs),
+		Albums: []AlbumResult{},
+		Errors: append([]FileError{}, fileErrs...),
+	}
+
+	groups := make(map[string]*albumGroup)
+	var order []string
+	for _, t := range tracks {
+		key := strings.ToLower(t.albumArtist()) + "\x00" + strings.ToLower(t.Album)
+		g, ok := groups[key]
+		if !ok {
+			g = &albumGroup{artist: t.albumArtist(), album: t.Album}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.tracks = append(g.tracks, t)
+	}
+
+	for _, key := range order {
+		g := groups[key]
+		result, errs := s.syncAlbum(ctx, g, opts)
+		report.Albums = append(report.Albums, result)
+		report.Errors = append(report.Errors, errs...)
+
+		if result.Status == StatusFailed {
+			report.Failed += len(g.tracks)
+			continue
+		}
+		report.Created += result.CreatedSongs
+		report.Updated += result.UpdatedSongs
+		report.Unchanged += result.UnchangedSongs
+	}
+	return report
+}
+
+func (s *Syncer) syncAlbum(ctx context.Context, g *albumGroup, opts Options) (AlbumResult, []FileError) {
+	result := AlbumResult{Artist: g.artist, Album: g.album}
+
+	var fileErrs []FileError
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		fileErrs, err = s.syncAlbumTracks(ctx, g, &result)
+		if err != nil {
+			return err
+		}
+		if len(fileErrs) > 0 {
+			return errSyncRollback
+		}
+		if opts.DryRun {
+			return errSyncRollback
+		}
+		return nil
+	})
+
+	switch {
+	case len(fileErrs) > 0:
+		result.Status = StatusFailed
+		result.Error = "el álbum tiene archivos con datos inválidos"
+	case err != nil && !errors.Is(err, errSyncRollback):
+		var valErrs domain.ValidationError
+		if errors.As(err, &valErrs) {
+			result.Error = fmt.Sprintf("datos inválidos: %v", map[string]string(valErrs))
+		} else {
+			log.Printf("[ERROR INTERNO] sincronizando álbum '%s' de '%s': %v\n", g.album, g.artist, err)
+			result.Error = "error interno al sincronizar el álbum"
+		}
+		result.Status = StatusFailed
+	case result.Status == "":
+		if result.CreatedSongs > 0 || result.UpdatedSongs > 0 || result.CreatedArtists > 0 {
+			result.Status = StatusUpdated
+		} else {
+			result.Status = StatusUnchanged
+		}
+	}
+	return result, fileErrs
+}
+
+// syncAlbumTracks trabaja dentro de la transacción del ctx. Los problemas de un archivo se
+// devuelven como FileError; el error se reserva para fallas de los servicios
+func (s *Syncer) syncAlbumTracks(ctx context.Context, g *albumGroup, result *AlbumResult) ([]FileError, error) {
+	var fileErrs []FileError
+
+	// Pistas repetidas en la biblioteca (mismo disco y número)
+	seen := make(map[[2]int]string)
+	for _, t := range g.tracks {
+		key := [2]int{t.DiscNumber, t.TrackNumber}
+		if prev, dup := seen[key]; dup {
+			fileErrs = append(fileErrs, FileError{Path: t.Path, Error: fmt.Sprintf("la pista %d del disco %d ya está en %s", t.TrackNumber, t.DiscNumber, prev)})
+			continue
+		}
+		seen[key] = t.Path
+	}
+	if len(fileErrs) > 0 {
+		return fileErrs, nil
+	}
+
+	// Los artistas se resuelven una vez por álbum (en dry run los IDs creados no sobreviven al rollback)
+	artistIDs := make(map[string]int64)
+	resolveArtist := func(name, genre string) (int64, error) {
+		key := strings.ToLower(name)
+		if id, ok := artistIDs[key]; ok {
+			return id, nil
+		}
+		artist, err := s.artists.GetByName(ctx, name)
+		if errors.Is(err, domain.ErrArtistNotFound) {
+			if genre == "" {
+				genre = domain.ImportDefaultGenre
+			}
+			artist, err = s.artists.Create(ctx, &domain.ArtistInput{Name: name, Genre: genre, Country: domain.ImportDefaultCountry})
+			result.CreatedArtists++
+		}
+		if err != nil {
+			return 0, err
+		}
+		artistIDs[key] = artist.ID
+		return artist.ID, nil
+	}
+
+	// 1. Artista del álbum
+	genre := ""
+	year := 0
+	for _, t := range g.tracks {
+		if genre == "" {
+			genre = t.Genre
+		}
+		if year == 0 {
+			year = t.Year
+		}
+	}
+	albumArtistID, err := resolveArtist(g.artist, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Álbum
+	album, err := s.albums.GetByTitleAndArtist(ctx, g.album, albumArtistID)
+	if errors.Is(err, domain.ErrAlbumNotFound) {
+		if year == 0 {
+			for _, t := range g.tracks {
+				fileErrs = append(fileErrs, FileError{Path: t.Path, Error: "falta el año para crear el álbum"})
+			}
+			return fileErrs, nil
+		}
+		album, err = s.albums.Create(ctx, &domain.AlbumInput{
+			Title:       g.album,
+			ReleaseDate: releaseDate(year),
+			Type:        albumType(len(g.tracks)),
+			Artists:     []domain.AlbumArtistInput{{ArtistID: albumArtistID, IsPrimary: true}},
+		})
+		if err == nil {
+			result.Status = StatusCreated
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.AlbumID = album.ID
+
+	// Solo se corrige la fecha si cambió el año, asi no se pierde un día y mes cargados a mano
+	if year != 0 && album.ReleaseDate.Year() != year {
+		patch, err := buildPatch(map[string]any{"release_date": releaseDate(year)})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.albums.Patch(ctx, album.ID, patch, nil); err != nil {
+			return nil, err
+		}
+		if result.Status == "" {
+			result.Status = StatusUpdated
+		}
+	}
+
+	// 3. Pistas
+	existing := make(map[[2]int]domain.Track)
+	for _, t := range album.Tracks {
+		existing[[2]int{t.DiscNumber, t.TrackNumber}] = t
+	}
+	for _, t := range g.tracks {
+		artists, err := trackArtists(t, func(name string) (int64, error) { return resolveArtist(name, t.Genre) })
+		if err != nil {
+			return nil, err
+		}
+
+		current, ok := existing[[2]int{t.DiscNumber, t.TrackNumber}]
+		if !ok {
+			song, err := s.songs.Create(ctx, &domain.SongInput{Title: t.Title, Duration: t.Duration, Artists: artists})
+			if err != nil {
+				return nil, err
+			}
+			if err := s.albums.AddTrack(ctx, album.ID, &domain.TrackInput{SongID: song.ID, DiscNumber: t.DiscNumber, TrackNumber: t.TrackNumber}); err != nil {
+				return nil, err
+			}
+			if err := s.songs.SetFilePath(ctx, song.ID, &t.Path); err != nil {
+				return nil, err
+			}
+			result.CreatedSongs++
+			continue
+		}
+
+		changes := make(map[string]any)
+		if current.Title != t.Title {
+			changes["title"] = t.Title
+		}
+		if current.Duration != t.Duration {
+			changes["duration"] = t.Duration
+		}
+		// Los productores no vienen en las etiquetas, se conservan
+		for _, a := range current.Artists {
+			if a.Role == "producer" {
+				artists = append(artists, domain.ArtistSongInput{ArtistID: a.ID, Role: a.Role})
+			}
+		}
+		if !sameArtists(current.Artists, artists) {
+			changes["artists"] = artists
+		}
+		// La ruta no es parte del patch, se guarda aparte cuando el archivo se movió
+		moved := current.FilePath == nil || *current.FilePath != t.Path
+		if moved {
+			if err := s.songs.SetFilePath(ctx, current.SongID, &t.Path); err != nil {
+				return nil, err
+			}
+		}
+		if len(changes) == 0 {
+			if moved {
+				result.UpdatedSongs++
+			} else {
+				result.UnchangedSongs++
+			}
+			continue
+		}
+
+		patch, err := buildPatch(changes)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.songs.Patch(ctx, current.SongID, patch, nil); err != nil {
+			return nil, err
+		}
+		result.UpdatedSongs++
+	}
+	return nil, nil
+}
+
+// trackArtists arma los artistas de la canción: el primero es main y el resto ft
+func trackArtists(t Track, resolve func(name string) (int64, error)) ([]domain.ArtistSongInput, error) {
+	artists := make([]domain.ArtistSongInput, 0, len(t.Artists))
+	added := make(map[int64]bool)
+	for i, name := range t.Artists {
+		id, err := resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		if added[id] {
+			continue
+		}
+		added[id] = true
+		role := "ft"
+		if i == 0 {
+			role = "main"
+		}
+		artists = append(artists, domain.ArtistSongInput{ArtistID: id, Role: role})
+	}
+	return artists, nil
+}
+
+func sameArtists(current []domain.ArtistWithRole, desired []domain.ArtistSongInput) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	a := make([]string, 0, len(current))
+	for _, c := range current {
+		a = append(a, fmt.Sprintf("%d:%s", c.ID, c.Role))
+	}
+	b := make([]string, 0, len(desired))
+	for _, d := range desired {
+		b = append(b, fmt.Sprintf("%d:%s", d.ArtistID, d.Role))
+	}
+	sort.Strings(a)
+	sort.Strings(b)
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+func buildPatch(changes map[string]any) (domain.MergePatch, error) {
+	patch := make(domain.MergePatch, len(changes))
+	for field, value := range changes {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		patch[field] = raw
+	}
+	return patch, nil
+}
+
+// Las etiquetas solo traen el año, se usa el 1 de enero
+func releaseDate(year int) string {
+	return fmt.Sprintf("%04d-01-01", year)
+}
+
+// albumType infiere el tipo por cantidad de pistas al crear el álbum
+func albumType(tracks int) string {
+	switch {
+	case tracks == 1:
+		return "Single"
+	case tracks <= 6:
+		return "EP"
+	default:
+		return "LP"
+	}
+}
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// Comentarios Vorbis: los usan FLAC (bloque VORBIS_COMMENT) y Ogg Vorbis (paquete de comentarios).
+// Cada comentario es "CLAVE=valor", las claves no distinguen mayúsculas y pueden repetirse
+
+var errVorbisComment = errors.New("bloque de comentarios Vorbis inválido")
+
+// parseVorbisComment lee el bloque (little endian) y llena los campos de t
+func parseVorbisComment(b []byte, t *Track) error {
+	next := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(b[:4])
+		if uint64(n) > uint64(len(b)-4) {
+			return nil, false
+		}
+		v := b[4 : 4+n]
+		b = b[4+n:]
+		return v, true
+	}
+
+	if _, ok := next(); !ok { // Vendor
+		return errVorbisComment
+	}
+	if len(b) < 4 {
+		return errVorbisComment
+	}
+	count := binary.LittleEndian.Uint32(b[:4])
+	b = b[4:]
+
+	comments := make(map[string][]string)
+	for i := uint32(0); i < count; i++ {
+		c, ok := next()
+		if !ok {
+			return errVorbisComment
+		}
+		key, value, ok := strings.Cut(string(c), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+		if value = strings.TrimSpace(value); value != "" {
+			comments[key] = append(comments[key], value)
+		}
+	}
+	applyVorbisComments(comments, t)
+	return nil
+}
+
+func applyVorbisComments(c map[string][]string, t *Track) {
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := c[k]; len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+
+	t.Title = first("TITLE")
+	// Cada ARTIST repetido es un artista distinto; el primero queda como principal
+	t.Artists = splitArtists(c["ARTIST"])
+	t.AlbumArtist = first("ALBUMARTIST", "ALBUM ARTIST")
+	t.Album = first("ALBUM")
+	t.TrackNumber = parseNumberOf(first("TRACKNUMBER"))
+	t.DiscNumber = parseNumberOf(first("DISCNUMBER"))
+	t.Year = parseYear(first("DATE", "YEAR", "ORIGINALDATE"))
+	t.Genre = first("GENRE")
+}
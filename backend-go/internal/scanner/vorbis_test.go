@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// vorbisComment arma un bloque de comentarios: largo y texto del vendor, cantidad y cada "CLAVE=valor"
+func vorbisComment(vendor string, comments ...string) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, uint32(len(vendor)))
+	b.WriteString(vendor)
+	binary.Write(&b, binary.LittleEndian, uint32(len(comments)))
+	for _, c := range comments {
+		binary.Write(&b, binary.LittleEndian, uint32(len(c)))
+		b.WriteString(c)
+	}
+	return b.Bytes()
+}
+
+func le32(n uint32) []byte { return binary.LittleEndian.AppendUint32(nil, n) }
+
+func TestParseVorbisComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   []byte
+		want    Track
+		wantErr bool
+	}{
+		{
+			name: "campos básicos",
+			block: vorbisComment("reference libFLAC 1.4.3",
+				"TITLE=Té para tres", "ARTIST=Soda Stereo", "ALBUM=Canción Animal", "TRACKNUMBER=3/10",
+				"DISCNUMBER=1", "DATE=1990-08-07", "GENRE=Rock", "ALBUMARTIST=Soda Stereo"),
+			want: Track{
+				Title: "Té para tres", Artists: []string{"Soda Stereo"}, AlbumArtist: "Soda Stereo",
+				Album: "Canción Animal", TrackNumber: 3, DiscNumber: 1, Year: 1990, Genre: "Rock",
+			},
+		},
+		{
+			name:  "ARTIST repetido y claves sin distinguir mayúsculas",
+			block: vorbisComment("", "artist=Los Tres", "Artist=Invitada", "title=Déjate caer"),
+			want:  Track{Title: "Déjate caer", Artists: []string{"Los Tres", "Invitada"}},
+		},
+		{
+			name:  "valores vacíos y comentarios sin '=' se ignoran",
+			block: vorbisComment("", "TITLE=  ", "SIN SEPARADOR", "ALBUM=Álbum", "YEAR=1984", "DATE="),
+			want:  Track{Album: "Álbum", Year: 1984},
+		},
+		{
+			name:    "vendor truncado",
+			block:   concat(le32(20), []byte("corto")),
+			wantErr: true,
+		},
+		{
+			name:    "sin cantidad de comentarios",
+			block:   concat(le32(0), []byte{1, 0}),
+			wantErr: true,
+		},
+		{
+			name:    "comentario más largo que el bloque",
+			block:   concat(le32(0), le32(1), le32(50), []byte("TITLE=corto")),
+			wantErr: true,
+		},
+		{
+			name:    "largo de comentario desbordado",
+			block:   concat(le32(0), le32(1), le32(0xFFFFFFFF), []byte("TITLE=x")),
+			wantErr: true,
+		},
+		{
+			name:    "cantidad mayor a los comentarios presentes",
+			block:   concat(vorbisComment("", "TITLE=Uno")[:4], le32(3), le32(9), []byte("TITLE=Uno")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Track
+			err := parseVorbisComment(tt.block, &got)
+			if tt.wantErr {
+				if !errors.Is(err, errVorbisComment) {
+					t.Fatalf("err = %v, se esperaba %v", err, errVorbisComment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVorbisComment: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVorbisComment = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Cada ARTIST repetido llega a la canción como invitado (ft) del primero
+func TestVorbisArtistsRoles(t *testing.T) {
+	var track Track
+	if err := parseVorbisComment(vorbisComment("", "ARTIST=Principal", "ARTIST=Invitado", "ARTIST=Otra"), &track); err != nil {
+		t.Fatalf("parseVorbisComment: %v", err)
+	}
+	ids := map[string]int64{"Principal": 1, "Invitado": 2, "Otra": 3}
+	got, err := trackArtists(track, func(name string) (int64, error) { return ids[name], nil })
+	if err != nil {
+		t.Fatalf("trackArtists: %v", err)
+	}
+	want := []domain.ArtistSongInput{{ArtistID: 1, Role: "main"}, {ArtistID: 2, Role: "ft"}, {ArtistID: 3, Role: "ft"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trackArtists = %+v, se esperaba %+v", got, want)
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
 )
 
 type albumService struct {
@@ -30,8 +32,25 @@ func (s *albumService) GetByID(ctx context.Context, albumID int64) (*domain.Albu
 	return s.repo.GetByID(ctx, albumID)
 }
 
+func (s *albumService) GetByTitleAndArtist(ctx context.Context, title string, artistID int64) (*domain.Album, error) {
+	if artistID <= 0 {
+		return nil, domain.ErrArtistIDInvalid
+	}
+	title = validation.SanitizeString(title)
+	if title == "" {
+		return nil, domain.ValidationError{"title": "el título del álbum es obligatorio"}
+	}
+	return s.repo.GetByTitleAndArtist(ctx, title, artistID)
+}
+
 func (s *albumService) GetAllPaginated(ctx context.Context, filter domain.AlbumFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Album], error) {
-	filter.Sanitize()
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	// Defaults de page/limit y decodificación del cursor
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 	return s.repo.GetAllPaginated(ctx, filter, params)
 }
 
@@ -43,7 +62,7 @@ func (s *albumService) GetAlbumsByArtistID(ctx context.Context, artistID int64)
 }
 
 // UPDATE
-func (s *albumService) Update(ctx context.Context, id int64, input *domain.AlbumInput) (*domain.Album, error) {
+func (s *albumService) Update(ctx context.Context, id int64, input *domain.AlbumInput, version *time.Time) (*domain.Album, error) {
 	// Validaciones defensivas
 	if id <= 0 {
 		return nil, domain.ErrAlbumIDInvalid
@@ -52,7 +71,38 @@ func (s *albumService) Update(ctx context.Context, id int64, input *domain.Album
 		return nil, err
 	}
 
-	return s.repo.Update(ctx, id, input)
+	return s.repo.Update(ctx, id, input, version)
+}
+
+// PATCH (JSON Merge Patch). Solo se validan los campos enviados. "artists", "discs" y "tracks"
+// reemplazan la lista completa; null en discs o tracks los deja vacíos
+func (s *albumService) Patch(ctx context.Context, id int64, patch domain.MergePatch, version *time.Time) (*domain.Album, error) {
+	if id <= 0 {
+		return nil, domain.ErrAlbumIDInvalid
+	}
+
+	return applyPatch(patch, version,
+		func() (domain.AlbumInput, time.Time, error) {
+			current, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				return domain.AlbumInput{}, time.Time{}, err
+			}
+			return current.ToInput(), current.UpdatedAt, nil
+		},
+		func(input *domain.AlbumInput) error {
+			// En el Update nil significa "sin cambios", un null explícito debe vaciar la lista
+			if patch.Has("discs") && input.Discs == nil {
+				input.Discs = []domain.DiscInput{}
+			}
+			if patch.Has("tracks") && input.Tracks == nil {
+				input.Tracks = []domain.TrackInput{}
+			}
+			return input.Validate()
+		},
+		func(input *domain.AlbumInput, expected *time.Time) (*domain.Album, error) {
+			return s.repo.Update(ctx, id, input, expected)
+		},
+	)
 }
 
 func (s *albumService) AddTrack(ctx context.Context, albumID int64, input *domain.TrackInput) error {
@@ -79,10 +129,43 @@ func (s *albumService) RemoveTrack(ctx context.Context, albumID int64, songID in
 	return s.repo.RemoveTrack(ctx, albumID, songID)
 }
 
-// DELETE
-func (s *albumService) Delete(ctx context.Context, albumID int64) error {
+func (s *albumService) InsertTrack(ctx context.Context, albumID int64, input *domain.TrackInput) error {
+	if albumID <= 0 {
+		return domain.ErrAlbumIDInvalid
+	}
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.InsertTrack(ctx, albumID, input)
+}
+
+func (s *albumService) RemoveTrackAndCompact(ctx context.Context, albumID int64, songID int64) error {
+	if albumID <= 0 {
+		return domain.ErrAlbumIDInvalid
+	}
+	if songID <= 0 {
+		return domain.ErrSongIDInvalid
+	}
+	return s.repo.RemoveTrackAndCompact(ctx, albumID, songID)
+}
+
+func (s *albumService) ReorderTracks(ctx context.Context, albumID int64, input *domain.TrackOrderInput) error {
 	if albumID <= 0 {
 		return domain.ErrAlbumIDInvalid
 	}
-	return s.repo.Delete(ctx, albumID)
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.ReorderTracks(ctx, albumID, input.DiscNumber, input.SongIDs)
+}
+
+// DELETE
+func (s *albumService) Delete(ctx context.Context, albumID int64, opts domain.DeleteOptions, version *time.Time) (*domain.DeleteReport, error) {
+	if albumID <= 0 {
+		return nil, domain.ErrAlbumIDInvalid
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Delete(ctx, albumID, opts, version)
 }
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
 	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
@@ -40,6 +42,14 @@ func (s *artistService) GetByID(ctx context.Context, id int64) (*domain.Artist,
 	}
 
 	artist, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		// El ID puede pertenecer a un duplicado ya fusionado, en ese caso se retorna el sobreviviente
+		newID, redirectErr := s.repo.ResolveRedirect(ctx, id)
+		if redirectErr != nil {
+			return nil, err
+		}
+		return s.repo.GetByID(ctx, newID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -47,17 +57,30 @@ func (s *artistService) GetByID(ctx context.Context, id int64) (*domain.Artist,
 	return artist, nil
 }
 
+// Búsqueda exacta por nombre (sin distinguir mayúsculas), usada por importación y escáner
+func (s *artistService) GetByName(ctx context.Context, name string) (*domain.Artist, error) {
+	name = validation.SanitizeString(name)
+	if name == "" {
+		return nil, domain.ValidationError{"name": "el nombre es obligatorio"}
+	}
+	return s.repo.GetByName(ctx, name)
+}
+
 func (s *artistService) GetAll(ctx context.Context) ([]domain.Artist, error) {
 	return s.repo.GetAll(ctx)
 }
 
 func (s *artistService) GetAllPaginated(ctx context.Context, filter domain.ArtistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
 	filter.Sanitize()
+	// Defaults de page/limit y decodificación del cursor
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 	return s.repo.GetAllPaginated(ctx, filter, params)
 }
 
 // 3. Update
-func (s *artistService) Update(ctx context.Context, id int64, input *domain.ArtistInput) (*domain.Artist, error) {
+func (s *artistService) Update(ctx context.Context, id int64, input *domain.ArtistInput, version *time.Time) (*domain.Artist, error) {
 	if err := input.Validate(); err != nil {
 		return nil, err
 	}
@@ -66,7 +89,7 @@ func (s *artistService) Update(ctx context.Context, id int64, input *domain.Arti
 		return nil, domain.ErrArtistIDInvalid
 	}
 
-	artist, err := s.repo.Update(ctx, id, input)
+	artist, err := s.repo.Update(ctx, id, input, version)
 	if err != nil {
 		return nil, err
 	}
@@ -74,15 +97,50 @@ func (s *artistService) Update(ctx context.Context, id int64, input *domain.Arti
 	return artist, nil
 }
 
+// PATCH (JSON Merge Patch). Solo se validan los campos enviados
+func (s *artistService) Patch(ctx context.Context, id int64, patch domain.MergePatch, version *time.Time) (*domain.Artist, error) {
+	if id <= 0 {
+		return nil, domain.ErrArtistIDInvalid
+	}
+
+	return applyPatch(patch, version,
+		func() (domain.ArtistInput, time.Time, error) {
+			current, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				return domain.ArtistInput{}, time.Time{}, err
+			}
+			return current.ToInput(), current.UpdatedAt, nil
+		},
+		func(input *domain.ArtistInput) error { return input.Validate() },
+		func(input *domain.ArtistInput, expected *time.Time) (*domain.Artist, error) {
+			return s.repo.Update(ctx, id, input, expected)
+		},
+	)
+}
+
 // 4. Delete
-func (s *artistService) Delete(ctx context.Context, id int64) error {
+func (s *artistService) Delete(ctx context.Context, id int64, opts domain.DeleteOptions, version *time.Time) (*domain.DeleteReport, error) {
 	if id <= 0 {
-		return domain.ErrArtistIDInvalid
+		return nil, domain.ErrArtistIDInvalid
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Delete(ctx, id, opts, version)
+}
+
+// 5. Fusionar duplicados. input.SourceID se absorbe dentro de targetID
+func (s *artistService) Merge(ctx context.Context, targetID int64, input *domain.ArtistMergeInput) (*domain.ArtistMergeReport, error) {
+	if targetID <= 0 {
+		return nil, domain.ErrArtistIDInvalid
+	}
+	if err := input.Validate(targetID); err != nil {
+		return nil, err
 	}
-	return s.repo.Delete(ctx, id)
+	return s.repo.Merge(ctx, targetID, input.SourceID)
 }
 
-// 5. Buscar segun nombre
+// 6. Buscar segun nombre
 func (s *artistService) SearchArtists(ctx context.Context, searchTerm string) ([]domain.ArtistSeachResult, error) {
 	searchTerm = validation.SanitizeString(searchTerm)
 	return s.repo.SearchArtists(ctx, searchTerm)
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+	"github.com/IsaacEspinoza91/Song-Manager/internal/scanner"
+)
+
+type audioService struct {
+	store    domain.BlobStore
+	songRepo domain.SongRepository
+}
+
+func NewAudioService(store domain.BlobStore, songRepo domain.SongRepository) domain.AudioService {
+	return &audioService{store: store, songRepo: songRepo}
+}
+
+// Attach guarda el archivo y lo asocia a la canción. El archivo pasa primero por un temporal en
+// disco: hay que leerlo completo para calcular su duración y hash antes de decidir si se acepta
+func (s *audioService) Attach(ctx context.Context, songID int64, r io.Reader, opts domain.AudioAttachOptions) (*domain.AudioAttachResult, error) {
+	if songID <= 0 {
+		return nil, domain.ErrSongIDInvalid
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	song, err := s.songRepo.GetByID(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Copia al temporal con límite de tamaño
+	tmp, err := os.CreateTemp("", "song-audio-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creando archivo temporal de audio: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, domain.MaxAudioSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > domain.MaxAudioSize {
+		return nil, domain.ErrAudioTooLarge
+	}
+
+	// 2. Formato por contenido y duración desde los metadatos del archivo
+	head := make([]byte, 4)
+	if _, err := tmp.ReadAt(head, 0); err != nil {
+		return nil, domain.ErrAudioUnsupported
+	}
+	format, ok := scanner.DetectFormat(head)
+	if !ok {
+		return nil, domain.ErrAudioUnsupported
+	}
+	track, err := format.Read(tmp.Name())
+	if err != nil {
+		return nil, domain.ErrAudioUnsupported
+	}
+
+	result := &domain.AudioAttachResult{
+		SongID:           song.ID,
+		ContentType:      format.ContentType,
+		Size:             size,
+		FileDuration:     track.Duration,
+		PreviousDuration: song.Duration,
+		Duration:         song.Duration,
+	}
+	newDuration := 0
+	if track.Duration > 0 && abs(track.Duration-song.Duration) > domain.AudioDurationTolerance {
+		if opts.DurationMode == domain.AudioDurationCheck {
+			return nil, domain.ValidationError{"duration": fmt.Sprintf("el archivo dura %d segundos y la canción tiene %d", track.Duration, song.Duration)}
+		}
+		newDuration = track.Duration
+		result.Duration = track.Duration
+		result.DurationUpdated = true
+	}
+
+	// 3. Guardar en el blob store y luego en la canción
+	previous, err := s.songRepo.GetAudio(ctx, song.ID)
+	if err != nil && !errors.Is(err, domain.ErrSongAudioNotFound) {
+		return nil, err
+	}
+
+	audio := &domain.SongAudio{
+		Key:         fmt.Sprintf("songs/%d/audio/%s%s", song.ID, hex.EncodeToString(hash.Sum(nil)[:8]), format.Ext),
+		ContentType: format.ContentType,
+		Size:        size,
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, audio.Key, tmp); err != nil {
+		return nil, err
+	}
+	if err := s.songRepo.SetAudio(ctx, song.ID, audio, newDuration); err != nil {
+		if previous == nil || previous.Key != audio.Key {
+			s.deleteBlob(ctx, audio.Key)
+		}
+		return nil, err
+	}
+
+	if previous != nil && previous.Key != audio.Key {
+		s.deleteBlob(ctx, previous.Key)
+	}
+	return result, nil
+}
+
+func (s *audioService) Open(ctx context.Context, songID int64) (io.ReadSeekCloser, *domain.BlobInfo, *domain.SongAudio, error) {
+	if songID <= 0 {
+		return nil, nil, nil, domain.ErrSongIDInvalid
+	}
+	audio, err := s.songRepo.GetAudio(ctx, songID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	f, info, err := s.store.Open(ctx, audio.Key)
+	if err != nil {
+		if errors.Is(err, domain.ErrBlobNotFound) {
+			log.Printf("[ERROR] el audio de la canción %d apunta a un archivo inexistente: %s\n", songID, audio.Key)
+			return nil, nil, nil, domain.ErrSongAudioNotFound
+		}
+		return nil, nil, nil, err
+	}
+	return f, info, audio, nil
+}
+
+// Detach quita el audio de la canción; la duración guardada se mantiene
+func (s *audioService) Detach(ctx context.Context, songID int64) error {
+	if songID <= 0 {
+		return domain.ErrSongIDInvalid
+	}
+	audio, err := s.songRepo.GetAudio(ctx, songID)
+	if err != nil {
+		return err
+	}
+	if err := s.songRepo.SetAudio(ctx, songID, nil, 0); err != nil {
+		return err
+	}
+	s.deleteBlob(ctx, audio.Key)
+	return nil
+}
+
+// deleteBlob es de mejor esfuerzo: un archivo huérfano no debe hacer fallar la operación
+func (s *audioService) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("[ERROR] eliminando audio %s: %v\n", key, err)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
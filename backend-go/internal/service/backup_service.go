@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type backupService struct {
+	tx   domain.Transactor
+	repo domain.BackupRepository
+}
+
+func NewBackupService(tx domain.Transactor, repo domain.BackupRepository) domain.BackupService {
+	return &backupService{tx: tx, repo: repo}
+}
+
+// Export escribe la cabecera y luego una línea por fila, a medida que llegan del repositorio
+func (s *backupService) Export(ctx context.Context, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw) // Encode agrega el salto de línea
+
+	write := func(recordType string, record any) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("error serializando %s: %w", recordType, err)
+		}
+		return enc.Encode(domain.BackupLine{Type: recordType, Data: data})
+	}
+
+	header := domain.BackupHeader{SchemaVersion: domain.BackupSchemaVersion, ExportedAt: time.Now().UTC()}
+	if err := write(domain.BackupTypeHeader, header); err != nil {
+		return err
+	}
+	if err := s.repo.Export(ctx, write); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// backupIDs traduce los IDs del archivo a los IDs nuevos de la base restaurada
+type backupIDs struct {
+	artists   map[int64]int64
+	songs     map[int64]int64
+	albums    map[int64]int64
+	playlists map[int64]int64
+}
+
+// errSkipRecord marca un registro válido que no tiene equivalente en la base restaurada
+var errSkipRecord = errors.New("registro omitido")
+
+// Restore carga el respaldo en una base sin catálogo, en una sola transacción: si alguna línea
+// falla no queda nada a medias. Las relaciones deben referenciar registros que aparecieron antes
+func (s *backupService) Restore(ctx context.Context, r io.Reader) (*domain.RestoreReport, error) {
+	reader := bufio.NewReader(r)
+	report := &domain.RestoreReport{Records: make(map[string]int), Skipped: make(map[string]int)}
+
+	// 1. Cabecera
+	lineNum, line, err := readBackupLine(reader, 0)
+	if errors.Is(err, io.EOF) {
+		return nil, domain.ValidationError{"file": "el archivo está vacío"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var header domain.BackupHeader
+	if line.Type != domain.BackupTypeHeader || json.Unmarshal(line.Data, &header) != nil {
+		return nil, domain.ValidationError{"header": "la primera línea debe ser la cabecera del respaldo"}
+	}
+	if header.SchemaVersion < domain.BackupMinSchemaVersion || header.SchemaVersion > domain.BackupSchemaVersion {
+		return nil, domain.ValidationError{"schema_version": fmt.Sprintf("versión de esquema %d no soportada, se aceptan de %d a %d", header.SchemaVersion, domain.BackupMinSchemaVersion, domain.BackupSchemaVersion)}
+	}
+	report.SchemaVersion = header.SchemaVersion
+
+	// 2. Registros
+	ids := backupIDs{
+		artists:   make(map[int64]int64),
+		songs:     make(map[int64]int64),
+		albums:    make(map[int64]int64),
+		playlists: make(map[int64]int64),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		empty, err := s.repo.IsCatalogEmpty(ctx)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return domain.ErrBackupTargetNotEmpty
+		}
+
+		for {
+			lineNum, line, err = readBackupLine(reader, lineNum)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			err := s.restoreLine(ctx, line, ids)
+			if errors.Is(err, errSkipRecord) {
+				report.Skipped[line.Type]++
+				continue
+			}
+			if err != nil {
+				var valErrs domain.ValidationError
+				if errors.As(err, &valErrs) {
+					valErrs["line"] = strconv.Itoa(lineNum) // Para ubicar el registro en el archivo
+					return valErrs
+				}
+				return fmt.Errorf("línea %d: %w", lineNum, err)
+			}
+			report.Records[line.Type]++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *backupService) restoreLine(ctx context.Context, line *domain.BackupLine, ids backupIDs) error {
+	decode := func(target any) error {
+		if err := json.Unmarshal(line.Data, target); err != nil {
+			return domain.ValidationError{"data": "registro JSON inválido"}
+		}
+		return nil
+	}
+	// mapID traduce una referencia a un registro que ya debió restaurarse
+	mapID := func(ref map[int64]int64, old int64, name string) (int64, error) {
+		id, ok := ref[old]
+		if !ok {
+			return 0, domain.ValidationError{name: fmt.Sprintf("referencia a %s %d que no aparece antes en el archivo", name, old)}
+		}
+		return id, nil
+	}
+
+	var err error
+	switch line.Type {
+	case domain.BackupTypeArtist:
+		var a domain.BackupArtist
+		if err = decode(&a); err != nil {
+			return err
+		}
+		ids.artists[a.ID], err = s.repo.InsertArtist(ctx, &a)
+	case domain.BackupTypeSong:
+		var song domain.BackupSong
+		if err = decode(&song); err != nil {
+			return err
+		}
+		ids.songs[song.ID], err = s.repo.InsertSong(ctx, &song)
+	case domain.BackupTypeAlbum:
+		var a domain.BackupAlbum
+		if err = decode(&a); err != nil {
+			return err
+		}
+		if _, perr := time.Parse(time.DateOnly, a.ReleaseDate); perr != nil {
+			return domain.ValidationError{"release_date": "el formato de la fecha debe ser YYYY-MM-DD"}
+		}
+		ids.albums[a.ID], err = s.repo.InsertAlbum(ctx, &a)
+	case domain.BackupTypeAlbumDisc:
+		var d domain.BackupAlbumDisc
+		if err = decode(&d); err != nil {
+			return err
+		}
+		if d.AlbumID, err = mapID(ids.albums, d.AlbumID, "album"); err != nil {
+			return err
+		}
+		err = s.repo.InsertAlbumDisc(ctx, &d)
+	case domain.BackupTypeSongArtist:
+		var sa domain.BackupSongArtist
+		if err = decode(&sa); err != nil {
+			return err
+		}
+		if sa.SongID, err = mapID(ids.songs, sa.SongID, "song"); err != nil {
+			return err
+		}
+		if sa.ArtistID, err = mapID(ids.artists, sa.ArtistID, "artist"); err != nil {
+			return err
+		}
+		err = s.repo.InsertSongArtist(ctx, &sa)
+	case domain.BackupTypeAlbumArtist:
+		var aa domain.BackupAlbumArtist
+		if err = decode(&aa); err != nil {
+			return err
+		}
+		if aa.AlbumID, err = mapID(ids.albums, aa.AlbumID, "album"); err != nil {
+			return err
+		}
+		if aa.ArtistID, err = mapID(ids.artists, aa.ArtistID, "artist"); err != nil {
+			return err
+		}
+		err = s.repo.InsertAlbumArtist(ctx, &aa)
+	case domain.BackupTypeTrack:
+		var t domain.BackupTrack
+		if err = decode(&t); err != nil {
+			return err
+		}
+		if t.AlbumID, err = mapID(ids.albums, t.AlbumID, "album"); err != nil {
+			return err
+		}
+		if t.SongID, err = mapID(ids.songs, t.SongID, "song"); err != nil {
+			return err
+		}
+		err = s.repo.InsertTrack(ctx, &t)
+	case domain.BackupTypePlaylist:
+		var p domain.BackupPlaylist
+		if err = decode(&p); err != nil {
+			return err
+		}
+		ids.playlists[p.ID], err = s.repo.InsertPlaylist(ctx, &p)
+	case domain.BackupTypePlaylistSong:
+		var ps domain.BackupPlaylistSong
+		if err = decode(&ps); err != nil {
+			return err
+		}
+		if ps.PlaylistID, err = mapID(ids.playlists, ps.PlaylistID, "playlist"); err != nil {
+			return err
+		}
+		if ps.SongID, err = mapID(ids.songs, ps.SongID, "song"); err != nil {
+			return err
+		}
+		err = s.repo.InsertPlaylistSong(ctx, &ps)
+	case domain.BackupTypeArtistRedirect:
+		var ar domain.BackupArtistRedirect
+		if err = decode(&ar); err != nil {
+			return err
+		}
+		if ar.NewID, err = mapID(ids.artists, ar.NewID, "artist"); err != nil {
+			return err
+		}
+		// Si el artista absorbido ya se había purgado, su ID de origen no identifica a nadie en la
+		// base nueva y conservarlo podría chocar con el ID que reciba otro artista restaurado
+		oldID, ok := ids.artists[ar.OldID]
+		if !ok {
+			return errSkipRecord
+		}
+		ar.OldID = oldID
+		err = s.repo.InsertArtistRedirect(ctx, &ar)
+	case domain.BackupTypeHeader:
+		return domain.ValidationError{"type": "la cabecera solo puede ir en la primera línea"}
+	default:
+		return domain.ValidationError{"type": fmt.Sprintf("tipo de registro desconocido '%s'", line.Type)}
+	}
+	return err
+}
+
+// readBackupLine lee la siguiente línea no vacía sin límite de largo (a diferencia de bufio.Scanner)
+func readBackupLine(r *bufio.Reader, lineNum int) (int, *domain.BackupLine, error) {
+	for {
+		raw, err := r.ReadBytes('\n')
+		if len(raw) == 0 && err != nil {
+			return lineNum, nil, err
+		}
+		lineNum++
+		if len(bytes.TrimSpace(raw)) == 0 {
+			if err != nil {
+				return lineNum, nil, err
+			}
+			continue
+		}
+
+		var line domain.BackupLine
+		if jsonErr := json.Unmarshal(raw, &line); jsonErr != nil || line.Type == "" {
+			return lineNum, nil, domain.ValidationError{"line": fmt.Sprintf("línea %d: JSON inválido o sin tipo", lineNum)}
+		}
+		return lineNum, &line, nil
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// fakeBackupRepo asigna IDs nuevos desde 100 y guarda las relaciones tal como llegan
+type fakeBackupRepo struct {
+	nextID        int64
+	playlistSongs []domain.BackupPlaylistSong
+	redirects     []domain.BackupArtistRedirect
+}
+
+func (f *fakeBackupRepo) newID() (int64, error) {
+	f.nextID++
+	return 100 + f.nextID, nil
+}
+
+func (f *fakeBackupRepo) Export(context.Context, func(string, any) error) error { return nil }
+func (f *fakeBackupRepo) IsCatalogEmpty(context.Context) (bool, error)          { return true, nil }
+func (f *fakeBackupRepo) InsertArtist(context.Context, *domain.BackupArtist) (int64, error) {
+	return f.newID()
+}
+func (f *fakeBackupRepo) InsertSong(context.Context, *domain.BackupSong) (int64, error) {
+	return f.newID()
+}
+func (f *fakeBackupRepo) InsertAlbum(context.Context, *domain.BackupAlbum) (int64, error) {
+	return f.newID()
+}
+func (f *fakeBackupRepo) InsertAlbumDisc(context.Context, *domain.BackupAlbumDisc) error { return nil }
+func (f *fakeBackupRepo) InsertSongArtist(context.Context, *domain.BackupSongArtist) error {
+	return nil
+}
+func (f *fakeBackupRepo) InsertAlbumArtist(context.Context, *domain.BackupAlbumArtist) error {
+	return nil
+}
+func (f *fakeBackupRepo) InsertTrack(context.Context, *domain.BackupTrack) error { return nil }
+func (f *fakeBackupRepo) InsertPlaylist(context.Context, *domain.BackupPlaylist) (int64, error) {
+	return f.newID()
+}
+func (f *fakeBackupRepo) InsertPlaylistSong(_ context.Context, ps *domain.BackupPlaylistSong) error {
+	f.playlistSongs = append(f.playlistSongs, *ps)
+	return nil
+}
+func (f *fakeBackupRepo) InsertArtistRedirect(_ context.Context, ar *domain.BackupArtistRedirect) error {
+	f.redirects = append(f.redirects, *ar)
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestRestoreRemapsPlaylistsAndRedirects(t *testing.T) {
+	backup := strings.Join([]string{
+		`{"type":"header","data":{"schema_version":3}}`,
+		`{"type":"artist","data":{"id":10,"name":"Sobreviviente"}}`,                                 // -> 101
+		`{"type":"artist","data":{"id":11,"name":"Absorbido","deleted_at":"2026-01-01T00:00:00Z"}}`, // -> 102
+		`{"type":"song","data":{"id":20,"title":"Canción","duration":180}}`,                         // -> 103
+		`{"type":"playlist","data":{"id":30,"name":"Favoritas"}}`,                                   // -> 104
+		`{"type":"playlist_song","data":{"playlist_id":30,"song_id":20,"position":1}}`,
+		`{"type":"artist_redirect","data":{"old_id":11,"new_id":10}}`,
+		`{"type":"artist_redirect","data":{"old_id":99,"new_id":10}}`, // Absorbido ya purgado
+	}, "\n")
+
+	repo := &fakeBackupRepo{}
+	report, err := NewBackupService(fakeTransactor{}, repo).Restore(context.Background(), strings.NewReader(backup))
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if len(repo.playlistSongs) != 1 || repo.playlistSongs[0].PlaylistID != 104 || repo.playlistSongs[0].SongID != 103 {
+		t.Errorf("entradas de playlist = %+v, se esperaba playlist 104 con canción 103", repo.playlistSongs)
+	}
+	if len(repo.redirects) != 1 || repo.redirects[0].OldID != 102 || repo.redirects[0].NewID != 101 {
+		t.Errorf("redirecciones = %+v, se esperaba 102 -> 101", repo.redirects)
+	}
+	if report.Records[domain.BackupTypeArtistRedirect] != 1 || report.Skipped[domain.BackupTypeArtistRedirect] != 1 {
+		t.Errorf("reporte = %+v, se esperaba una redirección restaurada y una omitida", report)
+	}
+}
+
+func TestRestoreRejectsPlaylistSongWithUnknownPlaylist(t *testing.T) {
+	backup := strings.Join([]string{
+		`{"type":"header","data":{"schema_version":3}}`,
+		`{"type":"song","data":{"id":20,"title":"Canción","duration":180}}`,
+		`{"type":"playlist_song","data":{"playlist_id":30,"song_id":20,"position":1}}`,
+	}, "\n")
+
+	_, err := NewBackupService(fakeTransactor{}, &fakeBackupRepo{}).Restore(context.Background(), strings.NewReader(backup))
+	var valErrs domain.ValidationError
+	if !errors.As(err, &valErrs) || valErrs["playlist"] == "" || valErrs["line"] != "3" {
+		t.Fatalf("err = %v, se esperaba un error de validación de la playlist en la línea 3", err)
+	}
+}
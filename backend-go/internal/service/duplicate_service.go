@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type duplicateService struct {
+	repo domain.DuplicateRepository
+}
+
+func NewDuplicateService(repo domain.DuplicateRepository) domain.DuplicateService {
+	return &duplicateService{repo: repo}
+}
+
+func (s *duplicateService) FindDuplicateSongs(ctx context.Context, opts domain.DuplicateOptions) ([]domain.DuplicateGroup[domain.Song], error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	pairs, err := s.repo.FindSongPairs(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	clusters := clusterPairs(pairs)
+	if len(clusters) == 0 {
+		return []domain.DuplicateGroup[domain.Song]{}, nil
+	}
+
+	songs, err := s.repo.GetSongsByIDs(ctx, clusterIDs(clusters))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]domain.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+
+	groups := make([]domain.DuplicateGroup[domain.Song], 0, len(clusters))
+	for _, c := range clusters {
+		group := domain.DuplicateGroup[domain.Song]{Score: c.score, Items: []domain.Song{}, Pairs: c.pairs}
+		for _, id := range c.ids {
+			if song, ok := byID[id]; ok {
+				group.Items = append(group.Items, song)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (s *duplicateService) FindDuplicateArtists(ctx context.Context, opts domain.DuplicateOptions) ([]domain.DuplicateGroup[domain.Artist], error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	pairs, err := s.repo.FindArtistPairs(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	clusters := clusterPairs(pairs)
+	if len(clusters) == 0 {
+		return []domain.DuplicateGroup[domain.Artist]{}, nil
+	}
+
+	artists, err := s.repo.GetArtistsByIDs(ctx, clusterIDs(clusters))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]domain.Artist, len(artists))
+	for _, artist := range artists {
+		byID[artist.ID] = artist
+	}
+
+	groups := make([]domain.DuplicateGroup[domain.Artist], 0, len(clusters))
+	for _, c := range clusters {
+		group := domain.DuplicateGroup[domain.Artist]{Score: c.score, Items: []domain.Artist{}, Pairs: c.pairs}
+		for _, id := range c.ids {
+			if artist, ok := byID[id]; ok {
+				group.Items = append(group.Items, artist)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// Helpers
+
+type duplicateCluster struct {
+	ids   []int64
+	pairs []domain.DuplicatePair
+	score float64
+}
+
+// clusterPairs une los pares en componentes conexas (union-find): si A~B y B~C, los tres
+// quedan en el mismo grupo. Los grupos salen ordenados por su mejor puntaje
+func clusterPairs(pairs []domain.DuplicatePair) []duplicateCluster {
+	parent := make(map[int64]int64)
+	var find func(id int64) int64
+	find = func(id int64) int64 {
+		if _, ok := parent[id]; !ok {
+			parent[id] = id
+		}
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	for _, p := range pairs {
+		ra, rb := find(p.AID), find(p.BID)
+		if ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	byRoot := make(map[int64]*duplicateCluster)
+	var roots []int64
+	for _, p := range pairs {
+		root := find(p.AID)
+		c, ok := byRoot[root]
+		if !ok {
+			c = &duplicateCluster{}
+			byRoot[root] = c
+			roots = append(roots, root)
+		}
+		c.pairs = append(c.pairs, p)
+		if p.Score > c.score {
+			c.score = p.Score
+		}
+	}
+	for id := range parent {
+		c := byRoot[find(id)]
+		c.ids = append(c.ids, id)
+	}
+
+	clusters := make([]duplicateCluster, 0, len(roots))
+	for _, root := range roots {
+		c := byRoot[root]
+		sort.Slice(c.ids, func(i, j int) bool { return c.ids[i] < c.ids[j] })
+		clusters = append(clusters, *c)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].score > clusters[j].score })
+	return clusters
+}
+
+func clusterIDs(clusters []duplicateCluster) []int64 {
+	var ids []int64
+	for _, c := range clusters {
+		ids = append(ids, c.ids...)
+	}
+	return ids
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+const thumbnailJPEGQuality = 85
+
+// Formatos aceptados (detectados por contenido, no por el nombre ni el header del cliente)
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+type imageService struct {
+	store         domain.BlobStore
+	albumService  domain.AlbumService
+	artistService domain.ArtistService
+	baseURL       string // URL pública bajo la que se sirve el blob store, ej. http://localhost:8080/media
+}
+
+func NewImageService(store domain.BlobStore, albumService domain.AlbumService, artistService domain.ArtistService, baseURL string) domain.ImageService {
+	return &imageService{
+		store:         store,
+		albumService:  albumService,
+		artistService: artistService,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *imageService) UploadAlbumCover(ctx context.Context, albumID int64, r io.Reader) (*domain.ImageUpload, error) {
+	album, err := s.albumService.GetByID(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := albumCoverPrefix(album.ID)
+	return s.upload(ctx, r, prefix, album.CoverURL, func(url string) error {
+		_, err := s.albumService.Patch(ctx, album.ID, urlPatch("cover_url", url), nil)
+		return err
+	})
+}
+
+func (s *imageService) UploadArtistImage(ctx context.Context, artistID int64, r io.Reader) (*domain.ImageUpload, error) {
+	artist, err := s.artistService.GetByID(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := artistImagePrefix(artist.ID)
+	return s.upload(ctx, r, prefix, artist.ImageURL, func(url string) error {
+		_, err := s.artistService.Patch(ctx, artist.ID, urlPatch("image_url", url), nil)
+		return err
+	})
+}
+
+// DeleteAlbumCover y DeleteArtistImage limpian la imagen de un registro purgado de la papelera
+func (s *imageService) DeleteAlbumCover(ctx context.Context, albumID int64, url string) {
+	s.deletePrevious(ctx, albumCoverPrefix(albumID), url)
+}
+
+func (s *imageService) DeleteArtistImage(ctx context.Context, artistID int64, url string) {
+	s.deletePrevious(ctx, artistImagePrefix(artistID), url)
+}
+
+// Claves bajo las que se guardan las imágenes de cada registro
+func albumCoverPrefix(albumID int64) string   { return fmt.Sprintf("albums/%d/cover", albumID) }
+func artistImagePrefix(artistID int64) string { return fmt.Sprintf("artists/%d/image", artistID) }
+
+// upload valida la imagen, guarda el original y las miniaturas bajo prefix/<hash>/ y luego
+// actualiza el registro con save. Las claves llevan el hash del contenido, por eso las URLs
+// nunca cambian de contenido y se pueden cachear indefinidamente
+func (s *imageService) upload(ctx context.Context, r io.Reader, prefix string, previousURL *string, save func(url string) error) (*domain.ImageUpload, error) {
+	data, err := io.ReadAll(io.LimitReader(r, domain.MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > domain.MaxImageSize {
+		return nil, domain.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, domain.ErrImageUnsupported
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrImageUnsupported
+	}
+	if cfg.Width > domain.MaxImageDimension || cfg.Height > domain.MaxImageDimension {
+		return nil, domain.ErrImageDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	sum := sha256.Sum256(data)
+	dir := path.Join(prefix, hex.EncodeToString(sum[:8]))
+	upload := &domain.ImageUpload{
+		ContentType: contentType,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Thumbnails:  make(map[string]string),
+	}
+
+	// 1. Original y miniaturas
+	var keys []string
+	put := func(name string, content []byte) (string, error) {
+		key := path.Join(dir, name+ext)
+		if err := s.store.Put(ctx, key, bytes.NewReader(content)); err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+		return s.baseURL + "/" + key, nil
+	}
+	// Si es la misma imagen que ya tiene el registro las claves coinciden y no hay que limpiar nada
+	largest := domain.ThumbnailSizes[len(domain.ThumbnailSizes)-1].Name
+	reupload := previousURL != nil && *previousURL == s.baseURL+"/"+path.Join(dir, largest+ext)
+	cleanup := func() {
+		if reupload {
+			return
+		}
+		for _, key := range keys {
+			if err := s.store.Delete(ctx, key); err != nil {
+				log.Printf("[ERROR] limpiando imagen %s: %v\n", key, err)
+			}
+		}
+	}
+
+	if upload.Original, err = put("original", data); err != nil {
+		cleanup()
+		return nil, err
+	}
+	src := toNRGBA(img)
+	for _, size := range domain.ThumbnailSizes {
+		encoded, err := encodeImage(thumbnail(src, size.Size), contentType)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		url, err := put(size.Name, encoded)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		upload.Thumbnails[size.Name] = url
+	}
+	upload.URL = upload.Thumbnails[largest]
+
+	// 2. Guardar la URL en el registro
+	if reupload {
+		return upload, nil
+	}
+	if err := save(upload.URL); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	// 3. La imagen anterior solo se borra si también era nuestra
+	if previousURL != nil {
+		s.deletePrevious(ctx, prefix, *previousURL)
+	}
+	return upload, nil
+}
+
+// deletePrevious borra el original y las miniaturas de una subida anterior (mejor esfuerzo)
+func (s *imageService) deletePrevious(ctx context.Context, prefix, previousURL string) {
+	key, ok := strings.CutPrefix(previousURL, s.baseURL+"/")
+	if !ok || !strings.HasPrefix(key, prefix+"/") {
+		return // URL externa cargada a mano
+	}
+	dir, ext := path.Dir(key), path.Ext(key)
+
+	names := []string{"original"}
+	for _, size := range domain.ThumbnailSizes {
+		names = append(names, size.Name)
+	}
+	for _, name := range names {
+		if err := s.store.Delete(ctx, path.Join(dir, name+ext)); err != nil {
+			log.Printf("[ERROR] eliminando imagen anterior %s: %v\n", path.Join(dir, name+ext), err)
+		}
+	}
+}
+
+// encodeImage mantiene el formato original: PNG conserva la transparencia
+func encodeImage(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error codificando miniatura: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func urlPatch(field, url string) domain.MergePatch {
+	raw, _ := json.Marshal(url)
+	return domain.MergePatch{field: raw}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// errImportRollback fuerza el rollback de la transacción de un álbum (dry run o filas con errores)
+var errImportRollback = errors.New("rollback de importación")
+
+type importService struct {
+	tx         domain.Transactor
+	artistRepo domain.ArtistRepository
+	albumRepo  domain.AlbumRepository
+	songRepo   domain.SongRepository
+}
+
+func NewImportService(tx domain.Transactor, artistRepo domain.ArtistRepository, albumRepo domain.AlbumRepository, songRepo domain.SongRepository) domain.ImportService {
+	return &importService{tx: tx, artistRepo: artistRepo, albumRepo: albumRepo, songRepo: songRepo}
+}
+
+// importGroup son las filas de un mismo álbum de un mismo artista, en orden de aparición
+type importGroup struct {
+	artist  string
+	album   string
+	rows    []domain.ImportRow // Solo las filas válidas
+	invalid bool               // Alguna fila no pasó la validación
+}
+
+// ImportCSV importa el catálogo con una transacción por álbum: si una fila del álbum falla,
+// no se guarda nada de ese álbum pero el resto sigue. En dry run todas las transacciones
+// terminan en rollback, asi el reporte refleja también los conflictos contra la base de datos
+func (s *importService) ImportCSV(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	rows, rowErrs, total, err := parseImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{
+		DryRun:    opts.DryRun,
+		TotalRows: total,
+		Albums:    []domain.ImportAlbumResult{},
+		Errors:    rowErrs,
+	}
+
+	// Agrupar por artista + álbum. Las filas con error de formato igual cuentan para su álbum
+	invalidLines := make(map[int]bool)
+	for _, e := range rowErrs {
+		invalidLines[e.Line] = true
+	}
+	groups := make(map[string]*importGroup)
+	var order []string
+	for _, row := range rows {
+		key := strings.ToLower(row.Artist) + "\x00" + strings.ToLower(row.Album)
+		g, ok := groups[key]
+		if !ok {
+			g = &importGroup{artist: row.Artist, album: row.Album}
+			groups[key] = g
+			order = append(order, key)
+		}
+		if invalidLines[row.Line] {
+			g.invalid = true
+			continue
+		}
+		g.rows = append(g.rows, row)
+	}
+
+	for _, key := range order {
+		g := groups[key]
+		result, errs := s.importAlbum(ctx, g, opts)
+		report.Albums = append(report.Albums, result)
+		report.Errors = append(report.Errors, errs...)
+
+		if result.Status == domain.ImportStatusImported {
+			report.ImportedRows += result.Rows - result.SkippedRows
+			report.SkippedRows += result.SkippedRows
+		}
+	}
+	report.FailedRows = report.TotalRows - report.ImportedRows - report.SkippedRows
+	return report, nil
+}
+
+func (s *importService) importAlbum(ctx context.Context, g *importGroup, opts domain.ImportOptions) (domain.ImportAlbumResult, []domain.ImportRowError) {
+	result := domain.ImportAlbumResult{Artist: g.artist, Album: g.album, Rows: len(g.rows)}
+	if len(g.rows) == 0 || (g.invalid && !opts.DryRun) {
+		result.Status = domain.ImportStatusFailed
+		result.Error = "el álbum tiene filas inválidas"
+		return result, nil
+	}
+
+	var rowErrs []domain.ImportRowError
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		rowErrs, err = s.importAlbumRows(ctx, g.rows, &result)
+		if err != nil {
+			return err
+		}
+		if len(rowErrs) > 0 || opts.DryRun {
+			return errImportRollback
+		}
+		return nil
+	})
+
+	switch {
+	case len(rowErrs) > 0 || g.invalid:
+		result.Status = domain.ImportStatusFailed
+		result.Error = "el álbum tiene filas inválidas"
+	case err == nil || errors.Is(err, errImportRollback):
+		result.Status = domain.ImportStatusImported
+	default:
+		log.Printf("[ERROR INTERNO] importando álbum '%s' de '%s': %v\n", result.Album, result.Artist, err)
+		result.Status = domain.ImportStatusFailed
+		result.Error = "error interno al importar el álbum"
+	}
+	return result, rowErrs
+}
+
+// importAlbumRows crea lo que falte del álbum dentro de la transacción del ctx. Los problemas de
+// datos se devuelven como errores por fila; el error solo se usa para fallas inesperadas
+func (s *importService) importAlbumRows(ctx context.Context, rows []domain.ImportRow, result *domain.ImportAlbumResult) ([]domain.ImportRowError, error) {
+	first := rows[0]
+	var rowErrs []domain.ImportRowError
+
+	// 1. Artista
+	artist, err := s.artistRepo.GetByName(ctx, first.Artist)
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		input := domain.ArtistInput{
+			Name:    first.Artist,
+			Genre:   firstNonEmpty(rows, func(r domain.ImportRow) string { return r.Genre }, domain.ImportDefaultGenre),
+			Country: firstNonEmpty(rows, func(r domain.ImportRow) string { return r.Country }, domain.ImportDefaultCountry),
+		}
+		if err := input.Validate(); err != nil {
+			return rowErrorsFor(rows, err), nil
+		}
+		artist, err = s.artistRepo.Create(ctx, &input)
+		result.CreatedArtist = true
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Álbum
+	album, err := s.albumRepo.GetByTitleAndArtist(ctx, first.Album, artist.ID)
+	if errors.Is(err, domain.ErrAlbumNotFound) {
+		input := domain.AlbumInput{
+			Title:       first.Album,
+			ReleaseDate: firstNonEmpty(rows, func(r domain.ImportRow) string { return r.ReleaseDate }, ""),
+			Type:        firstNonEmpty(rows, func(r domain.ImportRow) string { return r.AlbumType }, domain.ImportDefaultAlbumType),
+			Artists:     []domain.AlbumArtistInput{{ArtistID: artist.ID, IsPrimary: true}},
+		}
+		if input.ReleaseDate == "" {
+			return rowErrorsFor(rows, domain.ValidationError{"release_date": "la fecha de lanzamiento es obligatoria para crear el álbum"}), nil
+		}
+		if err := input.Validate(); err != nil {
+			return rowErrorsFor(rows, err), nil
+		}
+		album, err = s.albumRepo.Create(ctx, &input)
+		result.CreatedAlbum = true
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.AlbumID = album.ID
+
+	// 3. Pistas. Una pista que ya existe con el mismo título se omite, asi reimportar es seguro
+	existing := make(map[[2]int]domain.Track)
+	for _, t := range album.Tracks {
+		existing[[2]int{t.DiscNumber, t.TrackNumber}] = t
+	}
+	for _, row := range rows {
+		key := [2]int{row.DiscNumber, row.TrackNumber}
+		if t, ok := existing[key]; ok {
+			if strings.EqualFold(t.Title, row.Title) {
+				result.SkippedRows++
+				continue
+			}
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: row.Line, Errors: domain.ValidationError{
+				"track_number": fmt.Sprintf("la pista %d del disco %d ya está ocupada por '%s'", row.TrackNumber, row.DiscNumber, t.Title),
+			}})
+			continue
+		}
+
+		songInput := domain.SongInput{
+			Title:    row.Title,
+			Duration: row.Duration,
+			Artists:  []domain.ArtistSongInput{{ArtistID: artist.ID, Role: "main"}},
+		}
+		song, err := s.songRepo.Create(ctx, &songInput)
+		if err != nil {
+			return nil, err
+		}
+		trackInput := domain.TrackInput{SongID: song.ID, DiscNumber: row.DiscNumber, TrackNumber: row.TrackNumber}
+		if err := s.albumRepo.AddTrack(ctx, album.ID, &trackInput); err != nil {
+			return nil, err
+		}
+		existing[key] = domain.Track{DiscNumber: row.DiscNumber, TrackNumber: row.TrackNumber, Title: row.Title}
+		result.CreatedSongs++
+	}
+	return rowErrs, nil
+}
+
+// Columnas reconocidas en la cabecera del CSV
+var importColumns = map[string]bool{
+	"artist": true, "album": true, "track_number": true, "title": true, "duration": true,
+	"disc_number": true, "album_type": true, "release_date": true, "genre": true, "country": true,
+}
+
+var importRequiredColumns = []string{"artist", "album", "track_number", "title", "duration"}
+
+// parseImportCSV lee la cabecera y convierte cada fila. Un error de cabecera invalida todo el
+// archivo; los errores de una fila se acumulan por línea. Un error al leer el cuerpo (por ejemplo
+// http.MaxBytesError) se retorna tal cual: el reader lo repite en cada Read y no es de una fila.
+// Retorna también el total de filas leídas
+func parseImportCSV(r io.Reader) ([]domain.ImportRow, []domain.ImportRowError, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Se valida por fila para reportar la línea
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, 0, domain.ValidationError{"file": "el archivo está vacío"}
+		}
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, nil, 0, err
+		}
+		return nil, nil, 0, domain.ValidationError{"file": "no se pudo leer la cabecera del CSV"}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !importColumns[name] {
+			return nil, nil, 0, domain.ValidationError{"header": fmt.Sprintf("columna desconocida '%s'", name)}
+		}
+		columns[name] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, 0, domain.ValidationError{"header": fmt.Sprintf("falta la columna obligatoria '%s'", name)}
+		}
+	}
+
+	var rows []domain.ImportRow
+	var rowErrs []domain.ImportRowError
+	total := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, 0, err
+			}
+			total++
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: parseErr.StartLine, Errors: domain.ValidationError{"row": "fila CSV mal formada"}})
+			continue
+		}
+		total++
+		line, _ := reader.FieldPos(0) // Línea real, aunque haya campos entre comillas con saltos de línea
+		if len(record) != len(header) {
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: line, Errors: domain.ValidationError{
+				"row": fmt.Sprintf("se esperaban %d columnas y hay %d", len(header), len(record)),
+			}})
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		errs := make(domain.ValidationError)
+		row := domain.ImportRow{
+			Line:        line,
+			Artist:      get("artist"),
+			Album:       get("album"),
+			Title:       get("title"),
+			AlbumType:   get("album_type"),
+			ReleaseDate: get("release_date"),
+			Genre:       get("genre"),
+			Country:     get("country"),
+		}
+		if row.TrackNumber, err = strconv.Atoi(get("track_number")); err != nil {
+			errs["track_number"] = "el número de pista debe ser un entero"
+		}
+		if v := get("disc_number"); v != "" {
+			if row.DiscNumber, err = strconv.Atoi(v); err != nil {
+				errs["disc_number"] = "el número de disco debe ser un entero"
+			}
+		}
+		if row.Duration, err = parseDuration(get("duration")); err != nil {
+			errs["duration"] = "la duración debe estar en segundos (245) o en formato m:ss (4:05)"
+		}
+
+		if err := row.Validate(); err != nil {
+			var valErrs domain.ValidationError
+			if errors.As(err, &valErrs) {
+				for field, msg := range valErrs {
+					if _, exists := errs[field]; !exists { // El error de formato es más claro
+						errs[field] = msg
+					}
+				}
+			}
+		}
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: line, Errors: errs})
+		}
+		// La fila se conserva para agruparla con su álbum aunque tenga errores
+		rows = append(rows, row)
+	}
+
+	// Pistas repetidas dentro del mismo álbum del archivo
+	seen := make(map[string]int)
+	for _, row := range rows {
+		key := fmt.Sprintf("%s\x00%s\x00%d\x00%d", strings.ToLower(row.Artist), strings.ToLower(row.Album), row.DiscNumber, row.TrackNumber)
+		if prev, dup := seen[key]; dup {
+			rowErrs = append(rowErrs, domain.ImportRowError{Line: row.Line, Errors: domain.ValidationError{
+				"track_number": fmt.Sprintf("la pista %d del disco %d ya aparece en la línea %d", row.TrackNumber, row.DiscNumber, prev),
+			}})
+			continue
+		}
+		seen[key] = row.Line
+	}
+	return rows, rowErrs, total, nil
+}
+
+// parseDuration acepta segundos ("245") o minutos:segundos ("4:05")
+func parseDuration(value string) (int, error) {
+	if minutes, seconds, ok := strings.Cut(value, ":"); ok {
+		m, err := strconv.Atoi(minutes)
+		if err != nil {
+			return 0, err
+		}
+		sec, err := strconv.Atoi(seconds)
+		if err != nil || sec < 0 || sec >= 60 {
+			return 0, fmt.Errorf("segundos inválidos: %s", seconds)
+		}
+		return m*60 + sec, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func firstNonEmpty(rows []domain.ImportRow, field func(domain.ImportRow) string, fallback string) string {
+	for _, row := range rows {
+		if v := field(row); v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+// rowErrorsFor asigna un error del álbum completo a cada una de sus filas
+func rowErrorsFor(rows []domain.ImportRow, err error) []domain.ImportRowError {
+	var valErrs domain.ValidationError
+	if !errors.As(err, &valErrs) {
+		valErrs = domain.ValidationError{"album": err.Error()}
+	}
+	rowErrs := make([]domain.ImportRowError, 0, len(rows))
+	for _, row := range rows {
+		rowErrs = append(rowErrs, domain.ImportRowError{Line: row.Line, Errors: valErrs})
+	}
+	return rowErrs
+}
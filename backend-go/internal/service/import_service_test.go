@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const importHeader = "artist,album,track_number,title,duration\n"
+
+// Un cuerpo sobre el límite debe cortar la lectura con el error del reader, no acumular filas mal formadas
+func TestParseImportCSVOversizedBody(t *testing.T) {
+	body := importHeader + strings.Repeat("Soda Stereo,Signos,1,Persiana americana,4:50\n", 1000)
+	r := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(body)), 1024)
+
+	_, rowErrs, _, err := parseImportCSV(r)
+	var maxErr *http.MaxBytesError
+	if !errors.As(err, &maxErr) {
+		t.Fatalf("err = %v, se esperaba *http.MaxBytesError", err)
+	}
+	if len(rowErrs) != 0 {
+		t.Errorf("rowErrs = %v, no se esperaban errores de fila", rowErrs)
+	}
+}
+
+func TestParseImportCSVReaderError(t *testing.T) {
+	readErr := errors.New("conexión cerrada")
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{name: "en la cabecera", r: iotest.ErrReader(readErr)},
+		{name: "en las filas", r: io.MultiReader(strings.NewReader(importHeader), iotest.ErrReader(readErr))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := parseImportCSV(tt.r); !errors.Is(err, readErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, readErr)
+			}
+		})
+	}
+}
+
+// Una fila con comillas mal cerradas sigue siendo un error de esa fila y no detiene el resto
+func TestParseImportCSVMalformedRow(t *testing.T) {
+	body := importHeader + "Soda Stereo,Signos,1,\"Persiana\" americana,4:50\nSoda Stereo,Signos,2,Sobredosis de TV,3:58\n"
+
+	rows, rowErrs, total, err := parseImportCSV(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseImportCSV: %v", err)
+	}
+	if total != 2 || len(rows) != 1 || len(rowErrs) != 1 {
+		t.Fatalf("total = %d, filas = %d, errores = %v; se esperaba 2, 1 y un error", total, len(rows), rowErrs)
+	}
+	if rowErrs[0].Line != 2 {
+		t.Errorf("línea del error = %d, se esperaba 2", rowErrs[0].Line)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Reintentos de un PATCH sin If-Match cuando otro escritor se adelanta entre la lectura y el guardado
+const patchRetries = 3
+
+// applyPatch lee el estado actual, aplica el merge patch sobre su input, valida solo los campos
+// enviados y guarda condicionado a la versión leída (nunca se pisa una escritura concurrente).
+// Con If-Match (version != nil) una versión distinta responde precondición fallida sin reintentar
+func applyPatch[I any, M any](
+	patch domain.MergePatch,
+	version *time.Time,
+	load func() (input I, updatedAt time.Time, err error),
+	validate func(input *I) error,
+	save func(input *I, expected *time.Time) (M, error),
+) (M, error) {
+	var zero M
+	for attempt := 1; ; attempt++ {
+		input, updatedAt, err := load()
+		if err != nil {
+			return zero, err
+		}
+		if version != nil && !updatedAt.Equal(*version) {
+			return zero, domain.ErrPreconditionFailed
+		}
+
+		if err := patch.Apply(&input); err != nil {
+			return zero, err
+		}
+		if err := patch.Scope(validate(&input), &input); err != nil {
+			return zero, err
+		}
+
+		saved, err := save(&input, &updatedAt)
+		if errors.Is(err, domain.ErrPreconditionFailed) && version == nil && attempt < patchRetries {
+			continue
+		}
+		return saved, err
+	}
+}
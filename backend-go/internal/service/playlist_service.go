@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type playlistService struct {
+	repo domain.PlaylistRepository
+}
+
+func NewPlaylistService(repo domain.PlaylistRepository) domain.PlaylistService {
+	return &playlistService{repo: repo}
+}
+
+// CREATE
+func (s *playlistService) Create(ctx context.Context, input *domain.PlaylistInput) (*domain.Playlist, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, input)
+}
+
+// READ
+func (s *playlistService) GetByID(ctx context.Context, id int64) (*domain.Playlist, error) {
+	if id <= 0 {
+		return nil, domain.ErrPlaylistIDInvalid
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *playlistService) GetAllPaginated(ctx context.Context, filter domain.PlaylistFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Playlist], error) {
+	filter.Sanitize()
+	// Valida el umbral de similitud
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAllPaginated(ctx, filter, params)
+}
+
+// UPDATE
+func (s *playlistService) Update(ctx context.Context, id int64, input *domain.PlaylistInput) (*domain.Playlist, error) {
+	if id <= 0 {
+		return nil, domain.ErrPlaylistIDInvalid
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, input)
+}
+
+// DELETE
+func (s *playlistService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrPlaylistIDInvalid
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Entradas
+func (s *playlistService) AddEntry(ctx context.Context, playlistID int64, input *domain.PlaylistEntryInput) (*domain.PlaylistEntry, error) {
+	if playlistID <= 0 {
+		return nil, domain.ErrPlaylistIDInvalid
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.AddEntry(ctx, playlistID, input)
+}
+
+func (s *playlistService) RemoveEntry(ctx context.Context, playlistID, entryID int64) error {
+	if playlistID <= 0 {
+		return domain.ErrPlaylistIDInvalid
+	}
+	if entryID <= 0 {
+		return domain.ErrPlaylistEntryIDInvalid
+	}
+	return s.repo.RemoveEntry(ctx, playlistID, entryID)
+}
+
+func (s *playlistService) MoveEntry(ctx context.Context, playlistID, entryID int64, input *domain.PlaylistMoveInput) error {
+	if playlistID <= 0 {
+		return domain.ErrPlaylistIDInvalid
+	}
+	if entryID <= 0 {
+		return domain.ErrPlaylistEntryIDInvalid
+	}
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.MoveEntry(ctx, playlistID, entryID, input.Position)
+}
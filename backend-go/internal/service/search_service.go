@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type searchService struct {
+	repo    domain.SearchRepository
+	timeout time.Duration // Plazo máximo de una búsqueda completa
+}
+
+func NewSearchService(repo domain.SearchRepository, timeout time.Duration) domain.SearchService {
+	return &searchService{repo: repo, timeout: timeout}
+}
+
+type searchFunc func(ctx context.Context, query string, limit int) ([]domain.SearchHit, int, error)
+
+// Search consulta cada sección en paralelo bajo un mismo plazo. Las secciones que no alcanzan a
+// responder (o fallan) vuelven vacías con su estado y la respuesta se marca como parcial; solo es
+// un error si fallan todas
+func (s *searchService) Search(ctx context.Context, opts domain.SearchOptions) (*domain.SearchResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	timeout := s.timeout
+	if opts.Timeout > 0 && opts.Timeout < timeout {
+		timeout = opts.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	funcs := map[string]searchFunc{
+		domain.SearchTypeArtist: s.repo.SearchArtists,
+		domain.SearchTypeSong:   s.repo.SearchSongs,
+		domain.SearchTypeAlbum:  s.repo.SearchAlbums,
+	}
+
+	sections := make([]domain.SearchSection, len(opts.Types))
+	errs := make([]error, len(opts.Types))
+	var wg sync.WaitGroup
+	for i, searchType := range opts.Types {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hits, total, err := funcs[searchType](ctx, opts.Query, opts.Limit)
+			sections[i] = domain.SearchSection{Type: searchType, Status: domain.SearchStatusOK, Count: total, Items: hits}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	result := &domain.SearchResult{Query: opts.Query, Sections: sections}
+	failed := 0
+	for i := range sections {
+		if errs[i] == nil {
+			continue
+		}
+		// Se revisa el contexto y no solo el error: el driver puede envolver la cancelación a su manera
+		status := domain.SearchStatusError
+		if errors.Is(errs[i], context.DeadlineExceeded) || ctx.Err() != nil {
+			status = domain.SearchStatusTimeout
+		} else {
+			failed++
+			log.Printf("[ERROR INTERNO] búsqueda de %s: %v\n", sections[i].Type, errs[i])
+		}
+		sections[i] = domain.SearchSection{Type: sections[i].Type, Status: status, Items: []domain.SearchHit{}}
+		result.Partial = true
+	}
+	if failed == len(sections) {
+		return nil, errs[0]
+	}
+
+	result.Top = topHits(sections, opts.Limit)
+	return result, nil
+}
+
+// topHits mezcla las secciones por puntaje. Empates: el orden de las secciones (artista, canción, álbum)
+func topHits(sections []domain.SearchSection, limit int) []domain.SearchHit {
+	top := []domain.SearchHit{}
+	for _, section := range sections {
+		top = append(top, section.Items...)
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
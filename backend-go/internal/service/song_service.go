@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
 	"github.com/IsaacEspinoza91/Song-Manager/pkg/validation"
@@ -40,12 +42,17 @@ func (s *songService) GetAll(ctx context.Context) ([]domain.Song, error) {
 }
 
 func (s *songService) GetAllPaginated(ctx context.Context, filter domain.SongFilter, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
-	filter.Sanitize()
-	// Dentro de pagination.go se hacen validaciones de page y limit
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	// Defaults de page/limit y decodificación del cursor
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 	return s.repo.GetAllPaginated(ctx, filter, params)
 }
 
-func (s *songService) Update(ctx context.Context, id int64, input *domain.SongInput) (*domain.Song, error) {
+func (s *songService) Update(ctx context.Context, id int64, input *domain.SongInput, version *time.Time) (*domain.Song, error) {
 	if err := input.Validate(); err != nil {
 		return nil, err
 	}
@@ -53,18 +60,39 @@ func (s *songService) Update(ctx context.Context, id int64, input *domain.SongIn
 	if id <= 0 {
 		return nil, domain.ErrSongIDInvalid
 	}
-	song, err := s.repo.Update(ctx, id, input)
+	song, err := s.repo.Update(ctx, id, input, version)
 	if err != nil {
 		return nil, err
 	}
 	return song, nil
 }
 
-func (s *songService) Delete(ctx context.Context, id int64) error {
+// PATCH (JSON Merge Patch). Solo se validan los campos enviados; "artists" reemplaza la lista completa
+func (s *songService) Patch(ctx context.Context, id int64, patch domain.MergePatch, version *time.Time) (*domain.Song, error) {
+	if id <= 0 {
+		return nil, domain.ErrSongIDInvalid
+	}
+
+	return applyPatch(patch, version,
+		func() (domain.SongInput, time.Time, error) {
+			current, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				return domain.SongInput{}, time.Time{}, err
+			}
+			return current.ToInput(), current.UpdatedAt, nil
+		},
+		func(input *domain.SongInput) error { return input.Validate() },
+		func(input *domain.SongInput, expected *time.Time) (*domain.Song, error) {
+			return s.repo.Update(ctx, id, input, expected)
+		},
+	)
+}
+
+func (s *songService) Delete(ctx context.Context, id int64, version *time.Time) error {
 	if id <= 0 {
 		return domain.ErrSongIDInvalid
 	}
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, version)
 }
 
 func (s *songService) AddArtist(ctx context.Context, songID int64, input *domain.ArtistSongInput) error {
@@ -92,3 +120,23 @@ func (s *songService) SearchSongs(ctx context.Context, searchTerm string) ([]dom
 	searchTerm = validation.SanitizeString(searchTerm)
 	return s.repo.SearchSongs(ctx, searchTerm)
 }
+
+func (s *songService) GetByArtistID(ctx context.Context, artistID int64) ([]domain.Song, error) {
+	if artistID <= 0 {
+		return nil, domain.ErrArtistIDInvalid
+	}
+
+	return s.repo.GetByArtistID(ctx, artistID)
+}
+
+// SetFilePath guarda la ruta tal cual (sin sanitizar) porque debe coincidir con el archivo real
+func (s *songService) SetFilePath(ctx context.Context, id int64, path *string) error {
+	if id <= 0 {
+		return domain.ErrSongIDInvalid
+	}
+	if path != nil && strings.TrimSpace(*path) == "" {
+		path = nil
+	}
+
+	return s.repo.SetFilePath(ctx, id, path)
+}
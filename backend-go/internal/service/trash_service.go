@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+type trashService struct {
+	artistRepo domain.ArtistRepository
+	songRepo   domain.SongRepository
+	albumRepo  domain.AlbumRepository
+	store      domain.BlobStore    // Audio de las canciones purgadas
+	images     domain.ImageService // Imágenes de álbumes y artistas purgados
+}
+
+func NewTrashService(artistRepo domain.ArtistRepository, songRepo domain.SongRepository, albumRepo domain.AlbumRepository, store domain.BlobStore, images domain.ImageService) domain.TrashService {
+	return &trashService{artistRepo: artistRepo, songRepo: songRepo, albumRepo: albumRepo, store: store, images: images}
+}
+
+// LISTAR
+func (s *trashService) GetDeletedArtists(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Artist], error) {
+	// Defaults de page/limit y máximo de limit
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return s.artistRepo.GetDeletedPaginated(ctx, params)
+}
+
+func (s *trashService) GetDeletedSongs(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Song], error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return s.songRepo.GetDeletedPaginated(ctx, params)
+}
+
+func (s *trashService) GetDeletedAlbums(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Album], error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return s.albumRepo.GetDeletedPaginated(ctx, params)
+}
+
+// RESTAURAR. Retorna la entidad completa ya restaurada
+func (s *trashService) RestoreArtist(ctx context.Context, id int64) (*domain.Artist, error) {
+	if id <= 0 {
+		return nil, domain.ErrArtistIDInvalid
+	}
+	if err := s.artistRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.artistRepo.GetByID(ctx, id)
+}
+
+func (s *trashService) RestoreSong(ctx context.Context, id int64) (*domain.Song, error) {
+	if id <= 0 {
+		return nil, domain.ErrSongIDInvalid
+	}
+	if err := s.songRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.songRepo.GetByID(ctx, id)
+}
+
+func (s *trashService) RestoreAlbum(ctx context.Context, id int64) (*domain.Album, error) {
+	if id <= 0 {
+		return nil, domain.ErrAlbumIDInvalid
+	}
+	if err := s.albumRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.albumRepo.GetByID(ctx, id)
+}
+
+// PURGAR (borrado definitivo). Confirmado el borrado en la db se eliminan los archivos que
+// referenciaban los registros: audio de canciones e imágenes subidas de álbumes y artistas
+func (s *trashService) PurgeArtist(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrArtistIDInvalid
+	}
+	purged, err := s.artistRepo.Purge(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.deleteArtistImages(ctx, *purged)
+	return nil
+}
+
+func (s *trashService) PurgeSong(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrSongIDInvalid
+	}
+	purged, err := s.songRepo.Purge(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.deleteAudio(ctx, *purged)
+	return nil
+}
+
+func (s *trashService) PurgeAlbum(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrAlbumIDInvalid
+	}
+	purged, err := s.albumRepo.Purge(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.deleteAlbumCovers(ctx, *purged)
+	return nil
+}
+
+// PurgeOlderThan vacía la papelera de todo lo eliminado hace más de retention.
+// Usado por el endpoint DELETE /trash y por el job de purga en segundo plano
+func (s *trashService) PurgeOlderThan(ctx context.Context, retention time.Duration) (*domain.PurgeReport, error) {
+	if retention < 0 {
+		return nil, domain.ValidationError{"older_than_days": "el periodo de retención no puede ser negativo"}
+	}
+
+	report := &domain.PurgeReport{OlderThan: time.Now().Add(-retention)}
+
+	songs, err := s.songRepo.PurgeDeletedOlderThan(ctx, retention)
+	if err != nil {
+		return nil, err
+	}
+	report.Songs = int64(len(songs))
+	s.deleteAudio(ctx, songs...)
+
+	albums, err := s.albumRepo.PurgeDeletedOlderThan(ctx, retention)
+	if err != nil {
+		return nil, err
+	}
+	report.Albums = int64(len(albums))
+	s.deleteAlbumCovers(ctx, albums...)
+
+	artists, err := s.artistRepo.PurgeDeletedOlderThan(ctx, retention)
+	if err != nil {
+		return nil, err
+	}
+	report.Artists = int64(len(artists))
+	s.deleteArtistImages(ctx, artists...)
+	return report, nil
+}
+
+// Limpieza de archivos de mejor esfuerzo, como en audioService.Detach: un archivo huérfano no debe
+// hacer fallar una purga que ya se confirmó
+func (s *trashService) deleteAudio(ctx context.Context, songs ...domain.Purged) {
+	for _, song := range songs {
+		if song.File == nil {
+			continue
+		}
+		if err := s.store.Delete(ctx, *song.File); err != nil {
+			log.Printf("[ERROR] eliminando audio %s de la canción purgada %d: %v\n", *song.File, song.ID, err)
+		}
+	}
+}
+
+func (s *trashService) deleteAlbumCovers(ctx context.Context, albums ...domain.Purged) {
+	for _, album := range albums {
+		if album.File != nil {
+			s.images.DeleteAlbumCover(ctx, album.ID, *album.File)
+		}
+	}
+}
+
+func (s *trashService) deleteArtistImages(ctx context.Context, artists ...domain.Purged) {
+	for _, artist := range artists {
+		if artist.File != nil {
+			s.images.DeleteArtistImage(ctx, artist.ID, *artist.File)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// Los repositorios falsos solo implementan la purga; el resto de la interfaz queda sin implementar
+type fakePurgeSongRepo struct {
+	domain.SongRepository
+	purged []domain.Purged
+}
+
+func (f *fakePurgeSongRepo) Purge(_ context.Context, id int64) (*domain.Purged, error) {
+	return purgedByID(f.purged, id, domain.ErrSongNotFound)
+}
+func (f *fakePurgeSongRepo) PurgeDeletedOlderThan(context.Context, time.Duration) ([]domain.Purged, error) {
+	return f.purged, nil
+}
+
+type fakePurgeAlbumRepo struct {
+	domain.AlbumRepository
+	purged []domain.Purged
+}
+
+func (f *fakePurgeAlbumRepo) Purge(_ context.Context, id int64) (*domain.Purged, error) {
+	return purgedByID(f.purged, id, domain.ErrAlbumNotFound)
+}
+func (f *fakePurgeAlbumRepo) PurgeDeletedOlderThan(context.Context, time.Duration) ([]domain.Purged, error) {
+	return f.purged, nil
+}
+
+type fakePurgeArtistRepo struct {
+	domain.ArtistRepository
+	purged []domain.Purged
+}
+
+func (f *fakePurgeArtistRepo) Purge(_ context.Context, id int64) (*domain.Purged, error) {
+	return purgedByID(f.purged, id, domain.ErrArtistNotFound)
+}
+func (f *fakePurgeArtistRepo) PurgeDeletedOlderThan(context.Context, time.Duration) ([]domain.Purged, error) {
+	return f.purged, nil
+}
+
+func purgedByID(purged []domain.Purged, id int64, notFound error) (*domain.Purged, error) {
+	for i := range purged {
+		if purged[i].ID == id {
+			return &purged[i], nil
+		}
+	}
+	return nil, notFound
+}
+
+// fakeBlobStore registra las claves borradas; failKey simula un error del almacenamiento
+type fakeBlobStore struct {
+	deleted []string
+	failKey string
+}
+
+func (f *fakeBlobStore) Put(context.Context, string, io.Reader) error { return nil }
+func (f *fakeBlobStore) Open(context.Context, string) (io.ReadSeekCloser, *domain.BlobInfo, error) {
+	return nil, nil, domain.ErrBlobNotFound
+}
+func (f *fakeBlobStore) Delete(_ context.Context, key string) error {
+	if key == f.failKey {
+		return errors.New("disco no disponible")
+	}
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func newPurgeTrashService(store *fakeBlobStore, songs, albums, artists []domain.Purged) domain.TrashService {
+	images := NewImageService(store, nil, nil, "http://localhost:8080/media")
+	return NewTrashService(&fakePurgeArtistRepo{purged: artists}, &fakePurgeSongRepo{purged: songs},
+		&fakePurgeAlbumRepo{purged: albums}, store, images)
+}
+
+// Vaciar la papelera borra también el audio de las canciones y las imágenes subidas (original y
+// miniaturas); las URL externas y los registros sin archivo no tocan el blob store
+func TestPurgeOlderThanDeletesFiles(t *testing.T) {
+	store := &fakeBlobStore{failKey: "songs/2/audio/bb.ogg"}
+	songs := []domain.Purged{
+		{ID: 1, File: strPtr("songs/1/audio/aa.mp3")},
+		{ID: 2, File: strPtr("songs/2/audio/bb.ogg")}, // Falla al borrar, la purga sigue
+		{ID: 3},
+	}
+	albums := []domain.Purged{
+		{ID: 7, File: strPtr("http://localhost:8080/media/albums/7/cover/ab12/large.jpg")},
+		{ID: 8, File: strPtr("https://upload.wikimedia.org/portada.jpg")},
+	}
+	artists := []domain.Purged{{ID: 4, File: strPtr("http://localhost:8080/media/artists/4/image/cd34/large.png")}}
+
+	report, err := newPurgeTrashService(store, songs, albums, artists).PurgeOlderThan(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeOlderThan: %v", err)
+	}
+	if report.Songs != 3 || report.Albums != 2 || report.Artists != 1 {
+		t.Errorf("reporte = %+v, se esperaban 3 canciones, 2 álbumes y 1 artista", report)
+	}
+
+	want := []string{
+		"albums/7/cover/ab12/large.jpg", "albums/7/cover/ab12/medium.jpg",
+		"albums/7/cover/ab12/original.jpg", "albums/7/cover/ab12/small.jpg",
+		"artists/4/image/cd34/large.png", "artists/4/image/cd34/medium.png",
+		"artists/4/image/cd34/original.png", "artists/4/image/cd34/small.png",
+		"songs/1/audio/aa.mp3",
+	}
+	sort.Strings(store.deleted)
+	if !reflect.DeepEqual(store.deleted, want) {
+		t.Errorf("borrados = %v, se esperaba %v", store.deleted, want)
+	}
+}
+
+func TestPurgeSongDeletesAudio(t *testing.T) {
+	store := &fakeBlobStore{}
+	svc := newPurgeTrashService(store, []domain.Purged{{ID: 5, File: strPtr("songs/5/audio/ee.flac")}}, nil, nil)
+
+	if err := svc.PurgeSong(context.Background(), 5); err != nil {
+		t.Fatalf("PurgeSong: %v", err)
+	}
+	if !reflect.DeepEqual(store.deleted, []string{"songs/5/audio/ee.flac"}) {
+		t.Errorf("borrados = %v, se esperaba el audio de la canción", store.deleted)
+	}
+
+	if err := svc.PurgeSong(context.Background(), 6); !errors.Is(err, domain.ErrSongNotFound) {
+		t.Fatalf("err = %v, se esperaba %v", err, domain.ErrSongNotFound)
+	}
+	if len(store.deleted) != 1 {
+		t.Errorf("borrados = %v, una canción inexistente no debe borrar archivos", store.deleted)
+	}
+}
+
+// La imagen de otro registro (prefijo ajeno) no se borra aunque apunte al blob store
+func TestPurgeAlbumIgnoresForeignImage(t *testing.T) {
+	store := &fakeBlobStore{}
+	svc := newPurgeTrashService(store, nil, []domain.Purged{{ID: 9, File: strPtr("http://localhost:8080/media/albums/10/cover/ff/large.jpg")}}, nil)
+
+	if err := svc.PurgeAlbum(context.Background(), 9); err != nil {
+		t.Fatalf("PurgeAlbum: %v", err)
+	}
+	if len(store.deleted) != 0 {
+		t.Errorf("borrados = %v, no se esperaba borrar la portada de otro álbum", store.deleted)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IsaacEspinoza91/Song-Manager/internal/domain"
+)
+
+// FileSystem guarda los blobs como archivos bajo un directorio raíz, la clave es la ruta relativa
+type FileSystem struct {
+	root string
+}
+
+func NewFileSystem(root string) (*FileSystem, error) {
+	root = filepath.Clean(root)
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando el directorio de archivos %s: %w", root, err)
+	}
+	return &FileSystem{root: root}, nil
+}
+
+// resolve valida la clave y la convierte en ruta del disco. Rechaza rutas absolutas y ".."
+// para que una clave armada con datos del usuario no salga del directorio raíz
+func (s *FileSystem) resolve(key string) (string, error) {
+	clean := path.Clean(key)
+	if key == "" || clean != key || path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", domain.ErrBlobKeyInvalid
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+// Put escribe primero un archivo temporal y luego lo renombra, asi nunca se sirve un archivo a medias
+func (s *FileSystem) Put(ctx context.Context, key string, r io.Reader) error {
+	dst, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio para %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creando archivo temporal para %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // No hace nada si el rename ya ocurrió
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error escribiendo %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error cerrando %s: %w", key, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("error guardando %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FileSystem) Open(ctx context.Context, key string) (io.ReadSeekCloser, *domain.BlobInfo, error) {
+	src, err := s.resolve(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, domain.ErrBlobNotFound
+		}
+		return nil, nil, fmt.Errorf("error abriendo %s: %w", key, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("error leyendo metadatos de %s: %w", key, err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, nil, domain.ErrBlobNotFound
+	}
+	return f, &domain.BlobInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete no falla si el archivo ya no existe
+func (s *FileSystem) Delete(ctx context.Context, key string) error {
+	dst, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error eliminando %s: %w", key, err)
+	}
+	// Limpieza de directorios que quedaron vacíos, sin tocar la raíz
+	for dir := filepath.Dir(dst); dir != s.root && strings.HasPrefix(dir, s.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break // No está vacío
+		}
+	}
+	return nil
+}
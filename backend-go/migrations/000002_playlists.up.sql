@@ -0,0 +1,28 @@
+-- 7. Tabla Playlists
+CREATE TABLE IF NOT EXISTS playlists (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- 8. Tabla Intermedia: Playlist Songs (Entradas ordenadas)
+-- Una misma canción puede repetirse en la playlist, por eso la entrada tiene su propio ID
+CREATE TABLE IF NOT EXISTS playlist_songs (
+    id BIGSERIAL PRIMARY KEY,
+    playlist_id BIGINT NOT NULL,
+    song_id BIGINT NOT NULL,
+    position INT NOT NULL CHECK (position > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_playlist FOREIGN KEY (playlist_id) REFERENCES playlists(id) ON DELETE CASCADE,
+    CONSTRAINT fk_song FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE,
+
+    -- Diferida: permite desplazar posiciones dentro de la transacción sin chocar consigo misma
+    CONSTRAINT unique_position_per_playlist UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX idx_playlists_deleted_at ON playlists(deleted_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_playlist_songs_song_id ON playlist_songs(song_id);
@@ -0,0 +1,17 @@
+-- Soporte multi-disco (box sets, ediciones deluxe)
+-- Los tracks existentes quedan en el disco 1
+ALTER TABLE tracks ADD COLUMN IF NOT EXISTS disc_number INT NOT NULL DEFAULT 1 CHECK (disc_number > 0);
+
+-- La numeración de pistas ahora es única por disco y no por álbum
+ALTER TABLE tracks DROP CONSTRAINT IF EXISTS unique_track_number_per_album;
+ALTER TABLE tracks ADD CONSTRAINT unique_track_number_per_disc UNIQUE (album_id, disc_number, track_number);
+
+-- 9. Tabla Album Discs (títulos opcionales por disco, ej. "Disc 2: Live at Viña")
+CREATE TABLE IF NOT EXISTS album_discs (
+    album_id BIGINT NOT NULL,
+    disc_number INT NOT NULL CHECK (disc_number > 0),
+    title VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY (album_id, disc_number),
+    CONSTRAINT fk_album FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- 10. Tabla Artist Redirects
+-- Al fusionar artistas duplicados el ID del artista absorbido apunta al sobreviviente.
+-- old_id no tiene FK a propósito: el redirect sigue valiendo aunque el artista absorbido se purgue
+CREATE TABLE IF NOT EXISTS artist_redirects (
+    old_id BIGINT PRIMARY KEY,
+    new_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_new_artist FOREIGN KEY (new_id) REFERENCES artists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_artist_redirects_new_id ON artist_redirects(new_id);
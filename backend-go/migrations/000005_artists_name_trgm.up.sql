@@ -0,0 +1,2 @@
+-- Indice trigram para nombres de artistas (búsqueda difusa y detección de duplicados)
+CREATE INDEX IF NOT EXISTS artists_name_trgm_idx ON artists USING GIN (name gin_trgm_ops);
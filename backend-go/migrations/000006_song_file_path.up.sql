@@ -0,0 +1,2 @@
+-- Ubicación local conocida del archivo de audio (la registra el scanner de biblioteca)
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS file_path TEXT;
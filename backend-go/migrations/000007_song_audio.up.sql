@@ -0,0 +1,4 @@
+-- Audio adjunto a la canción, guardado en el blob store (audio_key es la clave del archivo)
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS audio_key TEXT;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS audio_content_type VARCHAR(100);
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS audio_size BIGINT;
@@ -0,0 +1,3 @@
+-- Paginación por cursor: el listado de álbumes se ordena por (release_date DESC, id).
+-- Artistas y canciones se ordenan por id y ya usan la clave primaria
+CREATE INDEX IF NOT EXISTS idx_albums_release_date_id ON albums (release_date DESC, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Índice de trigramas para buscar álbumes por título (búsqueda unificada GET /search)
+CREATE INDEX IF NOT EXISTS albums_title_trgm_idx ON albums USING GIN (title gin_trgm_ops);
@@ -0,0 +1,19 @@
+-- Búsqueda difusa insensible a mayúsculas y tildes ("cancion" encuentra "Canción", "pena" encuentra "Peña")
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- normalize_text pliega el texto igual que validation.NormalizeText en Go: minúsculas y sin tildes (ñ -> n).
+-- unaccent() a secas es STABLE porque depende del search_path; con el diccionario explícito
+-- puede declararse IMMUTABLE y usarse en índices
+CREATE OR REPLACE FUNCTION normalize_text(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, $1)) $$;
+
+-- Los índices de trigramas pasan a indexar el texto normalizado, que es el que comparan los filtros
+DROP INDEX IF EXISTS songs_title_trgm_idx;
+DROP INDEX IF EXISTS artists_name_trgm_idx;
+DROP INDEX IF EXISTS albums_title_trgm_idx;
+
+CREATE INDEX IF NOT EXISTS songs_title_norm_trgm_idx ON songs USING GIN (normalize_text(title) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS artists_name_norm_trgm_idx ON artists USING GIN (normalize_text(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS albums_title_norm_trgm_idx ON albums USING GIN (normalize_text(title) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS playlists_name_norm_trgm_idx ON playlists USING GIN (normalize_text(name) gin_trgm_ops);
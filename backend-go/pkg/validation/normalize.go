@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Letras que unaccent reemplaza pero que no se descomponen en letra + tilde
+var foldReplacer = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ł", "l")
+
+// NormalizeText pliega el texto para búsquedas difusas: sin espacios en los extremos, en minúsculas
+// y sin tildes ni diéresis ("Peña" -> "pena", "Canción" -> "cancion").
+// Debe plegar igual que la función normalize_text de la base de datos (lower + unaccent),
+// que es la que se aplica a las columnas comparadas
+func NormalizeText(s string) string {
+	s = strings.ToLower(SanitizeString(s))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range norm.NFD.String(s) {
+		// NFD separa la letra base de sus marcas (ñ = n + ~), se descartan las marcas
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return foldReplacer.Replace(b.String())
+}
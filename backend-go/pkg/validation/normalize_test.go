@@ -0,0 +1,43 @@
+package validation
+
+import "testing"
+
+// Los valores esperados son los de normalize_text(x) en la base (lower + unaccent),
+// salvo el recorte de espacios, que solo aplica NormalizeText a lo que escribe el cliente
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"Hola", "hola"},
+		{"  Peña  ", "pena"},
+		{"Canción", "cancion"},
+		{"Beyoncé", "beyonce"},
+		{"naïve", "naive"},
+		{"MÜNCHEN", "munchen"},
+		{"São Paulo", "sao paulo"},
+		{"Français", "francais"},
+		{"Cafe\u0301", "cafe"}, // Tilde combinante, ya descompuesta
+		{"Straße", "strasse"},
+		{"Ærø", "aero"},
+		{"Œuvre", "oeuvre"},
+		{"Łódź", "lodz"},
+		{"Đorđe", "dorde"},
+		{"AC/DC", "ac/dc"},
+		{"Guns N' Roses", "guns n' roses"},
+		{"2Pac & Dr. Dre", "2pac & dr. dre"},
+		{"日本語", "日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := NormalizeText(tt.in)
+			if got != tt.want {
+				t.Errorf("NormalizeText(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+			}
+			if again := NormalizeText(got); again != got {
+				t.Errorf("NormalizeText no es idempotente: %q -> %q", got, again)
+			}
+		})
+	}
+}
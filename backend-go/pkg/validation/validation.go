@@ -20,3 +20,18 @@ func SanitizeOpcionalString(s *string) *string {
 
 	return &clean
 }
+
+// Trim de cada valor de una lista, descartando vacíos y repetidos
+func SanitizeStringList(values []string) []string {
+	var clean []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		clean = append(clean, v)
+	}
+	return clean
+}